@@ -11,13 +11,18 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hrygo/council/internal/api/authz"
 	"github.com/hrygo/council/internal/api/handler"
 	"github.com/hrygo/council/internal/api/ws"
+	"github.com/hrygo/council/internal/core/auth"
 	"github.com/hrygo/council/internal/core/memory"
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
 	"github.com/hrygo/council/internal/infrastructure/cache"
 	"github.com/hrygo/council/internal/infrastructure/db"
 	"github.com/hrygo/council/internal/infrastructure/llm"
 	"github.com/hrygo/council/internal/infrastructure/persistence"
+	"github.com/hrygo/council/internal/infrastructure/search"
 	"github.com/hrygo/council/internal/pkg/config"
 	"github.com/hrygo/council/internal/resources"
 	"github.com/joho/godotenv"
@@ -58,6 +63,11 @@ func main() {
 
 	// LLM Registry
 	registry := llm.NewRegistry(cfg)
+	if windows, err := persistence.NewLLMModelRepository(pool).ContextWindows(context.Background()); err != nil {
+		log.Printf("Warning: Using default context windows: %v", err)
+	} else {
+		registry.SetContextWindows(windows)
+	}
 
 	// Core Services
 	// Map config.EmbeddingConfig to llm.EmbeddingConfig
@@ -71,10 +81,32 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize embedder: %v", err)
 	}
+	// Cache embeddings by content, so re-ingested chunks and repeated queries are not paid twice
+	embedder = llm.NewCachedEmbedder(embedder, embedCfg.Type, cache.GetClient(), llm.DefaultEmbeddingCacheSize)
 	memoryService := memory.NewService(embedder, pool, cache.GetClient())
 
+	// Tools shared by tool nodes and agents
+	var searchClient search.SearchClient
+	switch cfg.SearchProvider {
+	case "":
+	case "corpus":
+		searchClient = search.NewCorpusClient(pool)
+	default:
+		if searchClient, err = search.NewSearchClient(cfg.SearchProvider); err != nil {
+			log.Printf("Warning: Search disabled: %v", err)
+		}
+	}
+	toolRegistry := tools.NewDefaultRegistry()
+	if searchClient != nil {
+		toolRegistry.Register(&tools.WebSearchTool{Client: searchClient})
+	}
+	if cfg.CodeExecution {
+		toolRegistry.Register(&tools.RunCodeTool{})
+	}
+
 	// WebSocket Hub
 	hub := ws.NewHub()
+	hub.Spill = ws.NewRedisEventStore(cache.GetClient())
 	go hub.Run()
 
 	// Repositories
@@ -84,15 +116,40 @@ func main() {
 	templateRepo := persistence.NewTemplateRepository(pool)
 	sessionRepo := persistence.NewSessionRepository(pool)
 	fileRepo := persistence.NewSessionFileRepository(pool)
+	authRepo := persistence.NewAuthRepository(pool)
+	tenantRepo := persistence.NewTenantRepository(pool)
+	costLedger := persistence.NewCostLedgerRepository(pool)
+
+	// Authentication
+	if cfg.AuthEnabled {
+		if cfg.AuthSecret == "" {
+			log.Fatal("AUTH_ENABLED requires AUTH_SECRET")
+		}
+		if err := auth.EnsureAdmin(tenant.WithID(context.Background(), tenant.DefaultID), authRepo, cfg.AdminUsername, cfg.AdminPassword); err != nil {
+			log.Printf("Warning: Failed to bootstrap admin user: %v", err)
+		}
+	} else {
+		log.Println("Warning: Authentication disabled, every request runs as the local administrator")
+	}
+	tokens := auth.NewTokenIssuer(cfg.AuthSecret)
+	authn := &authz.Authenticator{Repo: authRepo, Tokens: tokens, Disabled: !cfg.AuthEnabled}
+	policy := &authz.Policy{Repo: authRepo}
 
 	// Handlers
 	agentHandler := handler.NewAgentHandler(agentRepo)
 	groupHandler := handler.NewGroupHandler(groupRepo)
+	groupHandler.Members = authRepo
 	templateHandler := handler.NewTemplateHandler(templateRepo)
 	memoryHandler := handler.NewMemoryHandler(memoryService)
+	memoryHandler.Policy = policy
 	knowledgeHandler := handler.NewKnowledgeHandler(memoryService, sessionRepo)
 	workflowMgmtHandler := handler.NewWorkflowMgmtHandler(workflowRepo, registry)
+	workflowMgmtHandler.AgentRepo = agentRepo
 	llmHandler := handler.NewLLMHandler(cfg, pool)
+	authHandler := handler.NewAuthHandler(authRepo, tokens)
+	tenantHandler := handler.NewTenantHandler(tenantRepo, authRepo)
+	costHandler := handler.NewCostHandler(costLedger)
+	costHandler.Policy = policy
 
 	// WorkflowHandler dependency injection
 	workflowHandler := handler.NewWorkflowHandler(
@@ -104,57 +161,106 @@ func main() {
 		fileRepo,
 		workflowRepo,
 	)
+	workflowHandler.Tools = toolRegistry
+	workflowHandler.SearchClient = searchClient
+	workflowHandler.Policy = policy
+	workflowHandler.Ledger = costLedger
+	hub.Commands = workflowHandler
+	hub.Authorizer = workflowHandler
+
+	// Resume sessions interrupted by the previous shutdown, tenant by tenant
+	if tenants, err := tenantRepo.List(context.Background()); err != nil {
+		log.Printf("Warning: Failed to list tenants for recovery: %v", err)
+	} else {
+		for _, t := range tenants {
+			if n, err := workflowHandler.RecoverSessions(tenant.WithID(context.Background(), t.ID)); err != nil {
+				log.Printf("Warning: Failed to recover sessions of tenant %s: %v", t.Name, err)
+			} else if n > 0 {
+				log.Printf("Recovered %d interrupted session(s) of tenant %s", n, t.Name)
+			}
+		}
+	}
 
 	// Routes
-	r.GET("/ws", func(c *gin.Context) {
+	// Every route acts for the caller's tenant. Catalogs (agents, workflows,
+	// templates) are shared within the tenant: members of any group read them,
+	// editors change them. Group and session routes check the role in the
+	// group that owns them. Only editors create groups: the creator owns the
+	// new group, which would otherwise let a viewer become an editor.
+	editAny := policy.RequireAny(auth.RoleEditor)
+	groupID := authz.GroupParam("id")
+	sessionGroup := workflowHandler.SessionGroup
+
+	r.GET("/ws", authn.Middleware(), func(c *gin.Context) {
 		ws.ServeWs(hub, c)
 	})
+	r.POST("/api/v1/auth/login", authHandler.Login)
 
-	api := r.Group("/api/v1")
+	api := r.Group("/api/v1", authn.Middleware())
 	{
+		// Auth
+		api.GET("/auth/me", authHandler.Me)
+		api.POST("/auth/keys", authHandler.CreateAPIKey)
+		api.POST("/users", authz.RequireAdmin(), authHandler.CreateUser)
+
+		// Tenants (administrators of the default tenant only)
+		api.GET("/tenants", authz.RequireOperator(), tenantHandler.List)
+		api.POST("/tenants", authz.RequireOperator(), tenantHandler.Create)
+
 		// Agents
-		api.POST("/agents", agentHandler.Create)
+		api.POST("/agents", editAny, agentHandler.Create)
 		api.GET("/agents", agentHandler.List)
 		api.GET("/agents/:id", agentHandler.Get)
-		api.PUT("/agents/:id", agentHandler.Update)
-		api.DELETE("/agents/:id", agentHandler.Delete)
+		api.PUT("/agents/:id", editAny, agentHandler.Update)
+		api.DELETE("/agents/:id", editAny, agentHandler.Delete)
 
 		// Groups
-		api.POST("/groups", groupHandler.Create)
+		api.POST("/groups", editAny, groupHandler.Create)
 		api.GET("/groups", groupHandler.List)
-		api.GET("/groups/:id", groupHandler.Get)
-		api.PUT("/groups/:id", groupHandler.Update)
-		api.DELETE("/groups/:id", groupHandler.Delete)
+		api.GET("/groups/:id", policy.Require(auth.RoleViewer, groupID), groupHandler.Get)
+		api.PUT("/groups/:id", policy.Require(auth.RoleEditor, groupID), groupHandler.Update)
+		api.DELETE("/groups/:id", policy.Require(auth.RoleOwner, groupID), groupHandler.Delete)
+		api.GET("/groups/:id/members", policy.Require(auth.RoleViewer, groupID), groupHandler.ListMembers)
+		api.PUT("/groups/:id/members", policy.Require(auth.RoleOwner, groupID), groupHandler.SetMember)
+		api.DELETE("/groups/:id/members/:user_id", policy.Require(auth.RoleOwner, groupID), groupHandler.RemoveMember)
 
 		// Workflows Management
 		api.GET("/workflows", workflowMgmtHandler.List)
 		api.GET("/workflows/:id", workflowMgmtHandler.Get)
-		api.POST("/workflows", workflowMgmtHandler.Create)
-		api.PUT("/workflows/:id", workflowMgmtHandler.Update)
-		api.POST("/workflows/generate", workflowMgmtHandler.Generate)
+		api.POST("/workflows", editAny, workflowMgmtHandler.Create)
+		api.PUT("/workflows/:id", editAny, workflowMgmtHandler.Update)
+		api.POST("/workflows/generate", editAny, workflowMgmtHandler.Generate)
 		api.POST("/workflows/estimate", workflowMgmtHandler.EstimateCost)
 
-		// Workflows Execution
+		// Workflows Execution (Execute checks the editor role in the input group)
 		api.POST("/workflows/execute", workflowHandler.Execute)
-		api.GET("/sessions/:id", workflowHandler.GetSession)
-		api.POST("/sessions/:id/control", workflowHandler.Control)
+		api.GET("/sessions/:id", policy.Require(auth.RoleViewer, sessionGroup), workflowHandler.GetSession)
+		api.POST("/sessions/:id/control", policy.Require(auth.RoleEditor, sessionGroup), workflowHandler.Control)
 
-		api.POST("/sessions/:id/signal", workflowHandler.Signal)
-		api.POST("/sessions/:id/review", workflowHandler.Review)
-		api.GET("/sessions/:id/files", workflowHandler.ListFiles)
-		api.GET("/sessions/:id/files/history", workflowHandler.GetFileHistory)
+		api.POST("/sessions/:id/signal", policy.Require(auth.RoleEditor, sessionGroup), workflowHandler.Signal)
+		api.POST("/sessions/:id/review", policy.Require(auth.RoleReviewer, sessionGroup), workflowHandler.Review)
+		api.GET("/sessions/:id/files", policy.Require(auth.RoleViewer, sessionGroup), workflowHandler.ListFiles)
+		api.GET("/sessions/:id/files/history", policy.Require(auth.RoleViewer, sessionGroup), workflowHandler.GetFileHistory)
+		api.GET("/sessions/:id/trace", policy.Require(auth.RoleViewer, sessionGroup), workflowHandler.GetTrace)
+		api.GET("/sessions/:id/events", policy.Require(auth.RoleViewer, sessionGroup), workflowHandler.StreamEvents)
+		api.GET("/sessions/:id/audit", policy.Require(auth.RoleViewer, sessionGroup), authHandler.ListSessionAudit)
 
 		// Templates
 		api.GET("/templates", templateHandler.List)
-		api.POST("/templates", templateHandler.Create)
-		api.DELETE("/templates/:id", templateHandler.Delete)
+		api.POST("/templates", editAny, templateHandler.Create)
+		api.DELETE("/templates/:id", editAny, templateHandler.Delete)
 
-		// Memory
+		// Memory (handlers check the role in the request's group)
 		api.POST("/memory/ingest", memoryHandler.Ingest)
 		api.POST("/memory/query", memoryHandler.Query)
 
 		// Knowledge (Session-specific)
-		api.GET("/sessions/:id/knowledge", knowledgeHandler.GetSessionKnowledge)
+		api.GET("/sessions/:id/knowledge", policy.Require(auth.RoleViewer, sessionGroup), knowledgeHandler.GetSessionKnowledge)
+
+		// Cost reports (the handler checks the role in the requested group;
+		// tenant-wide reports require an administrator)
+		api.GET("/costs/report", costHandler.Report)
+		api.GET("/costs/ledger", costHandler.ListLedger)
 
 		// LLM Options
 		api.GET("/llm/providers", llmHandler.GetProviderOptions)
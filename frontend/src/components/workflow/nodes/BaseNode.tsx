@@ -12,6 +12,7 @@ interface BaseNodeProps {
     headerColor?: string; // Tailwind class like 'bg-blue-500'
     children?: ReactNode;
     handles?: ('top' | 'associative' | 'bottom')[]; // Which handles to show
+    notice?: string; // Transient status line, e.g. waiting for a rate limit
 }
 
 export const BaseNode: FC<BaseNodeProps> = ({
@@ -21,7 +22,8 @@ export const BaseNode: FC<BaseNodeProps> = ({
     status = 'pending',
     headerColor = 'bg-gray-100',
     children,
-    handles = ['top', 'bottom']
+    handles = ['top', 'bottom'],
+    notice
 }) => {
     // Status visual mapping
     const statusStyles = {
@@ -92,6 +94,12 @@ export const BaseNode: FC<BaseNodeProps> = ({
                 {children}
             </div>
 
+            {notice && (
+                <div className="px-3 py-1 text-[10px] text-amber-700 dark:text-amber-300 bg-amber-50 dark:bg-amber-900/30 border-t border-amber-100 dark:border-amber-800 rounded-b-lg">
+                    {notice}
+                </div>
+            )}
+
             {handles.includes('bottom') && (
                 <Handle type="source" position={Position.Bottom} className="!w-3 !h-3 !bg-gray-300 dark:!bg-gray-500" />
             )}
@@ -19,12 +19,41 @@ import type {
     BaseNodeData
 } from '../../../types/workflow';
 import { type NodeStatus } from '../../../types/session';
+import type { RuntimeNode } from '../../../types/workflow-run';
 
 // Helper to cast data safely
 function getData<T>(data: unknown): T {
     return (data || {}) as T;
 }
 
+const throttleReasons: Record<string, string> = {
+    concurrency: 'too many calls in flight',
+    requests_per_minute: 'request limit reached',
+    tokens_per_minute: 'token limit reached',
+    retry_after: 'provider asked to back off',
+    queued: 'queued behind earlier calls',
+};
+
+// Explains why an LLM call of the node is waiting, if it is
+function throttleNotice(throttle: RuntimeNode['throttle']): string | undefined {
+    if (!throttle) return undefined;
+    const reason = throttleReasons[throttle.reason] || throttle.reason;
+    const wait = throttle.waitMs > 0 ? ` (~${Math.ceil(throttle.waitMs / 1000)}s)` : '';
+    return `⏳ ${throttle.provider}: ${reason}${wait}`;
+}
+
+// Explains which prompt sections were cut to fit the model's context window
+function truncationNotice(truncation: RuntimeNode['truncation']): string | undefined {
+    if (!truncation) return undefined;
+    return `✂️ Trimmed ${truncation.sections.join(', ')} (-${truncation.droppedTokens} tokens) to fit the context window`;
+}
+
+// The throttle wait matters while it lasts, so it wins over a truncation
+function nodeNotice(data: Record<string, unknown>): string | undefined {
+    return throttleNotice(data.throttle as RuntimeNode['throttle']) ||
+        truncationNotice(data.truncation as RuntimeNode['truncation']);
+}
+
 export const AgentNode = (props: NodeProps) => {
     const data = getData<AgentNodeData>(props.data);
     return (
@@ -34,6 +63,7 @@ export const AgentNode = (props: NodeProps) => {
             selected={props.selected}
             status={data.status as NodeStatus}
             headerColor="bg-blue-50 dark:bg-blue-900/30"
+            notice={nodeNotice(data as Record<string, unknown>)}
         >
             <div className="space-y-1">
                 <div className="font-medium text-gray-900 dark:text-gray-200">Agent Task</div>
@@ -89,6 +119,7 @@ export const FactCheckNode = (props: NodeProps) => {
             selected={props.selected}
             status={data.status as NodeStatus}
             headerColor="bg-cyan-50 dark:bg-cyan-900/30"
+            notice={nodeNotice(data as Record<string, unknown>)}
         >
             <div>
                 Source: <span className="font-medium text-gray-900 dark:text-gray-200">{data.search_sources?.length || 'Auto'}</span>
@@ -144,6 +175,7 @@ export const EndNode = (props: NodeProps) => {
             status={data.status as NodeStatus}
             headerColor="bg-red-50 dark:bg-red-900/30"
             handles={['top']}
+            notice={nodeNotice(data as Record<string, unknown>)}
         >
             <div className="text-center text-red-700 dark:text-red-400 font-medium">Completion</div>
         </BaseNode>
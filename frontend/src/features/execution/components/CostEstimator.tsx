@@ -10,7 +10,9 @@ interface CostEstimatorProps {
 interface CostEstimate {
     total_cost_usd: number;
     total_tokens: number;
+    cost_range?: { min: number; max: number };
     agent_breakdown: Record<string, number>;
+    warnings?: string[];
 }
 
 export const CostEstimator: React.FC<CostEstimatorProps> = ({ nodes, edges }) => {
@@ -114,6 +116,18 @@ export const CostEstimator: React.FC<CostEstimatorProps> = ({ nodes, edges }) =>
                         </div>
                     </div>
 
+                    {estimate.cost_range && (
+                        <div className="text-xs text-gray-500">
+                            Range: ${estimate.cost_range.min.toFixed(4)} – ${estimate.cost_range.max.toFixed(4)}
+                        </div>
+                    )}
+
+                    {estimate.warnings && estimate.warnings.length > 0 && (
+                        <ul className="text-xs text-amber-600 space-y-1">
+                            {estimate.warnings.map(w => <li key={w}>{w}</li>)}
+                        </ul>
+                    )}
+
                     {Object.keys(estimate.agent_breakdown).length > 0 && (
                         <div>
                             <div className="text-xs font-medium text-gray-500 mb-1 flex items-center gap-1">
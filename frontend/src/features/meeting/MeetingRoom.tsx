@@ -206,6 +206,7 @@ export const MeetingRoom: FC = () => {
     // Check for active session
     const wsStatus = useConnectStore(state => state.status);
     const wsConnect = useConnectStore(state => state.connect);
+    const wsSubscribe = useConnectStore(state => state.subscribe);
     const graphDefinition = useWorkflowRunStore(state => state.graphDefinition);
 
     // Auto-connect WebSocket if session exists but WS is disconnected
@@ -214,9 +215,10 @@ export const MeetingRoom: FC = () => {
             const wsHost = window.location.host;
             const wsProtocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
             const wsUrl = `${wsProtocol}//${wsHost}/ws`;  // Backend WebSocket route is /ws
+            wsSubscribe(currentSession.session_uuid);
             wsConnect(wsUrl);
         }
-    }, [currentSession, wsStatus, wsConnect]);
+    }, [currentSession, wsStatus, wsConnect, wsSubscribe]);
 
     // Fullscreen Mode
     if (maximizedPanel) {
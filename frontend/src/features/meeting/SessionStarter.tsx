@@ -102,6 +102,8 @@ export const SessionStarter: FC<SessionStarterProps> = ({ onStarted }) => {
             }
 
             const data = await res.json(); // { session_id, status }
+            // Replay from the start: events emitted before the subscription arrive too
+            useConnectStore.getState().subscribe(data.session_uuid, 0);
 
             // 5. Initialize Store
             useWorkflowRunStore.getState().setGraphFromTemplate(selectedTemplate);
@@ -204,4 +204,50 @@ describe('useWebSocketRouter', () => {
         });
         expect(useWorkflowRunStore.getState().nodes[0].data.status).toBe('failed');
     });
+
+    it('should show and clear llm:throttled waits', () => {
+        renderHook(() => useWebSocketRouter());
+        act(() => {
+            useConnectStore.setState({
+                _lastMessage: {
+                    event: 'llm:throttled',
+                    node_id: 'node-1',
+                    data: { node_id: 'node-1', provider: 'deepseek', model: 'deepseek-chat', reason: 'retry_after', wait_ms: 2000 }
+                }
+            });
+        });
+        expect(useWorkflowRunStore.getState().nodes[0].data.throttle).toEqual({ provider: 'deepseek', reason: 'retry_after', waitMs: 2000 });
+
+        act(() => {
+            useConnectStore.setState({
+                _lastMessage: { event: 'token_stream', data: { node_id: 'node-1', agent_id: 'agent-1', chunk: 'Hi' } }
+            });
+        });
+        expect(useWorkflowRunStore.getState().nodes[0].data.throttle).toBeUndefined();
+    });
+
+    it('should record context:truncated sections on the node', () => {
+        renderHook(() => useWebSocketRouter());
+        act(() => {
+            useConnectStore.setState({
+                _lastMessage: {
+                    event: 'context:truncated',
+                    node_id: 'node-1',
+                    data: {
+                        node_id: 'node-1',
+                        model: 'gpt-4',
+                        context_window: 8192,
+                        sections: [
+                            { key: 'document_content', label: '参考文档', original_tokens: 9000, kept_tokens: 3000 },
+                            { key: 'aggregated_outputs', label: '', original_tokens: 2000, kept_tokens: 1500, dropped_entries: 1 },
+                        ]
+                    }
+                }
+            });
+        });
+        expect(useWorkflowRunStore.getState().nodes[0].data.truncation).toEqual({
+            sections: ['参考文档', 'aggregated_outputs'],
+            droppedTokens: 6500,
+        });
+    });
 });
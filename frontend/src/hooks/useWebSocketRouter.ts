@@ -4,9 +4,11 @@ import { useSessionStore } from '../stores/useSessionStore';
 import { useWorkflowRunStore } from '../stores/useWorkflowRunStore';
 import type {
     WSMessage,
+    ContextTruncatedData,
     TokenStreamData,
     NodeStateChangeData,
     ParallelStartData,
+    ThrottledData,
     TokenUsageData
 } from '../types/websocket';
 
@@ -18,6 +20,13 @@ export const useWebSocketRouter = () => {
         switch (msg.event) {
             case 'token_stream': {
                 const data = msg.data as TokenStreamData;
+                if (workflowStore.nodes.find(n => n.id === data.node_id)?.data.throttle) {
+                    workflowStore.setNodeThrottle(data.node_id, null);
+                }
+                if (data.restart) {
+                    sessionStore.restartMessage(data.node_id, data.agent_id);
+                    break;
+                }
                 sessionStore.appendMessage({
                     node_id: data.node_id,
                     agent_uuid: data.agent_id,
@@ -82,7 +91,27 @@ export const useWebSocketRouter = () => {
                 break;
             }
 
+            case 'llm:throttled': {
+                const data = msg.data as ThrottledData;
+                workflowStore.setNodeThrottle(msg.node_id || data.node_id, {
+                    provider: data.provider,
+                    reason: data.reason,
+                    waitMs: data.wait_ms,
+                });
+                break;
+            }
+
+            case 'context:truncated': {
+                const data = msg.data as ContextTruncatedData;
+                workflowStore.setNodeTruncation(msg.node_id || data.node_id, {
+                    sections: data.sections.map(s => s.label || s.key),
+                    droppedTokens: data.sections.reduce((sum, s) => sum + s.original_tokens - s.kept_tokens, 0),
+                });
+                break;
+            }
+
             case 'execution:paused':
+            case 'execution:budget_exceeded':
                 workflowStore.setExecutionStatus('paused');
                 workflowStore.stopTimer();
                 sessionStore.updateSessionStatus('paused');
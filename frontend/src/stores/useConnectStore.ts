@@ -13,6 +13,7 @@ interface ConnectState {
     connect: (url: string) => void;
     disconnect: () => void;
     send: <T>(command: WSCommand<T>) => void;
+    subscribe: (sessionId: string, lastSeq?: number) => void;
 
     // Internal
     _onMessage: (msg: WSMessage) => void;
@@ -29,6 +30,11 @@ export const useConnectStore = create<ConnectState>()(
         let heartbeatTimer: ReturnType<typeof setInterval> | null = null;
         let reconnectTimer: ReturnType<typeof setTimeout> | null = null;
         let currentUrl: string | null = null;
+        // Sessions whose events this client receives, with the last seq seen;
+        // restored after reconnects so that missed events are replayed
+        const subscriptions = new Map<string, number | undefined>();
+        const subscribeCommand = (sessionId: string, lastSeq?: number) =>
+            JSON.stringify({ cmd: 'subscribe', data: { session_uuid: sessionId, last_seq: lastSeq } });
 
         return {
             socket: null,
@@ -48,6 +54,7 @@ export const useConnectStore = create<ConnectState>()(
 
                 ws.onopen = () => {
                     set({ status: 'connected', reconnectAttempts: 0, lastError: null });
+                    subscriptions.forEach((lastSeq, sessionId) => ws.send(subscribeCommand(sessionId, lastSeq)));
                     get()._startHeartbeat();
                 };
 
@@ -67,6 +74,10 @@ export const useConnectStore = create<ConnectState>()(
                 ws.onmessage = (event) => {
                     try {
                         const msg = JSON.parse(event.data) as WSMessage;
+                        const sessionId = (msg.data as { session_uuid?: string } | undefined)?.session_uuid;
+                        if (msg.seq && sessionId && subscriptions.has(sessionId)) {
+                            subscriptions.set(sessionId, msg.seq);
+                        }
                         get()._onMessage(msg);
                     } catch (e) {
                         console.error('Failed to parse WS message:', e);
@@ -94,6 +105,15 @@ export const useConnectStore = create<ConnectState>()(
                 }
             },
 
+            subscribe: (sessionId, lastSeq) => {
+                lastSeq = lastSeq ?? subscriptions.get(sessionId);
+                subscriptions.set(sessionId, lastSeq);
+                const { socket } = get();
+                if (socket && socket.readyState === WebSocket.OPEN) {
+                    socket.send(subscribeCommand(sessionId, lastSeq));
+                }
+            },
+
             _onMessage: (msg) => {
                 set({ _lastMessage: msg });
             },
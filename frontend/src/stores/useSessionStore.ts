@@ -66,6 +66,11 @@ interface SessionState {
      */
     appendMessage: (message: Omit<Message, 'message_uuid' | 'timestamp'> & { isChunk?: boolean }) => void;
 
+    /**
+     * 清空流式消息内容 (备用供应商重新生成时)
+     */
+    restartMessage: (node_id: string, agent_uuid?: string) => void;
+
     /**
      * 标记消息流式完成
      */
@@ -259,6 +264,18 @@ export const useSessionStore = create<SessionState>()(
             });
         },
 
+        restartMessage: (node_id, agent_uuid) => {
+            set(state => {
+                const group = state.messageGroups.find(g => g.node_id === node_id);
+                const existingMsg = group?.messages.findLast(
+                    (m: Message) => m.agent_uuid === agent_uuid && m.isStreaming
+                );
+                if (existingMsg) {
+                    existingMsg.content = '';
+                }
+            });
+        },
+
         finalizeMessage: (node_id, agent_uuid) => {
             set(state => {
                 const group = state.messageGroups.find(g => g.node_id === node_id);
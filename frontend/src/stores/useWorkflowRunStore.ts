@@ -70,6 +70,8 @@ interface WorkflowRunState {
     addActiveNode: (node_id: string) => void;
     removeActiveNode: (node_id: string) => void;
     updateNodeTokenUsage: (node_id: string, usage: NonNullable<RuntimeNode['tokenUsage']>) => void;
+    setNodeThrottle: (node_id: string, throttle: RuntimeNode['throttle'] | null) => void;
+    setNodeTruncation: (node_id: string, truncation: RuntimeNode['truncation']) => void;
     setExecutionStatus: (status: WorkflowRunState['executionStatus']) => void;
     sendControl: (session_uuid: string, action: ControlAction) => Promise<void>;
     setHumanReview: (request: HumanReviewRequest | null) => void;
@@ -158,6 +160,8 @@ export const useWorkflowRunStore = create<WorkflowRunState>()(
                     if (node) {
                         node.data.status = status;
                         if (error) node.data.error = error;
+                        delete node.data.throttle;
+                        if (status === 'running') delete node.data.truncation;
 
                         if (status === 'completed') state.stats.completedNodes++;
                         if (status === 'failed') state.stats.failedNodes++;
@@ -194,6 +198,27 @@ export const useWorkflowRunStore = create<WorkflowRunState>()(
                 });
             },
 
+            setNodeThrottle: (node_id, throttle) => {
+                set((state) => {
+                    const node = state.nodes.find(n => n.id === node_id);
+                    if (!node) return;
+                    if (throttle) {
+                        node.data.throttle = throttle;
+                    } else {
+                        delete node.data.throttle;
+                    }
+                });
+            },
+
+            setNodeTruncation: (node_id, truncation) => {
+                set((state) => {
+                    const node = state.nodes.find(n => n.id === node_id);
+                    if (node) {
+                        node.data.truncation = truncation;
+                    }
+                });
+            },
+
             setExecutionStatus: (status) => {
                 set((state) => {
                     state.executionStatus = status;
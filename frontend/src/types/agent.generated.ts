@@ -26,6 +26,19 @@ export interface ModelConfig {
   temperature: number /* float64 */;
   top_p: number /* float64 */;
   max_tokens: number /* int */;
+  /**
+   * Fallbacks are tried in order when the provider fails; empty uses the
+   * global LLM_FALLBACKS chain.
+   */
+  fallbacks?: ModelFallback[];
+}
+/**
+ * ModelFallback is a provider of a fallback chain and the model to ask it
+ * for; an empty model keeps the agent's model.
+ */
+export interface ModelFallback {
+  provider: string;
+  model?: string;
 }
 /**
  * Capabilities defines what this agent can do.
@@ -4,17 +4,24 @@ export type WSEventType =
     | 'node:parallel_start' // 并行节点开始
     | 'token_usage'         // Token 使用统计
     | 'execution:paused'    // 执行已暂停
+    | 'execution:budget_exceeded' // 超出预算, 已暂停
+    | 'llm:throttled'       // LLM 调用因限流排队等待
+    | 'context:truncated'   // 提示词超出模型上下文窗口, 部分内容被截断
     | 'execution:completed' // 执行完成
     | 'error'               // 错误
     | 'human_interaction_required' // 人工介入请求
     | 'node_resumed'        // 节点恢复执行
-    | 'tool_execution';     // 工具执行
+    | 'tool_execution'      // 工具执行
+    | 'stream:gap'          // 重连时部分事件已不可回放
+    | 'command:result'      // 命令执行结果 (按 id 关联)
+    | 'command:error';      // 命令执行失败 (按 id 关联)
 
 export interface WSMessage<T = unknown> {
     event: WSEventType;
     data: T;
     timestamp?: string;
     node_id?: string;
+    seq?: number;           // 会话内递增序号, 用于断线重连续传
 }
 
 // 具体事件数据类型
@@ -23,6 +30,7 @@ export interface TokenStreamData {
     agent_id: string;
     chunk: string;
     is_thinking?: boolean;
+    restart?: boolean;      // 供应商中途失败, 备用供应商重新生成: 丢弃已收到的内容
 }
 
 export interface NodeStateChangeData {
@@ -38,6 +46,29 @@ export interface TokenUsageData {
     estimated_cost_usd: number;
 }
 
+export interface ThrottledData {
+    node_id: string;
+    provider: string;
+    model: string;
+    reason: 'concurrency' | 'requests_per_minute' | 'tokens_per_minute' | 'retry_after' | 'queued';
+    wait_ms: number;        // 预计等待时长, 0 表示需等待进行中的调用完成
+}
+
+export interface TruncatedSection {
+    key: string;
+    label: string;
+    original_tokens: number;
+    kept_tokens: number;
+    dropped_entries?: number; // 丢弃的较早条目数 (如 aggregated_outputs 的早期轮次)
+}
+
+export interface ContextTruncatedData {
+    node_id: string;
+    model: string;
+    context_window: number;
+    sections: TruncatedSection[];
+}
+
 export interface ParallelStartData {
     node_id: string;
     branches: string[];
@@ -52,9 +83,11 @@ export interface ToolExecutionData {
 }
 
 // 上行命令 (Client -> Server)
-export type WSCommandType = 'start_session' | 'pause_session' | 'resume_session' | 'user_input';
+export type WSCommandType = 'start_session' | 'pause_session' | 'resume_session' | 'user_input' | 'subscribe' | 'unsubscribe'
+    | 'control' | 'review' | 'signal' | 'ping';
 
 export interface WSCommand<T = unknown> {
+    id?: string;            // 请求 ID, 回复事件中原样返回
     cmd: WSCommandType;
     data?: T;
 }
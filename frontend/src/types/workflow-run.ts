@@ -15,6 +15,15 @@ export interface RuntimeNode {
         output: number;
         cost: number;
     };
+    throttle?: {                 // LLM 调用正在限流排队
+        provider: string;
+        reason: string;
+        waitMs: number;
+    };
+    truncation?: {               // 提示词为适应上下文窗口被截断的部分
+        sections: string[];
+        droppedTokens: number;
+    };
     [key: string]: unknown;
 }
 
@@ -22,15 +22,36 @@ export interface WorkflowContext {
 //////////
 // source: cost.go
 
+/**
+ * CostEstimate is the expected spend of a workflow run, with the range it may
+ * fall in. TotalCostUSD and TotalTokens are the expected figures.
+ */
 export interface CostEstimate {
   total_cost_usd: number /* float64 */;
   total_tokens: number /* int */;
+  cost_range: CostRange;
+  token_range: TokenRange;
   node_breakdown: { [key: string]: NodeCostEstimate};
   agent_breakdown: { [key: string]: number /* float64 */};
+  warnings?: string[]; // Assumptions made, e.g. unknown agents
 }
 export interface NodeCostEstimate {
   cost_usd: number /* float64 */;
   tokens: number /* int */;
+  model?: string;
+  runs: number /* int */; // Maximum runs, > 1 inside loops
+  cost_range: CostRange;
+  token_range: TokenRange;
+}
+export interface CostRange {
+  min: number /* float64 */;
+  expected: number /* float64 */;
+  max: number /* float64 */;
+}
+export interface TokenRange {
+  min: number /* int */;
+  expected: number /* int */;
+  max: number /* int */;
 }
 
 //////////
@@ -0,0 +1,295 @@
+// Package authz authenticates API requests and enforces per-group roles.
+//
+// Requests carry either a session token issued by POST /auth/login or an API
+// key, as "Authorization: Bearer <token|key>", "X-API-Key: <key>" or, for
+// WebSocket and EventSource clients that cannot set headers, "?token=".
+//
+// Every user belongs to a tenant, and the middleware binds it to the request
+// context (see package tenant), so that repositories only ever see that
+// tenant's data. Within the tenant, access is checked against the role the
+// user holds in the group owning the resource (see auth.Role). Administrators
+// pass every check within their tenant. Resources not bound to a group, such
+// as the tenant's agent and workflow catalogs, are open to any authenticated
+// user for reading; changing them requires the editor role in at least one
+// group. Sessions and memories without a group require the role in at least
+// one group too. Administrators of the default tenant also manage tenants.
+package authz
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/tenant"
+)
+
+const userKey = "authz.user"
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("insufficient permissions")
+)
+
+// Authenticator resolves the calling user from a session token or an API key.
+type Authenticator struct {
+	Repo   auth.Repository
+	Tokens *auth.TokenIssuer
+	// Disabled lets every request through as auth.LocalUser.
+	Disabled bool
+}
+
+// Middleware rejects unauthenticated requests with 401 and stores the user
+// for UserFrom.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.Disabled {
+			SetUser(c, auth.LocalUser)
+			c.Next()
+			return
+		}
+
+		user, err := a.Authenticate(c.Request.Context(), credential(c))
+		if err != nil {
+			c.AbortWithStatusJSON(Status(err), gin.H{"error": err.Error()})
+			return
+		}
+		SetUser(c, user)
+		c.Next()
+	}
+}
+
+// Authenticate resolves an API key (auth.APIKeyPrefix) or a session token.
+func (a *Authenticator) Authenticate(ctx context.Context, credential string) (*auth.User, error) {
+	if credential == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	var user *auth.User
+	var err error
+	if strings.HasPrefix(credential, auth.APIKeyPrefix) {
+		user, err = a.Repo.GetUserByAPIKey(ctx, auth.HashAPIKey(credential))
+	} else {
+		var id uuid.UUID
+		if id, err = a.Tokens.Verify(credential); err != nil {
+			return nil, ErrUnauthenticated
+		}
+		user, err = a.Repo.GetUser(ctx, id)
+	}
+	if errors.Is(err, auth.ErrNotFound) {
+		return nil, ErrUnauthenticated
+	}
+	return user, err
+}
+
+func credential(c *gin.Context) string {
+	if h := c.GetHeader("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	return c.Query("token")
+}
+
+// SetUser stores the authenticated user on the request and binds the
+// request context to the user's tenant.
+func SetUser(c *gin.Context, u *auth.User) {
+	c.Set(userKey, u)
+	if c.Request != nil && u != nil && u.TenantID != uuid.Nil {
+		c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), u.TenantID))
+	}
+}
+
+// UserFrom returns the authenticated user, or nil outside the middleware.
+func UserFrom(c *gin.Context) *auth.User {
+	if v, ok := c.Get(userKey); ok {
+		if u, ok := v.(*auth.User); ok {
+			return u
+		}
+	}
+	return nil
+}
+
+// Status maps an authorization error to its HTTP status.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Policy checks group roles and records audit events. A nil Policy allows
+// everything, so that handlers can be used without authentication.
+type Policy struct {
+	Repo auth.Repository
+}
+
+// Check returns nil if the user holds at least the required role in the
+// group. An empty groupID means the resource belongs to no group; the user
+// must then hold the role in some group (see CheckAny).
+func (p *Policy) Check(ctx context.Context, u *auth.User, groupID string, required auth.Role) error {
+	if p == nil {
+		return nil
+	}
+	if u == nil {
+		return ErrUnauthenticated
+	}
+	if u.IsAdmin {
+		return nil
+	}
+	if groupID == "" {
+		return p.CheckAny(ctx, u, required)
+	}
+
+	gid, err := uuid.Parse(groupID)
+	if err != nil {
+		return ErrForbidden
+	}
+	role, err := p.Repo.GetRole(ctx, gid, u.ID)
+	if errors.Is(err, auth.ErrNotFound) {
+		return ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+	if !role.Allows(required) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CheckAny returns nil if the user holds at least the required role in any group.
+func (p *Policy) CheckAny(ctx context.Context, u *auth.User, required auth.Role) error {
+	if p == nil {
+		return nil
+	}
+	if u == nil {
+		return ErrUnauthenticated
+	}
+	if u.IsAdmin {
+		return nil
+	}
+
+	memberships, err := p.Repo.ListMemberships(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	for _, m := range memberships {
+		if m.Role.Allows(required) {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// GroupResolver returns the group owning the request's resource, or an error
+// if the resource does not exist.
+type GroupResolver func(c *gin.Context) (string, error)
+
+// GroupParam resolves the group from a path parameter holding its UUID.
+func GroupParam(name string) GroupResolver {
+	return func(c *gin.Context) (string, error) {
+		return c.Param(name), nil
+	}
+}
+
+// Require aborts the request unless the user holds the role in the resource's group.
+func (p *Policy) Require(required auth.Role, group GroupResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		groupID, err := group(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err := p.Check(c.Request.Context(), UserFrom(c), groupID, required); err != nil {
+			c.AbortWithStatusJSON(Status(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAny aborts the request unless the user holds the role in some group.
+func (p *Policy) RequireAny(required auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := p.CheckAny(c.Request.Context(), UserFrom(c), required); err != nil {
+			c.AbortWithStatusJSON(Status(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin aborts the request unless the user is an administrator.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		u := UserFrom(c)
+		if u == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrUnauthenticated.Error()})
+			return
+		}
+		if !u.IsAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": ErrForbidden.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireOperator aborts the request unless the user is an administrator of
+// the default tenant, the only users allowed to manage tenants.
+func RequireOperator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		u := UserFrom(c)
+		if u == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ErrUnauthenticated.Error()})
+			return
+		}
+		if !u.IsAdmin || u.TenantID != tenant.DefaultID {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": ErrForbidden.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Audit records an action by the user. Failures are logged, not returned,
+// so that auditing never blocks the action itself.
+func (p *Policy) Audit(ctx context.Context, u *auth.User, action, groupID, sessionID string, details map[string]interface{}) {
+	if p == nil || u == nil {
+		return
+	}
+	event := &auth.AuditEvent{
+		Username:  u.Username,
+		Action:    action,
+		GroupID:   parseOptional(groupID),
+		SessionID: parseOptional(sessionID),
+		Details:   details,
+	}
+	if u.ID != uuid.Nil {
+		id := u.ID
+		event.UserID = &id
+	}
+	if err := p.Repo.RecordAudit(ctx, event); err != nil {
+		log.Printf("[Audit] Failed to record %s by %s: %v", action, u.Username, err)
+	}
+}
+
+func parseOptional(id string) *uuid.UUID {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
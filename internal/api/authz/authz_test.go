@@ -0,0 +1,168 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func setup(t *testing.T) (*mocks.AuthMockRepository, *Authenticator, *auth.User) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewAuthMockRepository()
+	user := &auth.User{Username: "alice"}
+	if err := repo.CreateUser(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return repo, &Authenticator{Repo: repo, Tokens: auth.NewTokenIssuer("secret")}, user
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	repo, a, user := setup(t)
+	token, _, _ := a.Tokens.Issue(user.ID)
+	key, record, _ := auth.GenerateAPIKey("ci")
+	record.UserID = user.ID
+	_ = repo.CreateAPIKey(context.Background(), record)
+
+	r := gin.New()
+	r.Use(a.Middleware())
+	r.GET("/me", func(c *gin.Context) {
+		c.String(http.StatusOK, UserFrom(c).Username)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		query  string
+		want   int
+	}{
+		{"NoCredential", "", "", "", http.StatusUnauthorized},
+		{"BearerToken", "Authorization", "Bearer " + token, "", http.StatusOK},
+		{"BearerAPIKey", "Authorization", "Bearer " + key, "", http.StatusOK},
+		{"APIKeyHeader", "X-API-Key", key, "", http.StatusOK},
+		{"QueryToken", "", "", "?token=" + token, http.StatusOK},
+		{"ForgedToken", "Authorization", "Bearer " + token + "x", "", http.StatusUnauthorized},
+		{"UnknownKey", "X-API-Key", auth.APIKeyPrefix + "nope", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/me"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.want == http.StatusOK && w.Body.String() != "alice" {
+				t.Errorf("expected user alice, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Disabled(t *testing.T) {
+	_, a, _ := setup(t)
+	a.Disabled = true
+
+	r := gin.New()
+	r.Use(a.Middleware())
+	r.GET("/me", func(c *gin.Context) {
+		id, _ := tenant.FromContext(c.Request.Context())
+		c.String(http.StatusOK, UserFrom(c).Username+"@"+id.String())
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+	r.ServeHTTP(w, req)
+	if want := auth.LocalUser.Username + "@" + tenant.DefaultID.String(); w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("expected local user of the default tenant, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	repo, _, user := setup(t)
+	policy := &Policy{Repo: repo}
+	ctx := context.Background()
+	groupID := uuid.New()
+	_ = repo.SetMembership(ctx, &auth.Membership{GroupID: groupID, UserID: user.ID, Role: auth.RoleReviewer})
+
+	if err := policy.Check(ctx, user, groupID.String(), auth.RoleReviewer); err != nil {
+		t.Errorf("expected reviewer to pass, got %v", err)
+	}
+	if err := policy.Check(ctx, user, groupID.String(), auth.RoleEditor); err != ErrForbidden {
+		t.Errorf("expected reviewer to fail editor check, got %v", err)
+	}
+	if err := policy.Check(ctx, user, uuid.NewString(), auth.RoleViewer); err != ErrForbidden {
+		t.Errorf("expected non-member to fail, got %v", err)
+	}
+	if err := policy.Check(ctx, user, "", auth.RoleReviewer); err != nil {
+		t.Errorf("expected ungrouped resource to pass with the role in some group, got %v", err)
+	}
+	if err := policy.Check(ctx, user, "", auth.RoleEditor); err != ErrForbidden {
+		t.Errorf("expected ungrouped resource to require the role in some group, got %v", err)
+	}
+	if err := policy.Check(ctx, &auth.User{IsAdmin: true}, uuid.NewString(), auth.RoleOwner); err != nil {
+		t.Errorf("expected admin to pass, got %v", err)
+	}
+	if err := policy.Check(ctx, nil, groupID.String(), auth.RoleViewer); err != ErrUnauthenticated {
+		t.Errorf("expected anonymous to fail, got %v", err)
+	}
+
+	if err := policy.CheckAny(ctx, user, auth.RoleReviewer); err != nil {
+		t.Errorf("expected CheckAny reviewer to pass, got %v", err)
+	}
+	if err := policy.CheckAny(ctx, user, auth.RoleEditor); err != ErrForbidden {
+		t.Errorf("expected CheckAny editor to fail, got %v", err)
+	}
+}
+
+func TestPolicy_Require(t *testing.T) {
+	repo, _, user := setup(t)
+	policy := &Policy{Repo: repo}
+	groupID := uuid.New()
+	_ = repo.SetMembership(context.Background(), &auth.Membership{GroupID: groupID, UserID: user.ID, Role: auth.RoleViewer})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { SetUser(c, user) })
+	r.GET("/groups/:id", policy.Require(auth.RoleViewer, GroupParam("id")), func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.PUT("/groups/:id", policy.Require(auth.RoleEditor, GroupParam("id")), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for method, want := range map[string]int{http.MethodGet: http.StatusOK, http.MethodPut: http.StatusForbidden} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/groups/"+groupID.String(), nil)
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", method, want, w.Code)
+		}
+	}
+}
+
+func TestPolicy_Audit(t *testing.T) {
+	repo, _, user := setup(t)
+	policy := &Policy{Repo: repo}
+	sessionID := uuid.New()
+
+	policy.Audit(context.Background(), user, "session.review", "", sessionID.String(), map[string]interface{}{"action": "approve"})
+	policy.Audit(context.Background(), auth.LocalUser, "session.control", "", "not-a-uuid", nil)
+
+	if len(repo.Audit) != 2 {
+		t.Fatalf("expected 2 audit events, got %d", len(repo.Audit))
+	}
+	if e := repo.Audit[0]; e.UserID == nil || *e.UserID != user.ID || e.SessionID == nil || *e.SessionID != sessionID {
+		t.Errorf("unexpected audit event %+v", e)
+	}
+	if e := repo.Audit[1]; e.UserID != nil || e.SessionID != nil || e.Username != "local" {
+		t.Errorf("unexpected local audit event %+v", e)
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
+)
+
+type AuthHandler struct {
+	repo   auth.Repository
+	tokens *auth.TokenIssuer
+}
+
+func NewAuthHandler(repo auth.Repository, tokens *auth.TokenIssuer) *AuthHandler {
+	return &AuthHandler{repo: repo, tokens: tokens}
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Login exchanges a username and password for a signed session token.
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.repo.GetUserByUsername(c.Request.Context(), req.Username)
+	if err != nil && !errors.Is(err, auth.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil || !auth.CheckPassword(user, req.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid username or password"})
+		return
+	}
+
+	token, expires, err := h.tokens.Issue(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"expires_at": expires,
+		"user":       user,
+	})
+}
+
+// Me returns the calling user and the groups they belong to.
+func (h *AuthHandler) Me(c *gin.Context) {
+	user := authz.UserFrom(c)
+	memberships := []*auth.Membership{}
+	if user.ID != uuid.Nil {
+		list, err := h.repo.ListMemberships(c.Request.Context(), user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if list != nil {
+			memberships = list
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user, "memberships": memberships})
+}
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CreateAPIKey issues an API key for the calling user. The key is only
+// returned in this response.
+func (h *AuthHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := authz.UserFrom(c)
+	if user.ID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "api keys require an account"})
+		return
+	}
+
+	plain, key, err := auth.GenerateAPIKey(req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	key.UserID = user.ID
+	if err := h.repo.CreateAPIKey(c.Request.Context(), key); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"key": plain, "api_key": key})
+}
+
+type CreateUserRequest struct {
+	Username    string  `json:"username" binding:"required,max=64"`
+	Password    string  `json:"password" binding:"required,min=8"`
+	DisplayName *string `json:"display_name"`
+	IsAdmin     bool    `json:"is_admin"`
+}
+
+// CreateUser adds an account. Only administrators may call it.
+func (h *AuthHandler) CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.repo.GetUserByUsername(c.Request.Context(), req.Username); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "username already taken"})
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	user := &auth.User{
+		Username:     req.Username,
+		DisplayName:  req.DisplayName,
+		PasswordHash: &hash,
+		IsAdmin:      req.IsAdmin,
+	}
+	if err := h.repo.CreateUser(c.Request.Context(), user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// ListSessionAudit returns who did what in a session, oldest first.
+func (h *AuthHandler) ListSessionAudit(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format"})
+		return
+	}
+
+	events, err := h.repo.ListAudit(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if events == nil {
+		events = []*auth.AuditEvent{}
+	}
+
+	c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/agent"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/group"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func TestAuthHandler_LoginAndAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewAuthMockRepository()
+	tokens := auth.NewTokenIssuer("secret")
+	hash, _ := auth.HashPassword("correct horse")
+	_ = repo.CreateUser(context.Background(), &auth.User{Username: "alice", PasswordHash: &hash})
+
+	h := NewAuthHandler(repo, tokens)
+	authn := &authz.Authenticator{Repo: repo, Tokens: tokens}
+	r := gin.New()
+	r.POST("/auth/login", h.Login)
+	protected := r.Group("/", authn.Middleware())
+	protected.GET("/auth/me", h.Me)
+	protected.POST("/auth/keys", h.CreateAPIKey)
+
+	do := func(method, path, credential string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		if credential != "" {
+			req.Header.Set("Authorization", "Bearer "+credential)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodPost, "/auth/login", "", LoginRequest{Username: "alice", Password: "wrong"}); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong password, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/auth/login", "", LoginRequest{Username: "nobody", Password: "x"}); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown user, got %d", w.Code)
+	}
+
+	w := do(http.MethodPost, "/auth/login", "", LoginRequest{Username: "alice", Password: "correct horse"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var login struct {
+		Token string                 `json:"token"`
+		User  map[string]interface{} `json:"user"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &login)
+	if login.Token == "" {
+		t.Fatal("Expected a session token")
+	}
+	if _, leaked := login.User["password_hash"]; leaked {
+		t.Error("Password hash must not be serialized")
+	}
+
+	w = do(http.MethodPost, "/auth/keys", login.Token, CreateAPIKeyRequest{Name: "ci"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+
+	w = do(http.MethodGet, "/auth/me", created.Key, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the API key to authenticate, got %d", w.Code)
+	}
+	var me struct {
+		User auth.User `json:"user"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &me)
+	if me.User.Username != "alice" {
+		t.Errorf("Expected alice, got %+v", me.User)
+	}
+}
+
+func TestGroupHandler_Membership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	members := mocks.NewAuthMockRepository()
+	owner := &auth.User{Username: "olga"}
+	viewer := &auth.User{Username: "victor"}
+	_ = members.CreateUser(context.Background(), owner)
+	_ = members.CreateUser(context.Background(), viewer)
+
+	h := NewGroupHandler(mocks.NewGroupMockRepository())
+	h.Members = members
+	policy := &authz.Policy{Repo: members}
+
+	current := owner
+	r := gin.New()
+	r.Use(func(c *gin.Context) { authz.SetUser(c, current) })
+	r.POST("/groups", h.Create)
+	r.GET("/groups", h.List)
+	r.PUT("/groups/:id/members", policy.Require(auth.RoleOwner, authz.GroupParam("id")), h.SetMember)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/groups", group.Group{Name: "Board"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", w.Code)
+	}
+	var g group.Group
+	_ = json.Unmarshal(w.Body.Bytes(), &g)
+	if role, _ := members.GetRole(context.Background(), g.ID, owner.ID); role != auth.RoleOwner {
+		t.Errorf("Expected the creator to own the group, got %q", role)
+	}
+
+	// Not yet a member: the group is hidden and cannot be managed
+	current = viewer
+	var listed []group.Group
+	_ = json.Unmarshal(do(http.MethodGet, "/groups", nil).Body.Bytes(), &listed)
+	if len(listed) != 0 {
+		t.Errorf("Expected no visible groups, got %d", len(listed))
+	}
+	if w := do(http.MethodPut, "/groups/"+g.ID.String()+"/members", SetMemberRequest{Username: "victor", Role: auth.RoleOwner}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a non-owner, got %d", w.Code)
+	}
+
+	current = owner
+	if w := do(http.MethodPut, "/groups/"+g.ID.String()+"/members", SetMemberRequest{Username: "victor", Role: auth.RoleViewer}); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	current = viewer
+	_ = json.Unmarshal(do(http.MethodGet, "/groups", nil).Body.Bytes(), &listed)
+	if len(listed) != 1 || listed[0].ID != g.ID {
+		t.Errorf("Expected the group to be visible to its new member, got %+v", listed)
+	}
+}
+
+func TestGroupHandler_CreateRequiresEditor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	members := mocks.NewAuthMockRepository()
+	viewer := &auth.User{Username: "victor"}
+	editor := &auth.User{Username: "edith"}
+	_ = members.CreateUser(context.Background(), viewer)
+	_ = members.CreateUser(context.Background(), editor)
+	existing := uuid.New()
+	_ = members.SetMembership(context.Background(), &auth.Membership{GroupID: existing, UserID: viewer.ID, Role: auth.RoleViewer})
+	_ = members.SetMembership(context.Background(), &auth.Membership{GroupID: existing, UserID: editor.ID, Role: auth.RoleEditor})
+
+	groups := NewGroupHandler(mocks.NewGroupMockRepository())
+	groups.Members = members
+	agents := mocks.NewAgentMockRepository()
+	a := &agent.Agent{Name: "Analyst"}
+	_ = agents.Create(context.Background(), a)
+	editAny := (&authz.Policy{Repo: members}).RequireAny(auth.RoleEditor)
+
+	current := viewer
+	r := gin.New()
+	r.Use(func(c *gin.Context) { authz.SetUser(c, current) })
+	r.POST("/groups", editAny, groups.Create)
+	r.PUT("/agents/:id", editAny, NewAgentHandler(agents).Update)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// A viewer can neither create a group to own nor, therefore, edit agents
+	if w := do(http.MethodPost, "/groups", group.Group{Name: "Mine"}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a viewer creating a group, got %d", w.Code)
+	}
+	if w := do(http.MethodPut, "/agents/"+a.ID.String(), agent.Agent{Name: "Hijacked"}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a viewer editing an agent, got %d", w.Code)
+	}
+	if memberships, _ := members.ListMemberships(context.Background(), viewer.ID); len(memberships) != 1 {
+		t.Errorf("Expected no new membership, got %d", len(memberships))
+	}
+
+	current = editor
+	if w := do(http.MethodPost, "/groups", group.Group{Name: "Board"}); w.Code != http.StatusCreated {
+		t.Errorf("Expected 201 for an editor, got %d", w.Code)
+	}
+}
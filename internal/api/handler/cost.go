@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/workflow"
+)
+
+// defaultLedgerLimit caps the entries of a JSON ledger listing. CSV exports
+// are complete unless a limit is given.
+const defaultLedgerLimit = 1000
+
+// CostHandler reports the spend recorded in the cost ledger. Reports of a
+// group require the viewer role in it; tenant-wide reports require an
+// administrator.
+type CostHandler struct {
+	Ledger workflow.CostLedger
+	Policy *authz.Policy // Group roles; nil allows everything
+}
+
+func NewCostHandler(ledger workflow.CostLedger) *CostHandler {
+	return &CostHandler{Ledger: ledger}
+}
+
+// Report sums the ledger by the dimensions in group_by (default
+// "month,group"), e.g.
+//
+//	GET /costs/report?group_by=month,group&month=2025-01&format=csv
+func (h *CostHandler) Report(c *gin.Context) {
+	filter, ok := h.filter(c)
+	if !ok {
+		return
+	}
+	dimensions := []string{workflow.CostByMonth, workflow.CostByGroup}
+	if groupBy := c.Query("group_by"); groupBy != "" {
+		dimensions = strings.Split(groupBy, ",")
+	}
+	for i, d := range dimensions {
+		dimensions[i] = strings.TrimSpace(d)
+		if !workflow.IsCostDimension(dimensions[i]) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown group_by %q, expected one of %s", d, strings.Join(workflow.CostDimensions, ", "))})
+			return
+		}
+	}
+
+	rows, err := h.Ledger.Report(c.Request.Context(), filter, dimensions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		records := [][]string{append(append([]string{}, dimensions...), "calls", "prompt_tokens", "completion_tokens", "cost_usd")}
+		for _, row := range rows {
+			record := make([]string, 0, len(dimensions)+4)
+			for _, d := range dimensions {
+				record = append(record, row.Keys[d])
+			}
+			record = append(record,
+				strconv.Itoa(row.Calls), strconv.Itoa(row.PromptTokens), strconv.Itoa(row.CompletionTokens),
+				strconv.FormatFloat(row.CostUSD, 'f', 6, 64))
+			records = append(records, record)
+		}
+		writeCSV(c, "cost_report.csv", records)
+		return
+	}
+
+	var total workflow.Usage
+	for _, row := range rows {
+		total = total.Add(workflow.Usage{PromptTokens: row.PromptTokens, CompletionTokens: row.CompletionTokens, CostUSD: row.CostUSD})
+	}
+	if rows == nil {
+		rows = []*workflow.CostReportRow{}
+	}
+	c.JSON(http.StatusOK, gin.H{"group_by": dimensions, "rows": rows, "total": total})
+}
+
+// ListLedger lists the recorded LLM calls, newest first.
+func (h *CostHandler) ListLedger(c *gin.Context) {
+	filter, ok := h.filter(c)
+	if !ok {
+		return
+	}
+	csvFormat := c.Query("format") == "csv"
+	if filter.Limit == 0 && !csvFormat {
+		filter.Limit = defaultLedgerLimit
+	}
+
+	entries, err := h.Ledger.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if csvFormat {
+		records := [][]string{{
+			"created_at", "session_uuid", "group_uuid", "workflow_uuid", "node_id", "agent_uuid",
+			"provider", "model", "prompt_tokens", "completion_tokens", "cost_usd", "latency_ms",
+		}}
+		for _, e := range entries {
+			records = append(records, []string{
+				e.CreatedAt.UTC().Format(time.RFC3339), e.SessionID, e.GroupID, e.WorkflowID, e.NodeID, e.AgentID,
+				e.Provider, e.Model, strconv.Itoa(e.PromptTokens), strconv.Itoa(e.CompletionTokens),
+				strconv.FormatFloat(e.CostUSD, 'f', 6, 64), strconv.FormatInt(e.LatencyMs, 10),
+			})
+		}
+		writeCSV(c, "cost_ledger.csv", records)
+		return
+	}
+
+	if entries == nil {
+		entries = []*workflow.LedgerEntry{}
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// filter parses the query (group_uuid, month or from/to, limit) and checks
+// that the caller may see the selected spend. It writes the error response
+// and returns false if not.
+func (h *CostHandler) filter(c *gin.Context) (workflow.CostFilter, bool) {
+	filter := workflow.CostFilter{GroupID: c.Query("group_uuid")}
+
+	var err error
+	if month := c.Query("month"); month != "" {
+		if filter.From, err = time.Parse("2006-01", month); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "month must be YYYY-MM"})
+			return filter, false
+		}
+		filter.To = filter.From.AddDate(0, 1, 0)
+	}
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = parseDate(from); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+			return filter, false
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if filter.To, err = parseDate(to); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+			return filter, false
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return filter, false
+		}
+	}
+
+	if err := h.authorize(c, filter.GroupID); err != nil {
+		c.JSON(authz.Status(err), gin.H{"error": err.Error()})
+		return filter, false
+	}
+	return filter, true
+}
+
+func (h *CostHandler) authorize(c *gin.Context, groupID string) error {
+	if h.Policy == nil {
+		return nil
+	}
+	u := authz.UserFrom(c)
+	if groupID != "" {
+		return h.Policy.Check(c.Request.Context(), u, groupID, auth.RoleViewer)
+	}
+	// Tenant-wide spend spans every group
+	if u == nil {
+		return authz.ErrUnauthenticated
+	}
+	if !u.IsAdmin {
+		return authz.ErrForbidden
+	}
+	return nil
+}
+
+// parseDate accepts a date (YYYY-MM-DD, UTC midnight) or an RFC 3339 timestamp.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", s)
+	}
+	return t, nil
+}
+
+func writeCSV(c *gin.Context, filename string, records [][]string) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	if err := w.WriteAll(records); err != nil {
+		// Headers are sent, the client sees a truncated file
+		_ = c.Error(err)
+	}
+}
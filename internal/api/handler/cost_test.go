@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func TestCostHandler_Report(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ledger := mocks.NewCostLedgerMock()
+	ledger.Rows = []*workflow.CostReportRow{
+		{Keys: map[string]string{"month": "2025-01", "group": "g1"}, Calls: 10, PromptTokens: 2000, CompletionTokens: 500, CostUSD: 0.25},
+		{Keys: map[string]string{"month": "2025-01", "group": "g2"}, Calls: 2, PromptTokens: 100, CompletionTokens: 50, CostUSD: 0.05},
+	}
+	h := NewCostHandler(ledger)
+	r := gin.New()
+	r.GET("/costs/report", h.Report)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		w := get("/costs/report?month=2025-01")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			GroupBy []string                  `json:"group_by"`
+			Rows    []*workflow.CostReportRow `json:"rows"`
+			Total   workflow.Usage            `json:"total"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if strings.Join(resp.GroupBy, ",") != "month,group" || len(resp.Rows) != 2 {
+			t.Errorf("Expected monthly spend per group by default, got %+v", resp)
+		}
+		if resp.Total.CostUSD != 0.3 || resp.Total.TotalTokens() != 2650 {
+			t.Errorf("Expected totals over all rows, got %+v", resp.Total)
+		}
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		if !ledger.Filter.From.Equal(from) || !ledger.Filter.To.Equal(from.AddDate(0, 1, 0)) {
+			t.Errorf("Expected month to select January, got %v - %v", ledger.Filter.From, ledger.Filter.To)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		w := get("/costs/report?group_by=group,month&format=csv")
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+			t.Fatalf("Expected a CSV file, got %d %q", w.Code, w.Header().Get("Content-Type"))
+		}
+		records, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 3 || strings.Join(records[0], ",") != "group,month,calls,prompt_tokens,completion_tokens,cost_usd" {
+			t.Fatalf("Unexpected CSV %v", records)
+		}
+		if strings.Join(records[1], ",") != "g1,2025-01,10,2000,500,0.250000" {
+			t.Errorf("Unexpected CSV row %v", records[1])
+		}
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		for _, path := range []string{"/costs/report?group_by=tenant", "/costs/report?month=January", "/costs/report?from=yesterday"} {
+			if w := get(path); w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", path, w.Code)
+			}
+		}
+	})
+}
+
+func TestCostHandler_Ledger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ledger := mocks.NewCostLedgerMock()
+	ledger.Entries = []*workflow.LedgerEntry{{
+		ID: 1, SessionID: "s1", GroupID: "g1", NodeID: "agent_1", Provider: "openai", Model: "gpt-4o",
+		PromptTokens: 100, CompletionTokens: 20, CostUSD: 0.0012, LatencyMs: 850, CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+	h := NewCostHandler(ledger)
+	r := gin.New()
+	r.GET("/costs/ledger", h.ListLedger)
+
+	req, _ := http.NewRequest(http.MethodGet, "/costs/ledger?from=2025-01-01&to=2025-02-01", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"model":"gpt-4o"`) {
+		t.Fatalf("Expected the entries, got %d: %s", w.Code, w.Body.String())
+	}
+	if ledger.Filter.Limit != defaultLedgerLimit {
+		t.Errorf("Expected JSON listings to be capped, got limit %d", ledger.Filter.Limit)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/costs/ledger?format=csv", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil || len(records) != 2 || records[1][0] != "2025-01-02T03:04:05Z" || records[1][10] != "0.001200" {
+		t.Errorf("Unexpected CSV export %v (%v)", records, err)
+	}
+	if ledger.Filter.Limit != 0 {
+		t.Errorf("Expected CSV exports to be complete, got limit %d", ledger.Filter.Limit)
+	}
+}
+
+func TestCostHandler_Authorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	members := mocks.NewAuthMockRepository()
+	viewer := &auth.User{Username: "victor"}
+	_ = members.CreateUser(context.Background(), viewer)
+	groupID := uuid.New()
+	_ = members.SetMembership(context.Background(), &auth.Membership{GroupID: groupID, UserID: viewer.ID, Role: auth.RoleViewer})
+
+	h := NewCostHandler(mocks.NewCostLedgerMock())
+	h.Policy = &authz.Policy{Repo: members}
+	current := viewer
+	r := gin.New()
+	r.Use(func(c *gin.Context) { authz.SetUser(c, current) })
+	r.GET("/costs/report", h.Report)
+
+	get := func(path string) int {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := get("/costs/report?group_uuid=" + groupID.String()); code != http.StatusOK {
+		t.Errorf("Expected a viewer to see the spend of their group, got %d", code)
+	}
+	if code := get("/costs/report?group_uuid=" + uuid.NewString()); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another group, got %d", code)
+	}
+	if code := get("/costs/report"); code != http.StatusForbidden {
+		t.Errorf("Expected tenant-wide spend to require an administrator, got %d", code)
+	}
+
+	current = &auth.User{Username: "admin", IsAdmin: true}
+	if code := get("/costs/report"); code != http.StatusOK {
+		t.Errorf("Expected an administrator to see tenant-wide spend, got %d", code)
+	}
+}
@@ -1,15 +1,22 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
 	"github.com/hrygo/council/internal/core/group"
+	"github.com/hrygo/council/internal/core/tenant"
 )
 
 type GroupHandler struct {
 	repo group.Repository
+	// Members makes a group's creator its owner and limits List to the
+	// caller's groups; nil lists every group.
+	Members auth.Repository
 }
 
 func NewGroupHandler(repo group.Repository) *GroupHandler {
@@ -28,6 +35,14 @@ func (h *GroupHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if user := authz.UserFrom(c); h.Members != nil && user != nil && user.ID != uuid.Nil {
+		owner := &auth.Membership{GroupID: g.ID, UserID: user.ID, Role: auth.RoleOwner}
+		if err := h.Members.SetMembership(c.Request.Context(), owner); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	c.JSON(http.StatusCreated, g)
 }
 
@@ -55,6 +70,25 @@ func (h *GroupHandler) List(c *gin.Context) {
 		return
 	}
 
+	if user := authz.UserFrom(c); h.Members != nil && user != nil && !user.IsAdmin {
+		memberships, err := h.Members.ListMemberships(c.Request.Context(), user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		member := make(map[uuid.UUID]bool, len(memberships))
+		for _, m := range memberships {
+			member[m.GroupID] = true
+		}
+		visible := make([]*group.Group, 0, len(groups))
+		for _, g := range groups {
+			if member[g.ID] {
+				visible = append(visible, g)
+			}
+		}
+		groups = visible
+	}
+
 	c.JSON(http.StatusOK, groups)
 }
 
@@ -96,3 +130,93 @@ func (h *GroupHandler) Delete(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+func (h *GroupHandler) ListMembers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format"})
+		return
+	}
+
+	members, err := h.Members.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if members == nil {
+		members = []*auth.Membership{}
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+type SetMemberRequest struct {
+	Username string    `json:"username" binding:"required"`
+	Role     auth.Role `json:"role" binding:"required,oneof=owner editor reviewer viewer"`
+}
+
+// SetMember grants a user a role in the group, replacing any previous role.
+func (h *GroupHandler) SetMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format"})
+		return
+	}
+	var req SetMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.Members.GetUserByUsername(c.Request.Context(), req.Username)
+	if err == nil {
+		// Usernames are global; users of other tenants are not visible
+		if tenantID, ok := tenant.FromContext(c.Request.Context()); ok && user.TenantID != tenantID {
+			err = auth.ErrNotFound
+		}
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	m := &auth.Membership{GroupID: id, UserID: user.ID, Username: user.Username, Role: req.Role}
+	if err := h.Members.SetMembership(c.Request.Context(), m); err != nil {
+		if errors.Is(err, auth.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, m)
+}
+
+func (h *GroupHandler) RemoveMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format"})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id format"})
+		return
+	}
+
+	if err := h.Members.RemoveMembership(c.Request.Context(), id, userID); err != nil {
+		if errors.Is(err, auth.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
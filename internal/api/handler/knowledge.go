@@ -56,8 +56,7 @@ func (h *KnowledgeHandler) GetSessionKnowledge(c *gin.Context) {
 	// We need GroupID for the session
 	var groupID string
 	// Strategy: check active engines first
-	enginesMu.RLock()
-	if engine := activeEngines[sessionID]; engine != nil {
+	if engine := activeEngine(c.Request.Context(), sessionID); engine != nil {
 		engine.Mu.RLock()
 		groupID, _ = engine.Session.Inputs["group_uuid"].(string)
 		if groupID == "" {
@@ -65,7 +64,6 @@ func (h *KnowledgeHandler) GetSessionKnowledge(c *gin.Context) {
 		}
 		engine.Mu.RUnlock()
 	}
-	enginesMu.RUnlock()
 	// If not found (or inactive), check DB
 	if groupID == "" && h.sessionRepo != nil {
 		sEntity, err := h.sessionRepo.Get(c.Request.Context(), sessionID)
@@ -6,11 +6,15 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
 	"github.com/hrygo/council/internal/core/memory"
+	"github.com/hrygo/council/internal/core/tenant"
 )
 
 type MemoryHandler struct {
 	Manager memory.MemoryManager
+	Policy  *authz.Policy // Group roles; nil allows everything
 }
 
 func NewMemoryHandler(manager memory.MemoryManager) *MemoryHandler {
@@ -28,10 +32,14 @@ func (h *MemoryHandler) Ingest(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := h.Policy.Check(c.Request.Context(), authz.UserFrom(c), req.GroupID, auth.RoleEditor); err != nil {
+		c.JSON(authz.Status(err), gin.H{"error": err.Error()})
+		return
+	}
 
 	// Trigger Promotion (Long-Term Memory)
 	// In MVP we expose this directly. In production, this might be async.
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Detach(c.Request.Context()), 30*time.Second)
 	defer cancel()
 
 	if err := h.Manager.Promote(ctx, req.GroupID, req.Content); err != nil {
@@ -53,8 +61,12 @@ func (h *MemoryHandler) Query(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := h.Policy.Check(c.Request.Context(), authz.UserFrom(c), req.GroupID, auth.RoleViewer); err != nil {
+		c.JSON(authz.Status(err), gin.H{"error": err.Error()})
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Detach(c.Request.Context()), 10*time.Second)
 	defer cancel()
 
 	sessionID := c.Query("session_id")
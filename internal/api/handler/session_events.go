@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hrygo/council/internal/core/workflow"
+)
+
+// sseHeartbeat is the interval of the keep-alive comments on idle streams.
+const sseHeartbeat = 15 * time.Second
+
+// StreamEvents streams a session's events as Server-Sent Events, for clients
+// that cannot use the WebSocket. It shares the hub's fan-out and replay:
+//
+//   - each event is sent as "id: <seq>", "event: <type>" and the StreamEvent JSON as data;
+//   - Last-Event-ID (header or ?last_event_id=) resumes after that seq;
+//   - ?types=token_stream,node_state_change filters the event types;
+//   - the stream ends after "execution:completed", which is sent right after
+//     the replay for a session that has already finished.
+func (h *WorkflowHandler) StreamEvents(c *gin.Context) {
+	sessionID := c.Param("id")
+	if h.Hub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "event streaming not configured"})
+		return
+	}
+	var finished workflow.SessionStatus
+	if activeEngine(c.Request.Context(), sessionID) == nil && h.SessionRepo != nil {
+		entity, err := h.SessionRepo.Get(c.Request.Context(), sessionID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		switch entity.Status {
+		case workflow.SessionCompleted, workflow.SessionFailed, workflow.SessionCancelled:
+			finished = entity.Status
+		}
+	}
+
+	var lastSeq *uint64
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		seq, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		lastSeq = &seq
+	}
+
+	var types map[string]bool
+	if v := c.Query("types"); v != "" {
+		types = make(map[string]bool)
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types[t] = true
+			}
+		}
+	}
+
+	sub, replay := h.Hub.Subscribe(sessionID, lastSeq)
+	defer h.Hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+
+	// The replay is written directly, while live events queue on the subscriber
+	for _, event := range replay {
+		if !writeSSE(c.Writer, event, types) {
+			c.Writer.Flush()
+			return
+		}
+	}
+	if finished != "" {
+		writeSSE(c.Writer, workflow.StreamEvent{
+			Type:      "execution:completed",
+			Timestamp: time.Now(),
+			Data:      map[string]interface{}{"session_uuid": sessionID, "status": string(finished)},
+		}, types)
+		c.Writer.Flush()
+		return
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-sub.Events():
+			return writeSSE(w, event, types)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			return true
+		case <-sub.Done():
+			if reason := sub.CloseReason(); reason != "" {
+				data, _ := json.Marshal(gin.H{"error": reason})
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			}
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// writeSSE writes the event unless types filters it out, and reports whether
+// the stream goes on after it.
+func writeSSE(w io.Writer, event workflow.StreamEvent, types map[string]bool) bool {
+	if types == nil || types[event.Type] {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return true
+		}
+		if event.Seq > 0 {
+			fmt.Fprintf(w, "id: %d\n", event.Seq)
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	}
+	return event.Type != "execution:completed"
+}
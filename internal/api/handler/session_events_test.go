@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hrygo/council/internal/api/ws"
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func TestWorkflowHandler_StreamEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := ws.NewHub()
+	go hub.Run()
+	h := NewWorkflowHandler(hub, nil, nil, nil, nil, nil, nil)
+
+	router := gin.New()
+	router.GET("/sessions/:id/events", h.StreamEvents)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	event := func(eventType string) workflow.StreamEvent {
+		return workflow.StreamEvent{Type: eventType, Data: map[string]interface{}{"session_uuid": "s1"}}
+	}
+	// Sent before the client connects; seq 1 is skipped via Last-Event-ID
+	hub.Broadcast(event("token_stream"))
+	hub.Broadcast(event("token_stream"))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/sessions/s1/events?types=node_state_change,execution:completed", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", ct)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		hub.Broadcast(event("node_state_change"))
+		hub.Broadcast(event("token_stream"))
+		hub.Broadcast(event("execution:completed"))
+		hub.Broadcast(event("node_state_change")) // After the end of the stream
+	}()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	expected := []string{"id: 3", "event: node_state_change", "id: 5", "event: execution:completed"}
+	var got []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "id: ") || strings.HasPrefix(line, "event: ") {
+			got = append(got, line)
+		}
+	}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), `"seq":3`) {
+		t.Errorf("Expected StreamEvent payload as data, got %v", lines)
+	}
+}
+
+func TestWorkflowHandler_StreamEvents_LongReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := ws.NewHub()
+	go hub.Run()
+	h := NewWorkflowHandler(hub, nil, nil, nil, nil, nil, nil)
+
+	router := gin.New()
+	router.GET("/sessions/:id/events", h.StreamEvents)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// More missed events than a subscriber's send buffer holds
+	event := workflow.StreamEvent{Type: "token_stream", Data: map[string]interface{}{"session_uuid": "s1"}}
+	for i := 0; i < 600; i++ {
+		hub.Broadcast(event)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/sessions/s1/events", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		event.Type = "execution:completed"
+		start := time.Now()
+		hub.Broadcast(event)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the live event to be delivered at once, took %s", elapsed)
+		}
+	}()
+
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "id: ") {
+			ids = append(ids, line)
+		}
+	}
+	if len(ids) != 601 || ids[0] != "id: 1" || ids[600] != "id: 601" {
+		t.Errorf("Expected the 600 replayed events and the live one, got %d ending with %v", len(ids), ids[len(ids)-1:])
+	}
+}
+
+func TestWorkflowHandler_StreamEvents_FinishedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := ws.NewHub()
+	go hub.Run()
+	sessionRepo := mocks.NewSessionMockRepository()
+	sessionRepo.Status = workflow.SessionFailed
+	h := NewWorkflowHandler(hub, nil, nil, nil, sessionRepo, nil, nil)
+
+	router := gin.New()
+	router.GET("/sessions/:id/events", h.StreamEvents)
+
+	req, _ := http.NewRequest(http.MethodGet, "/sessions/s1/events", nil)
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the stream of a finished session to end")
+	}
+	if body := w.Body.String(); !strings.Contains(body, "event: execution:completed") || !strings.Contains(body, `"status":"failed"`) {
+		t.Errorf("Expected a terminal event with the session's status, got %q", body)
+	}
+}
+
+func TestWorkflowHandler_StreamEvents_InvalidLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewWorkflowHandler(ws.NewHub(), nil, nil, nil, nil, nil, nil)
+
+	router := gin.New()
+	router.GET("/sessions/:id/events", h.StreamEvents)
+
+	req, _ := http.NewRequest(http.MethodGet, "/sessions/s1/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrace returns the execution journal of a session: the input, output,
+// timings, error and token usage of every node run, in execution order.
+func (h *WorkflowHandler) GetTrace(c *gin.Context) {
+	sessionID := c.Param("id")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session_id required"})
+		return
+	}
+
+	executions, err := h.SessionRepo.ListExecutions(c.Request.Context(), sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load trace: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_uuid": sessionID,
+		"executions":   executions,
+	})
+}
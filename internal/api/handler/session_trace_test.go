@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func TestWorkflowHandler_GetTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sessionRepo := mocks.NewSessionMockRepository()
+	sessionRepo.Executions = []*workflow.NodeExecution{
+		{SessionID: "s1", NodeID: "start", Status: workflow.StatusCompleted, Iteration: 1},
+		{SessionID: "s1", NodeID: "agent", Status: workflow.StatusCompleted, Iteration: 1, PromptTokens: 10},
+		{SessionID: "other", NodeID: "start", Status: workflow.StatusCompleted, Iteration: 1},
+	}
+	h := NewWorkflowHandler(nil, nil, nil, nil, sessionRepo, nil, nil)
+
+	router := gin.New()
+	router.GET("/sessions/:id/trace", h.GetTrace)
+
+	req, _ := http.NewRequest(http.MethodGet, "/sessions/s1/trace", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		SessionID  string                    `json:"session_uuid"`
+		Executions []*workflow.NodeExecution `json:"executions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Executions) != 2 {
+		t.Fatalf("Expected 2 executions, got %d", len(resp.Executions))
+	}
+	if resp.Executions[1].NodeID != "agent" || resp.Executions[1].PromptTokens != 10 {
+		t.Errorf("Unexpected execution: %+v", resp.Executions[1])
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/tenant"
+)
+
+// TenantHandler manages tenants. Its routes are restricted to administrators
+// of the default tenant.
+type TenantHandler struct {
+	repo  tenant.Repository
+	users auth.Repository
+}
+
+func NewTenantHandler(repo tenant.Repository, users auth.Repository) *TenantHandler {
+	return &TenantHandler{repo: repo, users: users}
+}
+
+type CreateTenantRequest struct {
+	Name          string `json:"name" binding:"required,max=128"`
+	AdminUsername string `json:"admin_username" binding:"required,max=64"`
+	AdminPassword string `json:"admin_password" binding:"required,min=8"`
+}
+
+// Create adds a tenant together with its first administrator, who then
+// creates the tenant's groups and users.
+func (h *TenantHandler) Create(c *gin.Context) {
+	var req CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.users.GetUserByUsername(c.Request.Context(), req.AdminUsername); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "username already taken"})
+		return
+	}
+	hash, err := auth.HashPassword(req.AdminPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	t := &tenant.Tenant{Name: req.Name}
+	if err := h.repo.Create(c.Request.Context(), t); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	admin := &auth.User{Username: req.AdminUsername, PasswordHash: &hash, IsAdmin: true}
+	if err := h.users.CreateUser(tenant.WithID(c.Request.Context(), t.ID), admin); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"tenant": t, "admin": admin})
+}
+
+func (h *TenantHandler) List(c *gin.Context) {
+	tenants, err := h.repo.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if tenants == nil {
+		tenants = []*tenant.Tenant{}
+	}
+
+	c.JSON(http.StatusOK, tenants)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func TestTenantHandler_Create(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tenants := mocks.NewTenantMockRepository()
+	users := mocks.NewAuthMockRepository()
+	h := NewTenantHandler(tenants, users)
+
+	current := &auth.User{Username: "root", TenantID: tenant.DefaultID, IsAdmin: true}
+	r := gin.New()
+	r.Use(func(c *gin.Context) { authz.SetUser(c, current) })
+	r.POST("/tenants", authz.RequireOperator(), h.Create)
+
+	create := func(req CreateTenantRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		httpReq, _ := http.NewRequest(http.MethodPost, "/tenants", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+		return w
+	}
+
+	w := create(CreateTenantRequest{Name: "Acme", AdminUsername: "acme-admin", AdminPassword: "long enough"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(tenants.Tenants) != 1 {
+		t.Fatalf("Expected one tenant, got %d", len(tenants.Tenants))
+	}
+	admin, err := users.GetUserByUsername(context.Background(), "acme-admin")
+	if err != nil || !admin.IsAdmin || admin.TenantID != tenants.Tenants[0].ID {
+		t.Errorf("Expected an administrator of the new tenant, got %+v (%v)", admin, err)
+	}
+
+	if w := create(CreateTenantRequest{Name: "Other", AdminUsername: "acme-admin", AdminPassword: "long enough"}); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a taken username, got %d", w.Code)
+	}
+
+	// Administrators of other tenants cannot manage tenants
+	current = admin
+	if w := create(CreateTenantRequest{Name: "Sneaky", AdminUsername: "sneaky", AdminPassword: "long enough"}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a tenant administrator, got %d", w.Code)
+	}
+}
+
+func TestWorkflowHandler_TenantIsolation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewWorkflowHandler(nil, mocks.NewAgentMockRepository(), nil, nil, mocks.NewSessionMockRepository(), nil, nil)
+
+	owner, stranger := uuid.New(), uuid.New()
+	current := &auth.User{Username: "stranger", TenantID: stranger, IsAdmin: true}
+	r := gin.New()
+	r.Use(func(c *gin.Context) { authz.SetUser(c, current) })
+	r.POST("/sessions/:id/control", h.Control)
+
+	session := workflow.NewSession(&workflow.GraphDefinition{ID: "g"}, nil)
+	session.Start(tenant.WithID(context.Background(), owner))
+	enginesMu.Lock()
+	activeEngines[session.ID] = workflow.NewEngine(session)
+	enginesMu.Unlock()
+	defer func() {
+		enginesMu.Lock()
+		delete(activeEngines, session.ID)
+		enginesMu.Unlock()
+	}()
+
+	control := func() int {
+		body, _ := json.Marshal(ControlRequest{Action: "pause"})
+		req, _ := http.NewRequest(http.MethodPost, "/sessions/"+session.ID+"/control", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Even an administrator of another tenant does not see the session
+	if code := control(); code != http.StatusNotFound {
+		t.Errorf("Expected 404 across tenants, got %d", code)
+	}
+	if session.Status == workflow.SessionPaused {
+		t.Error("Expected the session of another tenant to be untouched")
+	}
+
+	current = &auth.User{Username: "owner", TenantID: owner, IsAdmin: true}
+	if code := control(); code != http.StatusOK {
+		t.Errorf("Expected 200 within the tenant, got %d", code)
+	}
+}
+
+func TestGroupHandler_SetMemberAcrossTenants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	members := mocks.NewAuthMockRepository()
+	outsider := &auth.User{Username: "outsider"}
+	_ = members.CreateUser(tenant.WithID(context.Background(), uuid.New()), outsider)
+
+	h := NewGroupHandler(mocks.NewGroupMockRepository())
+	h.Members = members
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		authz.SetUser(c, &auth.User{Username: "admin", TenantID: tenant.DefaultID, IsAdmin: true})
+	})
+	r.PUT("/groups/:id/members", h.SetMember)
+
+	body, _ := json.Marshal(SetMemberRequest{Username: "outsider", Role: auth.RoleViewer})
+	req, _ := http.NewRequest(http.MethodPut, "/groups/"+uuid.NewString()+"/members", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a user of another tenant, got %d", w.Code)
+	}
+}
@@ -8,13 +8,19 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
 	"github.com/hrygo/council/internal/api/ws"
 	"github.com/hrygo/council/internal/core/agent"
+	"github.com/hrygo/council/internal/core/auth"
 	"github.com/hrygo/council/internal/core/memory"
 	"github.com/hrygo/council/internal/core/middleware"
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
 	"github.com/hrygo/council/internal/council"
 	"github.com/hrygo/council/internal/infrastructure/llm"
+	"github.com/hrygo/council/internal/infrastructure/search"
 )
 
 type WorkflowHandler struct {
@@ -25,6 +31,10 @@ type WorkflowHandler struct {
 	SessionRepo   workflow.SessionRepository
 	FileRepo      workflow.SessionFileRepository
 	WorkflowRepo  workflow.Repository
+	Tools         *tools.Registry     // Shared tool registry; nil uses the built-in tools
+	SearchClient  search.SearchClient // Evidence source for fact checks; optional
+	Policy        *authz.Policy       // Group roles and audit log; nil allows everything
+	Ledger        workflow.CostLedger // Records every LLM call for spend reports; optional
 }
 
 var (
@@ -45,8 +55,9 @@ func NewWorkflowHandler(hub *ws.Hub, agentRepo agent.Repository, registry *llm.R
 }
 
 type ExecuteRequest struct {
-	Graph *workflow.GraphDefinition `json:"graph"`
-	Input map[string]interface{}    `json:"input"`
+	Graph  *workflow.GraphDefinition `json:"graph"`
+	Input  map[string]interface{}    `json:"input"`
+	Budget *workflow.Budgets         `json:"budget"` // Optional session and group spend limits
 }
 
 func (h *WorkflowHandler) Execute(c *gin.Context) {
@@ -71,6 +82,22 @@ func (h *WorkflowHandler) Execute(c *gin.Context) {
 			log.Printf("[Workflow] Warning: Client used deprecated 'group_id' parameter. Please update to 'group_uuid'.")
 		}
 	}
+	user := authz.UserFrom(c)
+	if err := h.Policy.Check(c.Request.Context(), user, groupID, auth.RoleEditor); err != nil {
+		c.JSON(authz.Status(err), gin.H{"error": err.Error()})
+		return
+	}
+	if req.Budget != nil {
+		if req.Budget.Group != nil && groupID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "a group budget requires group_uuid"})
+			return
+		}
+		session.SetBudgets(*req.Budget)
+		if err := h.loadGroupSpend(c.Request.Context(), session, groupID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
 	workflowID := ""
 	if req.Graph != nil {
 		workflowID = req.Graph.ID
@@ -93,16 +120,101 @@ func (h *WorkflowHandler) Execute(c *gin.Context) {
 			}
 		}
 	}
-	session.Start(context.Background())
+	session.Start(tenant.Detach(c.Request.Context()))
 
 	if err := h.SessionRepo.Create(c.Request.Context(), session, groupID, workflowID); err != nil {
 		log.Printf("[Workflow] Failed to persist session: %v", err)
 		// We continue anyway for MVP but ideally fail here
 	}
+	h.Policy.Audit(c.Request.Context(), user, "session.start", groupID, session.ID, map[string]interface{}{"workflow_uuid": workflowID})
+
+	engine := h.newEngine(session)
+	go h.drive(engine, engine.Run)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"session_uuid": session.ID,
+		"status":       "started",
+	})
+}
+
+type ControlRequest struct {
+	Action string            `json:"action" binding:"required,oneof=pause resume stop"`
+	Budget *workflow.Budgets `json:"budget,omitempty"` // Replaces the given limits, e.g. to resume past a budget
+}
+
+func (h *WorkflowHandler) Control(c *gin.Context) {
+	id := c.Param("id")
+	var req ControlRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Create Engine
+	result, err := h.control(c.Request.Context(), authz.UserFrom(c), id, req)
+	if err != nil {
+		c.JSON(commandStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// control applies a pause/resume/stop action to an active session.
+func (h *WorkflowHandler) control(ctx context.Context, user *auth.User, id string, req ControlRequest) (gin.H, error) {
+	engine := activeEngine(ctx, id)
+	if engine == nil {
+		return nil, ErrSessionNotActive
+	}
+	session := engine.Session
+
+	if req.Budget != nil {
+		budgets := session.Budgets().Merge(*req.Budget)
+		session.SetBudgets(budgets)
+		if h.SessionRepo != nil {
+			if err := h.SessionRepo.UpdateBudgets(ctx, id, budgets); err != nil {
+				log.Printf("[Workflow] Failed to persist budgets of session %s: %v", id, err)
+			}
+		}
+	}
+
+	switch req.Action {
+	case "pause":
+		session.Pause()
+	case "resume":
+		session.Resume()
+	case "stop":
+		session.Stop()
+	}
+	h.audit(ctx, user, "session.control", session, map[string]interface{}{"action": req.Action, "budget": req.Budget})
+
+	return gin.H{
+		"session_uuid": id,
+		"status":       session.Status,
+		"action":       req.Action,
+		"budget":       session.Budgets(),
+	}, nil
+}
+
+// loadGroupSpend records what the session's group spent in its other sessions,
+// which counts against the group budget.
+func (h *WorkflowHandler) loadGroupSpend(ctx context.Context, session *workflow.Session, groupID string) error {
+	if session.Budgets().Group == nil || groupID == "" || h.SessionRepo == nil {
+		return nil
+	}
+	spent, err := h.SessionRepo.GroupUsage(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	session.SetGroupSpend(spent.Sub(session.Usage()))
+	return nil
+}
+
+// newEngine builds an engine configured for Council workflows and registers it as active.
+func (h *WorkflowHandler) newEngine(session *workflow.Session) *workflow.Engine {
 	engine := workflow.NewEngine(session)
 	engine.SetSessionRepository(h.SessionRepo)
+	if h.Ledger != nil {
+		engine.SetCostLedger(h.Ledger)
+	}
 	enginesMu.Lock()
 	activeEngines[session.ID] = engine
 	enginesMu.Unlock()
@@ -112,7 +224,14 @@ func (h *WorkflowHandler) Execute(c *gin.Context) {
 	engine.MergeStrategy = &council.CouncilMergeStrategy{}
 
 	// Configure Factory for Council Application Logic (SPEC-1303)
-	engine.NodeFactory = council.NewCouncilNodeFactory(h.AgentRepo, h.Registry, h.MemoryManager)
+	factory := council.NewCouncilNodeFactory(h.AgentRepo, h.Registry, h.MemoryManager)
+	if h.Tools != nil {
+		factory.SetToolRegistry(h.Tools)
+	}
+	if h.SearchClient != nil {
+		factory.SetSearchClient(h.SearchClient)
+	}
+	engine.NodeFactory = factory
 
 	// First, create memService as it's a dependency for NodeDependencies now.
 	// Note: We use global getters here for simplicity, but ideally these would be in WorkflowHandler
@@ -120,123 +239,97 @@ func (h *WorkflowHandler) Execute(c *gin.Context) {
 		middleware.NewCircuitBreaker(10),                // Logic Circuit Breaker (Depth > 10)
 		middleware.NewFactCheckTrigger(),                // Anti-Hallucination
 		middleware.NewMemoryMiddleware(h.MemoryManager), // Memory Persistence
+		middleware.NewBudgetGuard(engine.StreamChannel), // Session and group spend limits
 	}
+	return engine
+}
 
-	// Run in Goroutine
-	go func() {
-		log.Printf("[Workflow] Starting execution for session %s", session.ID)
-		defer func() {
-			if r := recover(); r != nil {
-				log.Printf("[Workflow] PANIC in session %s: %v", session.ID, r)
-			}
-			session.Complete()
+// drive executes the engine until it settles (including suspended nodes that are
+// later resumed), bridges its stream to the WS hub and persists the final status.
+// run is either engine.Run for new sessions or engine.Recover for restored ones.
+func (h *WorkflowHandler) drive(engine *workflow.Engine, run func(ctx context.Context) error) {
+	session := engine.Session
+	log.Printf("[Workflow] Starting execution for session %s", session.ID)
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Workflow] PANIC in session %s: %v", session.ID, r)
+		}
+		session.Complete()
 
-			// Cleanup active engine
-			enginesMu.Lock()
-			delete(activeEngines, session.ID)
-			enginesMu.Unlock()
+		// Cleanup active engine
+		enginesMu.Lock()
+		delete(activeEngines, session.ID)
+		enginesMu.Unlock()
 
-			log.Printf("[Workflow] Session %s completed and removed from active list", session.ID)
-		}()
+		log.Printf("[Workflow] Session %s completed and removed from active list", session.ID)
+	}()
 
-		// Bridge Engine Stream -> WS Hub
-		// We need to modify Engine to allow tapping or we just read from the stream channel
-		// Engine exposes StreamChannel
-		go func() {
-			for event := range engine.StreamChannel {
-				// Augment event with SessionID?
-				event.Data["session_uuid"] = session.ID
+	// Bridge Engine Stream -> WS Hub
+	// We need to modify Engine to allow tapping or we just read from the stream channel
+	// Engine exposes StreamChannel
+	go func() {
+		for event := range engine.StreamChannel {
+			// Augment event with SessionID?
+			if event.Data == nil {
+				event.Data = make(map[string]interface{})
+			}
+			event.Data["session_uuid"] = session.ID
 
+			if h.Hub != nil {
 				h.Hub.Broadcast(event)
 			}
-		}()
-
-		err := engine.Run(session.Context())
+		}
+	}()
 
-		status := "completed"
-		if err != nil {
-			log.Printf("[Workflow] Execution error for session %s: %v", session.ID, err)
-			session.SetStatus(workflow.SessionFailed)
-			status = "failed"
+	err := run(session.Context())
+	if err == nil {
+		// Keep the engine active while nodes (e.g. HumanReview) wait to be resumed
+		err = engine.Wait(session.Context())
+	}
 
-			// Emit error event
-			engine.StreamChannel <- workflow.StreamEvent{
-				Type:      "execution:error",
-				Timestamp: time.Now(),
-				Data:      map[string]interface{}{"error": err.Error()},
-			}
-		}
+	status := "completed"
+	if err != nil {
+		log.Printf("[Workflow] Execution error for session %s: %v", session.ID, err)
+		session.SetStatus(workflow.SessionFailed)
+		status = "failed"
 
-		// Emit completion/final event
+		// Emit error event
 		engine.StreamChannel <- workflow.StreamEvent{
-			Type:      "execution:completed",
+			Type:      "execution:error",
 			Timestamp: time.Now(),
-			Data:      map[string]interface{}{"status": status},
+			Data:      map[string]interface{}{"error": err.Error()},
 		}
-
-		// Persist final status to DB
-		if h.SessionRepo != nil {
-			if err := h.SessionRepo.UpdateStatus(context.Background(), session.ID, session.Status); err != nil {
-				log.Printf("[WorkflowHandler] Failed to update status: %v", err)
-			}
-		}
-
-		close(engine.StreamChannel)
-	}()
-
-	c.JSON(http.StatusAccepted, gin.H{
-		"session_uuid": session.ID,
-		"status":       "started",
-	})
-}
-
-type ControlRequest struct {
-	Action string `json:"action" binding:"required,oneof=pause resume stop"`
-}
-
-func (h *WorkflowHandler) Control(c *gin.Context) {
-	id := c.Param("id")
-	var req ControlRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	} else {
+		session.SetStatus(workflow.SessionCompleted)
 	}
 
-	// In a real app, we need a SessionRepository or global SessionRegistry to look up active sessions.
-	// For MVP, we don't have a global registry yet. The session created in Execute is lost reference-wise unless stored.
-	// We need to fix this by adding a SessionRegistry to WorkflowHandler.
-
-	// FIX: This indicates a missing architectural piece. We need a way to retrieve running sessions.
-	// For now, I will add a TO-DO and mock response, but I must fix this to make it work.
-	// Strategy: Use a simple map in memory for active sessions.
-
-	engine := h.getEngine(id) // Helper method
-	if engine == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found or not active"})
-		return
+	// Emit completion/final event
+	engine.StreamChannel <- workflow.StreamEvent{
+		Type:      "execution:completed",
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"status": status},
 	}
-	session := engine.Session
 
-	switch req.Action {
-	case "pause":
-		session.Pause()
-	case "resume":
-		session.Resume()
-	case "stop":
-		session.Stop()
+	// Persist final status to DB
+	if h.SessionRepo != nil {
+		if err := h.SessionRepo.UpdateStatus(tenant.Detach(session.Context()), session.ID, session.Status); err != nil {
+			log.Printf("[WorkflowHandler] Failed to update status: %v", err)
+		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"session_uuid": id,
-		"status":       session.Status,
-		"action":       req.Action,
-	})
+	close(engine.StreamChannel)
 }
 
-func (h *WorkflowHandler) getEngine(sessionID string) *workflow.Engine {
+// activeEngine returns the running engine of a session, if the session is
+// active and belongs to the tenant of ctx.
+func activeEngine(ctx context.Context, sessionID string) *workflow.Engine {
 	enginesMu.RLock()
-	defer enginesMu.RUnlock()
-	return activeEngines[sessionID]
+	engine := activeEngines[sessionID]
+	enginesMu.RUnlock()
+	if engine == nil || !tenant.Same(ctx, engine.Session.Context()) {
+		return nil
+	}
+	return engine
 }
 
 type SignalRequest struct {
@@ -252,19 +345,25 @@ func (h *WorkflowHandler) Signal(c *gin.Context) {
 		return
 	}
 
-	engine := h.getEngine(id)
-	if engine == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+	result, err := h.signal(c.Request.Context(), authz.UserFrom(c), id, req)
+	if err != nil {
+		c.JSON(commandStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	session := engine.Session
+	c.JSON(http.StatusOK, result)
+}
 
-	if err := session.SendSignal(req.NodeID, req.Payload); err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		return
+// signal delivers a payload to a node waiting on the session's signal channel.
+func (h *WorkflowHandler) signal(ctx context.Context, user *auth.User, id string, req SignalRequest) (gin.H, error) {
+	engine := activeEngine(ctx, id)
+	if engine == nil {
+		return nil, ErrSessionNotActive
 	}
-
-	c.JSON(http.StatusOK, gin.H{"status": "signal_sent"})
+	if err := engine.Session.SendSignal(req.NodeID, req.Payload); err != nil {
+		return nil, err
+	}
+	h.audit(ctx, user, "session.signal", engine.Session, map[string]interface{}{"node_id": req.NodeID})
+	return gin.H{"status": "signal_sent"}, nil
 }
 
 type ReviewRequest struct {
@@ -281,41 +380,53 @@ func (h *WorkflowHandler) Review(c *gin.Context) {
 		return
 	}
 
-	engine := h.getEngine(id)
-	if engine == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found or not active"})
+	result, err := h.review(c.Request.Context(), authz.UserFrom(c), id, req, c.GetHeader("Date"))
+	if err != nil {
+		c.JSON(commandStatus(err), gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, result)
+}
 
-	// Logic for Human Review Resume
-	// We need to fetch the node to confirm it is suspended?
-	// The Engine.ResumeNode checks this.
+// review resumes a suspended HumanReview node with the reviewer's decision.
+func (h *WorkflowHandler) review(ctx context.Context, user *auth.User, id string, req ReviewRequest, timestamp string) (gin.H, error) {
+	engine := activeEngine(ctx, id)
+	if engine == nil {
+		return nil, ErrSessionNotActive
+	}
+	if user == nil {
+		user = auth.LocalUser
+	}
 
-	// Construct output payload based on Action
+	// Construct output payload based on Action; Engine.ResumeNode checks
+	// that the node is suspended. The reviewer identity is set after the
+	// patch data so that clients cannot override it.
 	output := map[string]interface{}{
 		"review_action": req.Action,
-		"reviewer":      "human", // Placeholder
-		"timestamp":     c.GetHeader("Date"),
+		"timestamp":     timestamp,
 	}
-	if req.Data != nil {
-		for k, v := range req.Data {
-			output[k] = v
-		}
+	for k, v := range req.Data {
+		output[k] = v
 	}
-
-	if err := engine.ResumeNode(c.Request.Context(), req.NodeID, output); err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		return
+	output["reviewer"] = user.Name()
+	if user.ID != uuid.Nil {
+		output["reviewer_uuid"] = user.ID.String()
+	} else {
+		delete(output, "reviewer_uuid")
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "resumed"})
+	if err := engine.ResumeNode(ctx, req.NodeID, output); err != nil {
+		return nil, err
+	}
+	h.audit(ctx, user, "session.review", engine.Session, map[string]interface{}{"node_id": req.NodeID, "action": req.Action})
+	return gin.H{"status": "resumed"}, nil
 }
 
 func (h *WorkflowHandler) GetSession(c *gin.Context) {
 	id := c.Param("id")
 
 	// 1. Prefer Active Engine State (Live Source of Truth)
-	if engine := h.getEngine(id); engine != nil {
+	if engine := activeEngine(c.Request.Context(), id); engine != nil {
 		engine.Mu.RLock()
 		status := engine.Session.Status
 		startTime := engine.Session.StartTime
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/hrygo/council/internal/api/ws"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/workflow"
+)
+
+var (
+	_ ws.CommandHandler    = (*WorkflowHandler)(nil)
+	_ ws.SessionAuthorizer = (*WorkflowHandler)(nil)
+)
+
+var (
+	// ErrSessionNotActive is returned by session commands when no engine runs the session.
+	ErrSessionNotActive = errors.New("session not found or not active")
+	// ErrSessionNotFound is returned when a session is neither active nor persisted.
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// commandRoles is the group role each session command requires.
+var commandRoles = map[string]auth.Role{
+	"control": auth.RoleEditor,
+	"signal":  auth.RoleEditor,
+	"review":  auth.RoleReviewer,
+}
+
+// commandStatus maps a session command error to its HTTP status.
+func commandStatus(err error) int {
+	if errors.Is(err, ErrSessionNotActive) {
+		return http.StatusNotFound
+	}
+	return http.StatusConflict
+}
+
+// HandleCommand implements ws.CommandHandler: it runs the control, review and
+// signal commands received over the WebSocket with the same logic as the REST
+// endpoints. data is the command's JSON body, as for the REST request.
+func (h *WorkflowHandler) HandleCommand(ctx context.Context, user *auth.User, cmd, sessionID string, data json.RawMessage) (interface{}, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session_uuid required")
+	}
+	role, ok := commandRoles[cmd]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q", cmd)
+	}
+	if err := h.authorizeSession(ctx, user, sessionID, role); err != nil {
+		return nil, err
+	}
+
+	switch cmd {
+	case "control":
+		var req ControlRequest
+		if err := decodeCommand(data, &req); err != nil {
+			return nil, err
+		}
+		return h.control(ctx, user, sessionID, req)
+	case "review":
+		var req ReviewRequest
+		if err := decodeCommand(data, &req); err != nil {
+			return nil, err
+		}
+		return h.review(ctx, user, sessionID, req, time.Now().UTC().Format(http.TimeFormat))
+	default:
+		var req SignalRequest
+		if err := decodeCommand(data, &req); err != nil {
+			return nil, err
+		}
+		return h.signal(ctx, user, sessionID, req)
+	}
+}
+
+// AuthorizeSubscribe implements ws.SessionAuthorizer: subscribers need the
+// viewer role in the session's group.
+func (h *WorkflowHandler) AuthorizeSubscribe(ctx context.Context, user *auth.User, sessionID string) error {
+	return h.authorizeSession(ctx, user, sessionID, auth.RoleViewer)
+}
+
+func (h *WorkflowHandler) authorizeSession(ctx context.Context, user *auth.User, sessionID string, role auth.Role) error {
+	if h.Policy == nil {
+		return nil
+	}
+	groupID, err := h.sessionGroup(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	return h.Policy.Check(ctx, user, groupID, role)
+}
+
+// SessionGroup is an authz.GroupResolver for routes with a session :id.
+func (h *WorkflowHandler) SessionGroup(c *gin.Context) (string, error) {
+	return h.sessionGroup(c.Request.Context(), c.Param("id"))
+}
+
+// sessionGroup returns the group a session runs in, preferring the live engine.
+func (h *WorkflowHandler) sessionGroup(ctx context.Context, sessionID string) (string, error) {
+	if engine := activeEngine(ctx, sessionID); engine != nil {
+		engine.Mu.RLock()
+		defer engine.Mu.RUnlock()
+		return inputGroup(engine.Session.Inputs), nil
+	}
+	if h.SessionRepo == nil {
+		return "", ErrSessionNotFound
+	}
+	entity, err := h.SessionRepo.Get(ctx, sessionID)
+	if err != nil {
+		return "", ErrSessionNotFound
+	}
+	return entity.GroupID, nil
+}
+
+// inputGroup reads the group from session inputs, accepting the legacy group_id.
+func inputGroup(inputs map[string]interface{}) string {
+	if groupID, _ := inputs["group_uuid"].(string); groupID != "" {
+		return groupID
+	}
+	groupID, _ := inputs["group_id"].(string)
+	return groupID
+}
+
+// audit records a session action by user in the audit log.
+func (h *WorkflowHandler) audit(ctx context.Context, user *auth.User, action string, session *workflow.Session, details map[string]interface{}) {
+	if h.Policy == nil {
+		return
+	}
+	h.Policy.Audit(ctx, user, action, inputGroup(session.Inputs), session.ID, details)
+}
+
+// decodeCommand decodes and validates a command body like ShouldBindJSON.
+func decodeCommand(data json.RawMessage, req interface{}) error {
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("invalid command data: %w", err)
+	}
+	return binding.Validator.ValidateStruct(req)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func TestWorkflowHandler_HandleCommand(t *testing.T) {
+	h := NewWorkflowHandler(nil, nil, nil, nil, nil, nil, nil)
+
+	graph := &workflow.GraphDefinition{
+		ID:          "test-graph",
+		Nodes:       map[string]*workflow.Node{"start": {ID: "start", Type: workflow.NodeTypeStart}},
+		StartNodeID: "start",
+	}
+	session := workflow.NewSession(graph, nil)
+	session.Start(context.Background())
+	enginesMu.Lock()
+	activeEngines[session.ID] = workflow.NewEngine(session)
+	enginesMu.Unlock()
+	defer func() {
+		enginesMu.Lock()
+		delete(activeEngines, session.ID)
+		enginesMu.Unlock()
+	}()
+
+	result, err := h.HandleCommand(context.Background(), nil, "control", session.ID, json.RawMessage(`{"session_uuid": "x", "action": "pause"}`))
+	if err != nil {
+		t.Fatalf("control failed: %v", err)
+	}
+	if session.Status != workflow.SessionPaused {
+		t.Errorf("Expected session to be paused, got %s", session.Status)
+	}
+	if result.(gin.H)["action"] != "pause" {
+		t.Errorf("Unexpected result: %v", result)
+	}
+
+	if _, err := h.HandleCommand(context.Background(), nil, "control", session.ID, json.RawMessage(`{"action": "explode"}`)); err == nil {
+		t.Error("Expected validation error for unknown action")
+	}
+	if _, err := h.HandleCommand(context.Background(), nil, "review", session.ID, json.RawMessage(`{"node_id": "start", "action": "approve"}`)); err == nil {
+		t.Error("Expected error reviewing a node that is not suspended")
+	}
+	if _, err := h.HandleCommand(context.Background(), nil, "signal", "missing", json.RawMessage(`{"node_id": "n", "payload": 1}`)); !errors.Is(err, ErrSessionNotActive) {
+		t.Errorf("Expected ErrSessionNotActive, got %v", err)
+	}
+	if _, err := h.HandleCommand(context.Background(), nil, "launch", session.ID, nil); err == nil {
+		t.Error("Expected unknown command error")
+	}
+}
+
+func TestWorkflowHandler_CommandRoles(t *testing.T) {
+	authRepo := mocks.NewAuthMockRepository()
+	sessionRepo := mocks.NewSessionMockRepository()
+	h := NewWorkflowHandler(nil, nil, nil, nil, sessionRepo, nil, nil)
+	h.Policy = &authz.Policy{Repo: authRepo}
+
+	groupID := uuid.New()
+	reviewer := &auth.User{Username: "rita"}
+	_ = authRepo.CreateUser(context.Background(), reviewer)
+	_ = authRepo.SetMembership(context.Background(), &auth.Membership{GroupID: groupID, UserID: reviewer.ID, Role: auth.RoleReviewer})
+
+	graph := &workflow.GraphDefinition{
+		ID:          "test-graph",
+		Nodes:       map[string]*workflow.Node{"start": {ID: "start", Type: workflow.NodeTypeStart}},
+		StartNodeID: "start",
+	}
+	session := workflow.NewSession(graph, map[string]interface{}{"group_uuid": groupID.String()})
+	session.Start(context.Background())
+	enginesMu.Lock()
+	activeEngines[session.ID] = workflow.NewEngine(session)
+	enginesMu.Unlock()
+	defer func() {
+		enginesMu.Lock()
+		delete(activeEngines, session.ID)
+		enginesMu.Unlock()
+	}()
+
+	ctx := context.Background()
+	if err := h.AuthorizeSubscribe(ctx, reviewer, session.ID); err != nil {
+		t.Errorf("Expected reviewer to subscribe, got %v", err)
+	}
+	if err := h.AuthorizeSubscribe(ctx, &auth.User{ID: uuid.New(), Username: "eve"}, session.ID); !errors.Is(err, authz.ErrForbidden) {
+		t.Errorf("Expected non-member subscription to be forbidden, got %v", err)
+	}
+	sessionRepo.Err = errors.New("no rows")
+	if err := h.AuthorizeSubscribe(ctx, reviewer, "missing"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+	sessionRepo.Err = nil
+
+	if _, err := h.HandleCommand(ctx, reviewer, "control", session.ID, json.RawMessage(`{"action": "pause"}`)); !errors.Is(err, authz.ErrForbidden) {
+		t.Errorf("Expected reviewer to be denied control, got %v", err)
+	}
+	if session.Status == workflow.SessionPaused {
+		t.Error("Expected the forbidden command not to run")
+	}
+	// Allowed past authorization; fails because the node is not suspended
+	if _, err := h.HandleCommand(ctx, reviewer, "review", session.ID, json.RawMessage(`{"node_id": "start", "action": "approve"}`)); errors.Is(err, authz.ErrForbidden) {
+		t.Errorf("Expected reviewer to be allowed to review, got %v", err)
+	}
+}
@@ -71,6 +71,31 @@ func TestWorkflowHandler_Control(t *testing.T) {
 		}
 	})
 
+	t.Run("ResumeWithBudget", func(t *testing.T) {
+		session.SetBudgets(workflow.Budgets{
+			Session: &workflow.Budget{MaxTokens: 1000},
+			Group:   &workflow.Budget{MaxCostUSD: 5},
+		})
+		session.Pause()
+
+		payload := ControlRequest{Action: "resume", Budget: &workflow.Budgets{Session: &workflow.Budget{MaxTokens: 3000}}}
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest(http.MethodPost, "/sessions/"+session.ID+"/control", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", w.Code)
+		}
+		if session.Status != workflow.SessionRunning {
+			t.Errorf("Expected session to be Running, got %s", session.Status)
+		}
+		budgets := sessionRepo.Budgets[session.ID]
+		if budgets.Session.MaxTokens != 3000 || budgets.Group.MaxCostUSD != 5 {
+			t.Errorf("Expected the raised session limit to be persisted next to the group limit, got %+v", budgets)
+		}
+	})
+
 	t.Run("Stop", func(t *testing.T) {
 		payload := ControlRequest{Action: "stop"}
 		body, _ := json.Marshal(payload)
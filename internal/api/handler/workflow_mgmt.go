@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/agent"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/infrastructure/llm"
 )
@@ -16,8 +18,9 @@ import (
 // WorkflowMgmtHandler handles CRUD operations for workflows
 // WorkflowMgmtHandler handles CRUD operations for workflows
 type WorkflowMgmtHandler struct {
-	Repo     workflow.Repository
-	Registry *llm.Registry
+	Repo      workflow.Repository
+	Registry  *llm.Registry
+	AgentRepo agent.Repository // Resolves agent models and personas for estimates; optional
 }
 
 func NewWorkflowMgmtHandler(repo workflow.Repository, registry *llm.Registry) *WorkflowMgmtHandler {
@@ -148,6 +151,7 @@ Output STRICT JSON only.`
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get default LLM provider"})
 		return
 	}
+	provider = h.Registry.WithFallbacks(provider, "default", nil)
 
 	resp, err := provider.Generate(ctx, &llm.CompletionRequest{
 		Messages: []llm.Message{
@@ -187,32 +191,47 @@ Output STRICT JSON only.`
 	})
 }
 
+// EstimateRequest carries the workflow to estimate and the execute input
+// (documents, proposal) its prompts will contain. A bare graph is accepted too.
+type EstimateRequest struct {
+	Graph *workflow.GraphDefinition `json:"graph"`
+	Input map[string]interface{}    `json:"input"`
+}
+
 // EstimateCost calculates the estimated cost of a workflow
 func (h *WorkflowMgmtHandler) EstimateCost(c *gin.Context) {
-	// Support both: POST with JSON body (draft workflow) OR GET /:id (saved workflow)
-	// Spec says: POST /api/v1/workflows/:id/estimate (if ID exists)
+	// Support both: POST with JSON body (draft workflow) OR POST /:id (saved workflow)
 	// But usually we want to estimate *before* saving edits.
-	// Let's support POST on collection /workflows/estimate with body.
+	var req EstimateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// If ID is passed in URL:
 	id := c.Param("id")
-	var graph workflow.GraphDefinition
-
 	if id != "" {
 		g, err := h.Repo.Get(c.Request.Context(), id)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow not found"})
 			return
 		}
-		graph = *g
-	} else {
-		// Expect body
-		if err := c.ShouldBindJSON(&graph); err != nil {
+		req.Graph = g
+	} else if req.Graph == nil {
+		// Expect the graph itself as body
+		var graph workflow.GraphDefinition
+		if err := c.ShouldBindBodyWith(&graph, binding.JSON); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		req.Graph = &graph
 	}
 
-	estimate := workflow.EstimateWorkflowCost(&graph)
-	c.JSON(http.StatusOK, estimate)
+	estimator := &workflow.Estimator{Agents: h.AgentRepo, CountTokens: llm.CountTokens}
+	if h.Registry != nil {
+		estimator.DefaultModel = h.Registry.GetDefaultModel()
+	}
+	c.JSON(http.StatusOK, estimator.Estimate(c.Request.Context(), req.Graph, req.Input))
 }
@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/agent"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/infrastructure/llm"
 	"github.com/hrygo/council/internal/infrastructure/mocks"
@@ -136,6 +139,44 @@ func TestWorkflowMgmtHandler_EstimateCost(t *testing.T) {
 		}
 	})
 
+	t.Run("AgentsAndInput", func(t *testing.T) {
+		agents := mocks.NewAgentMockRepository()
+		writer := &agent.Agent{ID: uuid.New(), Name: "Writer", PersonaPrompt: "You write.", ModelConfig: agent.ModelConfig{Model: "gpt-4"}}
+		agents.Agents[writer.ID] = writer
+		handler.AgentRepo = agents
+		defer func() { handler.AgentRepo = nil }()
+
+		estimate := func(input map[string]interface{}) workflow.CostEstimate {
+			body, _ := json.Marshal(EstimateRequest{
+				Graph: &workflow.GraphDefinition{Nodes: map[string]*workflow.Node{
+					"n1": {ID: "n1", Type: workflow.NodeTypeAgent, Properties: map[string]interface{}{"agent_uuid": writer.ID.String()}},
+				}},
+				Input: input,
+			})
+			req, _ := http.NewRequest("POST", "/workflows/estimate", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp workflow.CostEstimate
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			return resp
+		}
+
+		short := estimate(nil)
+		long := estimate(map[string]interface{}{"document_content": strings.Repeat("A long document. ", 500)})
+		if short.NodeBreakdown["n1"].Model != "gpt-4" || short.AgentBreakdown["Writer"] == 0 {
+			t.Errorf("Expected the agent's model to be priced, got %+v", short)
+		}
+		if long.TotalTokens <= short.TotalTokens+1000 {
+			t.Errorf("Expected the input document to be counted, got %d vs %d tokens", long.TotalTokens, short.TotalTokens)
+		}
+		if long.CostRange.Min >= long.CostRange.Max {
+			t.Errorf("Expected a cost range, got %+v", long.CostRange)
+		}
+	})
+
 	t.Run("Saved", func(t *testing.T) {
 		mockRepo.GetFunc = func(ctx context.Context, id string) (*workflow.GraphDefinition, error) {
 			return &workflow.GraphDefinition{ID: id, Nodes: map[string]*workflow.Node{}}, nil
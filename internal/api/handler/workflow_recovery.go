@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/core/workflow"
+)
+
+// RecoverSessions reloads sessions that were running or paused when the backend
+// stopped and continues them from their frontier of incomplete nodes.
+// Sessions waiting on a HumanReview node become reviewable again.
+// It only recovers sessions of the tenant of ctx, and returns the number of
+// sessions that were resumed.
+func (h *WorkflowHandler) RecoverSessions(ctx context.Context) (int, error) {
+	if h.SessionRepo == nil {
+		return 0, fmt.Errorf("session repository not configured")
+	}
+
+	entities, err := h.SessionRepo.ListRecoverable(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for _, entity := range entities {
+		if activeEngine(ctx, entity.ID) != nil {
+			continue // Already active in this process
+		}
+
+		if entity.Graph == nil && entity.WorkflowID != "" && h.WorkflowRepo != nil {
+			// Sessions created before graph snapshots existed: fall back to the stored workflow
+			if graph, err := h.WorkflowRepo.Get(ctx, entity.WorkflowID); err == nil {
+				entity.Graph = graph
+			}
+		}
+		if entity.Graph == nil {
+			log.Printf("[Recovery] Session %s has no graph definition, marking as failed", entity.ID)
+			if err := h.SessionRepo.UpdateStatus(ctx, entity.ID, workflow.SessionFailed); err != nil {
+				log.Printf("[Recovery] Failed to update status of session %s: %v", entity.ID, err)
+			}
+			continue
+		}
+
+		session := workflow.RestoreSession(entity)
+		session.SetFileRepository(h.FileRepo)
+		if err := h.loadGroupSpend(ctx, session, entity.GroupID); err != nil {
+			log.Printf("[Recovery] Failed to load group spend of session %s: %v", entity.ID, err)
+		}
+		session.Start(tenant.Detach(ctx))
+		if entity.Status == workflow.SessionPaused {
+			session.Pause()
+		}
+
+		engine := h.newEngine(session)
+		go h.drive(engine, engine.Recover)
+
+		log.Printf("[Recovery] Resumed session %s (status=%s)", entity.ID, entity.Status)
+		recovered++
+	}
+
+	return recovered, nil
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func TestWorkflowHandler_RecoverSessions(t *testing.T) {
+	sessionRepo := mocks.NewSessionMockRepository()
+	h := NewWorkflowHandler(nil, nil, nil, nil, sessionRepo, nil, nil)
+
+	graph := &workflow.GraphDefinition{
+		ID:          "test-wf",
+		StartNodeID: "start",
+		Nodes: map[string]*workflow.Node{
+			"start":  {ID: "start", Type: workflow.NodeTypeStart, NextIDs: []string{"review"}},
+			"review": {ID: "review", Type: workflow.NodeTypeHumanReview},
+		},
+	}
+	sessionRepo.Recoverable = []*workflow.SessionEntity{
+		{
+			ID:           "recoverable-review",
+			Status:       workflow.SessionRunning,
+			Graph:        graph,
+			NodeStatuses: map[string]workflow.NodeStatus{"start": workflow.StatusCompleted, "review": workflow.StatusSuspended},
+		},
+		{ID: "recoverable-no-graph", Status: workflow.SessionRunning},
+	}
+
+	n, err := h.RecoverSessions(context.Background())
+	if err != nil {
+		t.Fatalf("RecoverSessions failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 recovered session, got %d", n)
+	}
+
+	engine := activeEngine(context.Background(), "recoverable-review")
+	if engine == nil {
+		t.Fatal("expected recovered session to be active")
+	}
+	if engine.GetStatus("review") != workflow.StatusSuspended {
+		t.Errorf("expected review node to stay suspended, got %s", engine.GetStatus("review"))
+	}
+	if activeEngine(context.Background(), "recoverable-no-graph") != nil {
+		t.Error("expected session without graph not to be recovered")
+	}
+
+	// The suspended session must remain reviewable after recovery
+	time.Sleep(50 * time.Millisecond)
+	if activeEngine(context.Background(), "recoverable-review") == nil {
+		t.Error("expected suspended session to stay active while awaiting review")
+	}
+
+	engine.Session.Stop()
+}
@@ -2,13 +2,18 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/api/authz"
 	"github.com/hrygo/council/internal/api/ws"
+	"github.com/hrygo/council/internal/core/auth"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/infrastructure/llm"
 	"github.com/hrygo/council/internal/infrastructure/mocks"
@@ -65,12 +70,92 @@ func TestWorkflowHandler_Execute(t *testing.T) {
 	}
 }
 
+func TestWorkflowHandler_ExecuteWithBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sessionRepo := mocks.NewSessionMockRepository()
+	sessionRepo.GroupSpend = workflow.Usage{PromptTokens: 4000, CostUSD: 0.4}
+	h := NewWorkflowHandler(nil, mocks.NewAgentMockRepository(), nil, nil, sessionRepo, nil, nil)
+
+	router := gin.New()
+	router.POST("/execute", h.Execute)
+	execute := func(req ExecuteRequest) int {
+		body, _ := json.Marshal(req)
+		httpReq, _ := http.NewRequest("POST", "/execute", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		return w.Code
+	}
+
+	// End-only graph: no node reaches the budget guard's pause
+	graph := &workflow.GraphDefinition{
+		ID:          "budget-wf",
+		Nodes:       map[string]*workflow.Node{"end": {ID: "end", Type: workflow.NodeTypeEnd}},
+		StartNodeID: "end",
+	}
+	groupBudget := &workflow.Budgets{Group: &workflow.Budget{MaxCostUSD: 1}}
+
+	if code := execute(ExecuteRequest{Graph: graph, Budget: groupBudget}); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a group budget without a group, got %d", code)
+	}
+
+	budget := &workflow.Budgets{Session: &workflow.Budget{MaxTokens: 2000}, Group: &workflow.Budget{MaxTokens: 5000}}
+	input := map[string]interface{}{"group_uuid": "g1"}
+	if code := execute(ExecuteRequest{Graph: graph, Input: input, Budget: budget}); code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d", code)
+	}
+	session := sessionRepo.CapturedSessions[0]
+	if got := session.Budgets(); got.Session.MaxTokens != 2000 || got.Group.MaxTokens != 5000 {
+		t.Errorf("Expected the budgets to be set before the session is persisted, got %+v", got)
+	}
+
+	// The group's earlier spend counts against the group limit
+	session.AddUsage(workflow.Usage{PromptTokens: 1000})
+	if exceeded := session.CheckBudget(); exceeded == nil || exceeded.Scope != "group" {
+		t.Errorf("Expected the group budget to be exceeded, got %v", exceeded)
+	}
+}
+
+func TestWorkflowHandler_ExecuteWithoutGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sessionRepo := mocks.NewSessionMockRepository()
+	h := NewWorkflowHandler(nil, mocks.NewAgentMockRepository(), nil, nil, sessionRepo, nil, nil)
+	authRepo := mocks.NewAuthMockRepository()
+	h.Policy = &authz.Policy{Repo: authRepo}
+	viewer := &auth.User{Username: "bob"}
+	_ = authRepo.CreateUser(context.Background(), viewer)
+	_ = authRepo.SetMembership(context.Background(), &auth.Membership{GroupID: uuid.New(), UserID: viewer.ID, Role: auth.RoleViewer})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { authz.SetUser(c, viewer) })
+	router.POST("/execute", h.Execute)
+
+	graph := &workflow.GraphDefinition{
+		ID:          "ungrouped-wf",
+		Nodes:       map[string]*workflow.Node{"end": {ID: "end", Type: workflow.NodeTypeEnd}},
+		StartNodeID: "end",
+	}
+	body, _ := json.Marshal(ExecuteRequest{Graph: graph})
+	req, _ := http.NewRequest("POST", "/execute", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden || len(sessionRepo.CapturedSessions) != 0 {
+		t.Errorf("Expected a viewer to be denied a workflow without a group, got %d", w.Code)
+	}
+}
+
 func TestWorkflowHandler_Review(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	sessionRepo := mocks.NewSessionMockRepository()
 	h := NewWorkflowHandler(nil, nil, nil, nil, sessionRepo, nil, nil)
+	authRepo := mocks.NewAuthMockRepository()
+	h.Policy = &authz.Policy{Repo: authRepo}
+	reviewer := &auth.User{Username: "alice"}
+	_ = authRepo.CreateUser(context.Background(), reviewer)
+	_ = authRepo.SetMembership(context.Background(), &auth.Membership{GroupID: uuid.New(), UserID: reviewer.ID, Role: auth.RoleReviewer})
 
 	router := gin.New()
+	router.Use(func(c *gin.Context) { authz.SetUser(c, reviewer) })
 	router.POST("/sessions/:id/review", h.Review)
 
 	// Setup active session in a suspended node
@@ -82,7 +167,9 @@ func TestWorkflowHandler_Review(t *testing.T) {
 	}
 	session := workflow.NewSession(graph, nil)
 	engine := workflow.NewEngine(session)
+	enginesMu.Lock()
 	activeEngines[session.ID] = engine
+	enginesMu.Unlock()
 
 	// To simulate suspended, we manually set status in map
 	engine.Status["review"] = workflow.StatusSuspended
@@ -90,7 +177,7 @@ func TestWorkflowHandler_Review(t *testing.T) {
 	payload := ReviewRequest{
 		NodeID: "review",
 		Action: "approve",
-		Data:   map[string]interface{}{"comment": "ok"},
+		Data:   map[string]interface{}{"comment": "ok", "reviewer": "mallory"},
 	}
 	body, _ := json.Marshal(payload)
 
@@ -99,6 +186,30 @@ func TestWorkflowHandler_Review(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Errorf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The review output carries the authenticated reviewer, not the client's claim
+	var resumed *workflow.StreamEvent
+	timeout := time.After(time.Second)
+	for resumed == nil {
+		select {
+		case event := <-engine.StreamChannel:
+			if event.Type == "node_resumed" {
+				resumed = &event
+			}
+		case <-timeout:
+			t.Fatal("Expected a node_resumed event")
+		}
+	}
+	if resumed.Data["reviewer"] != "alice" || resumed.Data["reviewer_uuid"] != reviewer.ID.String() {
+		t.Errorf("Unexpected reviewer in %+v", resumed.Data)
+	}
+	if resumed.Data["comment"] != "ok" {
+		t.Errorf("Expected review data to be kept, got %+v", resumed.Data)
+	}
+
+	if actions := authRepo.AuditActions(); len(actions) != 1 || actions[0] != "session.review" {
+		t.Errorf("Expected the review to be audited, got %v", actions)
 	}
 }
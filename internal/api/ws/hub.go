@@ -1,16 +1,40 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/core/workflow"
 )
 
+const (
+	// Time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+	// Time allowed to read the next pong message from the peer.
+	pongWait = 60 * time.Second
+	// Send pings to peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+	// Maximum message size allowed from peer.
+	maxMessageSize = 64 * 1024
+	// Events buffered per client before backpressure applies.
+	sendBufferSize = 256
+)
+
+// DefaultSendTimeout is how long Broadcast waits for a slow client to make
+// room in its buffer before disconnecting it.
+const DefaultSendTimeout = 5 * time.Second
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -18,25 +42,58 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
-// Hub maintains the set of active clients and broadcasts messages to the
+// Hub maintains the set of active clients and delivers each session's events
+// to the clients subscribed to that session (its room).
+//
+// Every session event gets a monotonic per-session Seq and is kept in the
+// room's ReplayBuffer (older events spill to Spill, if set), so that a client
+// reconnecting with last_seq receives the events it missed before the live feed.
+// With Spill, an idle room's events are spilled when it is dropped, and a
+// recreated room continues from the highest stored Seq.
+//
+// Backpressure: Broadcast blocks while a subscriber's buffer is full, which in
+// turn slows down the session producing the events. A client that makes no
+// progress within SendTimeout is disconnected with a close reason, so it can
+// reconnect and resume instead of silently missing events.
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan workflow.StreamEvent
+	rooms      map[string]*room // session UUID -> room
 	register   chan *Client
 	unregister chan *Client
-	mu         sync.Mutex
+	mu         sync.Mutex // Guards clients and rooms; acquired before room.mu
+
+	Commands    CommandHandler    // Runs session commands received from clients; optional
+	Authorizer  SessionAuthorizer // Checks subscriptions; nil allows every subscription
+	SendTimeout time.Duration
+	ReplaySize  int           // Events kept in memory per session (default DefaultReplaySize)
+	Spill       EventStore    // Optional store for events evicted from the replay buffer
+	RoomTTL     time.Duration // Idle rooms without subscribers are dropped after this
+}
+
+// room holds a session's subscribers and recent events.
+type room struct {
+	mu       sync.Mutex // Held while delivering, so replay and live events never interleave
+	seq      uint64
+	events   *ReplayBuffer
+	clients  map[*Client]bool
+	lastUsed time.Time
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		broadcast:  make(chan workflow.StreamEvent),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		clients:    make(map[*Client]bool),
+		register:    make(chan *Client),
+		unregister:  make(chan *Client),
+		clients:     make(map[*Client]bool),
+		rooms:       make(map[string]*room),
+		SendTimeout: DefaultSendTimeout,
+		ReplaySize:  DefaultReplaySize,
+		RoomTTL:     30 * time.Minute,
 	}
 }
 
 func (h *Hub) Run() {
+	sweep := time.NewTicker(time.Minute)
+	defer sweep.Stop()
 	for {
 		select {
 		case client := <-h.register:
@@ -45,68 +102,518 @@ func (h *Hub) Run() {
 			h.mu.Unlock()
 
 		case client := <-h.unregister:
-			h.mu.Lock()
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				close(client.send)
+			h.removeClient(client)
+
+		case <-sweep.C:
+			h.sweepRooms(time.Now())
+		}
+	}
+}
+
+// Broadcast delivers an event to the subscribers of its session
+// (Data["session_uuid"]), assigning its Seq. Events without a session go to
+// every client and are not replayed.
+func (h *Hub) Broadcast(event workflow.StreamEvent) {
+	sessionID, _ := event.Data["session_uuid"].(string)
+	if sessionID == "" {
+		h.mu.Lock()
+		targets := make([]*Client, 0, len(h.clients))
+		for client := range h.clients {
+			targets = append(targets, client)
+		}
+		h.mu.Unlock()
+
+		for _, client := range targets {
+			h.deliver(client, event)
+		}
+		return
+	}
+
+	r := h.room(sessionID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	event.Seq = r.seq
+	r.lastUsed = time.Now()
+	if evicted := r.events.Add(event); len(evicted) > 0 && h.Spill != nil {
+		if err := h.Spill.Append(context.Background(), sessionID, evicted); err != nil {
+			log.Printf("[WS] Failed to spill events of session %s: %v", sessionID, err)
+		}
+	}
+
+	for client := range r.clients {
+		if !h.deliver(client, event) {
+			delete(r.clients, client)
+		}
+	}
+}
+
+// Subscribe registers a subscriber without a WebSocket connection (e.g. an
+// SSE stream) to a session. The events after lastSeq, if set, are returned
+// rather than queued, as nothing reads the queue yet and a long replay would
+// overflow it; later events follow on Events() until Done() is closed.
+// Release the subscriber with Unsubscribe.
+func (h *Hub) Subscribe(sessionID string, lastSeq *uint64) (*Client, []workflow.StreamEvent) {
+	client := &Client{
+		hub:      h,
+		send:     make(chan workflow.StreamEvent, sendBufferSize),
+		sessions: make(map[string]bool),
+		done:     make(chan struct{}),
+	}
+	h.mu.Lock()
+	h.clients[client] = true
+	h.mu.Unlock()
+
+	r := h.room(sessionID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var replay []workflow.StreamEvent
+	if lastSeq != nil {
+		replay = h.missed(sessionID, r, *lastSeq)
+	}
+	h.join(client, r, sessionID)
+	return client, replay
+}
+
+// Unsubscribe removes a subscriber created with Subscribe.
+func (h *Hub) Unsubscribe(client *Client) {
+	h.removeClient(client)
+}
+
+// Subscribers returns the number of clients subscribed to a session.
+func (h *Hub) Subscribers(sessionID string) int {
+	h.mu.Lock()
+	r, ok := h.rooms[sessionID]
+	h.mu.Unlock()
+	if !ok {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.clients)
+}
+
+// room returns the session's room, creating it on first use.
+func (h *Hub) room(sessionID string) *room {
+	h.mu.Lock()
+	r, ok := h.rooms[sessionID]
+	h.mu.Unlock()
+	if ok {
+		return r
+	}
+
+	// Look up the stored numbering without holding the hub lock
+	seq := h.storedSeq(sessionID)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r, ok := h.rooms[sessionID]; ok {
+		return r
+	}
+	r = &room{
+		seq:      seq,
+		events:   NewReplayBuffer(h.ReplaySize),
+		clients:  make(map[*Client]bool),
+		lastUsed: time.Now(),
+	}
+	h.rooms[sessionID] = r
+	return r
+}
+
+// storedSeq returns the highest Seq spilled for the session, or 0.
+func (h *Hub) storedSeq(sessionID string) uint64 {
+	if h.Spill == nil {
+		return 0
+	}
+	seq, err := h.Spill.LastSeq(context.Background(), sessionID)
+	if err != nil {
+		log.Printf("[WS] Failed to read the last seq of session %s: %v", sessionID, err)
+	}
+	return seq
+}
+
+// deliver reports whether the event was queued for the client.
+func (h *Hub) deliver(client *Client, event workflow.StreamEvent) bool {
+	select {
+	case client.send <- event:
+		return true
+	case <-client.done:
+		return false
+	default:
+	}
+
+	// Buffer full: wait for the client to catch up
+	timer := time.NewTimer(h.SendTimeout)
+	defer timer.Stop()
+	select {
+	case client.send <- event:
+		return true
+	case <-client.done:
+		return false
+	case <-timer.C:
+		log.Printf("[WS] Disconnecting slow client after %s of backpressure", h.SendTimeout)
+		client.close("slow consumer: events not read in time")
+		return false
+	}
+}
+
+// subscribe adds the client to the session's room. With lastSeq, the events
+// after it are replayed first; if some of them are no longer available, a
+// "stream:gap" event tells the client to resync from the REST API.
+func (h *Hub) subscribe(client *Client, sessionID string, lastSeq *uint64) {
+	r := h.room(sessionID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lastSeq != nil {
+		for _, event := range h.missed(sessionID, r, *lastSeq) {
+			if !h.deliver(client, event) {
+				return
 			}
-			h.mu.Unlock()
+		}
+	}
 
-		case message := <-h.broadcast:
-			h.mu.Lock()
-			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(h.clients, client)
-				}
+	h.join(client, r, sessionID)
+}
+
+// join adds the client to the room. Requires r.mu.
+func (h *Hub) join(client *Client, r *room, sessionID string) {
+	r.clients[client] = true
+	client.mu.Lock()
+	client.sessions[sessionID] = true
+	client.mu.Unlock()
+}
+
+// missed collects the events after lastSeq from the replay buffer and the
+// spill store. Requires r.mu.
+func (h *Hub) missed(sessionID string, r *room, lastSeq uint64) []workflow.StreamEvent {
+	if lastSeq > r.seq {
+		lastSeq = 0 // Sequence restarted (e.g. server restart): replay everything we have
+	}
+	if lastSeq == r.seq {
+		return nil
+	}
+	events := r.events.Since(lastSeq)
+	oldest := r.events.Oldest()
+	if oldest != 0 && oldest <= lastSeq+1 {
+		return events
+	}
+
+	// The buffer starts after lastSeq (or is empty in a recreated room):
+	// prepend older events from the spill store
+	if h.Spill != nil {
+		stored, err := h.Spill.Since(context.Background(), sessionID, lastSeq)
+		if err != nil {
+			log.Printf("[WS] Failed to read spilled events of session %s: %v", sessionID, err)
+		}
+		var spilled []workflow.StreamEvent
+		for _, e := range stored {
+			if oldest == 0 || e.Seq < oldest {
+				spilled = append(spilled, e)
 			}
-			h.mu.Unlock()
 		}
+		events = append(spilled, events...)
 	}
+	if len(events) > 0 && events[0].Seq == lastSeq+1 {
+		return events
+	}
+
+	next := r.seq + 1
+	if len(events) > 0 {
+		next = events[0].Seq
+	}
+	gap := workflow.StreamEvent{
+		Type:      "stream:gap",
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"session_uuid": sessionID,
+			"from_seq":     lastSeq + 1,
+			"to_seq":       next - 1,
+		},
+	}
+	return append([]workflow.StreamEvent{gap}, events...)
 }
 
-// Broadcast sends an event to all connected clients
-func (h *Hub) Broadcast(event workflow.StreamEvent) {
-	h.broadcast <- event
+func (h *Hub) unsubscribe(client *Client, sessionID string) {
+	client.mu.Lock()
+	delete(client.sessions, sessionID)
+	client.mu.Unlock()
+
+	h.mu.Lock()
+	r, ok := h.rooms[sessionID]
+	h.mu.Unlock()
+	if ok {
+		r.mu.Lock()
+		delete(r.clients, client)
+		r.mu.Unlock()
+	}
+}
+
+func (h *Hub) removeClient(client *Client) {
+	h.mu.Lock()
+	if _, ok := h.clients[client]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.clients, client)
+	h.mu.Unlock()
+
+	client.mu.Lock()
+	sessions := make([]string, 0, len(client.sessions))
+	for sessionID := range client.sessions {
+		sessions = append(sessions, sessionID)
+	}
+	client.mu.Unlock()
+
+	for _, sessionID := range sessions {
+		h.unsubscribe(client, sessionID)
+	}
+	client.close("")
+}
+
+// sweepRooms drops rooms that have no subscribers and no recent events,
+// spilling their buffered events first so that the session's replay and
+// numbering survive.
+func (h *Hub) sweepRooms(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sessionID, r := range h.rooms {
+		r.mu.Lock()
+		idle := len(r.clients) == 0 && now.Sub(r.lastUsed) > h.RoomTTL
+		if idle && h.Spill != nil {
+			if err := h.Spill.Append(context.Background(), sessionID, r.events.Since(0)); err != nil {
+				log.Printf("[WS] Failed to spill events of session %s: %v", sessionID, err)
+			}
+		}
+		r.mu.Unlock()
+		if idle {
+			delete(h.rooms, sessionID)
+		}
+	}
 }
 
 // Client is a middleman between the websocket connection and the hub
 type Client struct {
-	hub  *Hub
-	conn *websocket.Conn
-	send chan workflow.StreamEvent
+	hub      *Hub
+	conn     *websocket.Conn
+	send     chan workflow.StreamEvent
+	mu       sync.Mutex
+	sessions map[string]bool // Subscribed sessions, guarded by mu
+	user     *auth.User      // Authenticated user; nil when auth is not configured
+	tenant   context.Context // Carries the user's tenant beyond the upgrade request
+
+	done        chan struct{}
+	closeOnce   sync.Once
+	closeReason string
 }
 
-func (c *Client) writePump() {
+// ClientMessage is a command sent by the client. The reply, correlated by ID,
+// is a "command:result" event with the command's result or a "command:error"
+// event with the error:
+//
+//	{"id": "1", "cmd": "subscribe", "data": {"session_uuid": "...", "last_seq": 42}}
+//	{"id": "2", "cmd": "control", "data": {"session_uuid": "...", "action": "pause"}}
+//	{"id": "3", "cmd": "review", "data": {"session_uuid": "...", "node_id": "review", "action": "approve"}}
+//	{"id": "4", "cmd": "signal", "data": {"session_uuid": "...", "node_id": "wait", "payload": {...}}}
+//	{"id": "5", "cmd": "ping"}
+type ClientMessage struct {
+	ID   string          `json:"id,omitempty"`
+	Cmd  string          `json:"cmd"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// CommandHandler runs the session commands (control, review, signal) received
+// over the socket on behalf of user. data is the command's JSON body.
+type CommandHandler interface {
+	HandleCommand(ctx context.Context, user *auth.User, cmd, sessionID string, data json.RawMessage) (interface{}, error)
+}
+
+// SessionAuthorizer decides whether a user may receive a session's events.
+type SessionAuthorizer interface {
+	AuthorizeSubscribe(ctx context.Context, user *auth.User, sessionID string) error
+}
+
+// authorizeSubscribe checks a subscription with the hub's Authorizer, if any.
+func (c *Client) authorizeSubscribe(sessionID string) error {
+	if c.hub.Authorizer == nil {
+		return nil
+	}
+	ctx, cancel := c.commandContext()
+	defer cancel()
+	return c.hub.Authorizer.AuthorizeSubscribe(ctx, c.user, sessionID)
+}
+
+// commandContext bounds a command to writeWait on behalf of the client's tenant.
+func (c *Client) commandContext() (context.Context, context.CancelFunc) {
+	parent := c.tenant
+	if parent == nil {
+		parent = context.Background()
+	}
+	return context.WithTimeout(parent, writeWait)
+}
+
+// Events delivers the subscribed sessions' events.
+func (c *Client) Events() <-chan workflow.StreamEvent {
+	return c.send
+}
+
+// Done is closed when the hub drops the client; CloseReason tells why.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// CloseReason is set when the hub disconnects a slow client.
+func (c *Client) CloseReason() string {
+	select {
+	case <-c.done:
+		return c.closeReason
+	default:
+		return ""
+	}
+}
+
+func (c *Client) close(reason string) {
+	c.closeOnce.Do(func() {
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// readPump handles subscription messages and detects disconnects.
+func (c *Client) readPump() {
 	defer func() {
+		c.hub.unregister <- c
 		c.conn.Close()
 	}()
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
 	for {
-		message, ok := <-c.send
-		if !ok {
-			// Hub closed the channel
-			_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
 			return
 		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
 
-		w, err := c.conn.NextWriter(websocket.TextMessage)
-		if err != nil {
-			return
+		var msg ClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.reply(msg, nil, fmt.Errorf("invalid message: %w", err))
+			continue
+		}
+		result, err := c.handle(msg)
+		c.reply(msg, result, err)
+	}
+}
+
+// handle runs a client command and returns its result.
+func (c *Client) handle(msg ClientMessage) (interface{}, error) {
+	var target struct {
+		SessionID string  `json:"session_uuid"`
+		LastSeq   *uint64 `json:"last_seq"` // Resume after this Seq; omitted for live events only
+	}
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &target); err != nil {
+			return nil, fmt.Errorf("invalid command data: %w", err)
 		}
+	}
 
-		if err := json.NewEncoder(w).Encode(message); err != nil {
-			return
+	switch msg.Cmd {
+	case "ping":
+		return map[string]interface{}{"pong": true}, nil
+	case "subscribe":
+		if target.SessionID == "" {
+			return nil, fmt.Errorf("session_uuid required")
 		}
+		if err := c.authorizeSubscribe(target.SessionID); err != nil {
+			return nil, err
+		}
+		// Acknowledge first so that the reply precedes the replayed events
+		c.reply(msg, map[string]interface{}{"session_uuid": target.SessionID}, nil)
+		c.hub.subscribe(c, target.SessionID, target.LastSeq)
+		return nil, nil
+	case "unsubscribe":
+		c.hub.unsubscribe(c, target.SessionID)
+		return map[string]interface{}{"session_uuid": target.SessionID}, nil
+	case "control", "review", "signal":
+		if c.hub.Commands == nil {
+			return nil, fmt.Errorf("command %q not supported", msg.Cmd)
+		}
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		return c.hub.Commands.HandleCommand(ctx, c.user, msg.Cmd, target.SessionID, msg.Data)
+	default:
+		return nil, fmt.Errorf("unknown command %q", msg.Cmd)
+	}
+}
+
+// reply sends the outcome of a command to the client. A nil result without
+// error means the reply was already sent.
+func (c *Client) reply(msg ClientMessage, result interface{}, err error) {
+	event := workflow.StreamEvent{
+		Type:      "command:result",
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"id": msg.ID, "cmd": msg.Cmd},
+	}
+	switch {
+	case err != nil:
+		event.Type = "command:error"
+		event.Data["error"] = err.Error()
+	case result == nil:
+		return
+	default:
+		event.Data["result"] = result
+	}
+	c.hub.deliver(c, event)
+}
 
-		if err := w.Close(); err != nil {
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+	for {
+		select {
+		case message := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			w, err := c.conn.NextWriter(websocket.TextMessage)
+			if err != nil {
+				return
+			}
+			if err := json.NewEncoder(w).Encode(message); err != nil {
+				return
+			}
+			if err := w.Close(); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+
+		case <-c.done:
+			// Hub removed the client
+			code := websocket.CloseNormalClosure
+			if c.closeReason != "" {
+				code = websocket.ClosePolicyViolation
+			}
+			_ = c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(code, c.closeReason), time.Now().Add(writeWait))
 			return
 		}
 	}
 }
 
-// HandleWS handles websocket requests from the peer.
+// ServeWs handles websocket requests from the peer. The client subscribes to
+// a session with ?session_uuid= (and optionally &last_seq= to resume) or later
+// with a "subscribe" message. Commands run as the user set by the authz middleware.
 func ServeWs(hub *Hub, c *gin.Context) {
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -114,11 +621,32 @@ func ServeWs(hub *Hub, c *gin.Context) {
 		return
 	}
 
-	client := &Client{hub: hub, conn: conn, send: make(chan workflow.StreamEvent, 256)}
+	client := &Client{
+		hub:      hub,
+		conn:     conn,
+		send:     make(chan workflow.StreamEvent, sendBufferSize),
+		sessions: make(map[string]bool),
+		user:     authz.UserFrom(c),
+		tenant:   tenant.Detach(c.Request.Context()),
+		done:     make(chan struct{}),
+	}
 	client.hub.register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
 	// new goroutines.
 	go client.writePump()
-	// Read pump logic can be added if we accept input from WS
+
+	if sessionID := c.Query("session_uuid"); sessionID != "" {
+		var lastSeq *uint64
+		if v, err := strconv.ParseUint(c.Query("last_seq"), 10, 64); err == nil {
+			lastSeq = &v
+		}
+		if err := client.authorizeSubscribe(sessionID); err != nil {
+			client.reply(ClientMessage{Cmd: "subscribe"}, nil, err)
+		} else {
+			hub.subscribe(client, sessionID, lastSeq)
+		}
+	}
+
+	go client.readPump()
 }
@@ -1,6 +1,9 @@
 package ws
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -8,6 +11,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/hrygo/council/internal/api/authz"
+	"github.com/hrygo/council/internal/core/auth"
 	"github.com/hrygo/council/internal/core/workflow"
 )
 
@@ -105,8 +110,205 @@ func TestHub_ClientDisconnect(t *testing.T) {
 	conn.Close()
 	time.Sleep(50 * time.Millisecond)
 
-	// Since we don't have a read pump in Client yet, we might not detect disconnect immediately
-	// unless we write to it or implement a read pump that detects EOF.
-	// Our writePump only closes on write error or hub unregister.
-	// Let's force unregister if we had a way, or just accept that 0% -> 50% is enough for now.
+	// The read pump detects EOF and unregisters the client
+	hub.mu.Lock()
+	count = len(hub.clients)
+	hub.mu.Unlock()
+	if count != 0 {
+		t.Errorf("Expected 0 clients after disconnect, got %d", count)
+	}
+}
+
+func TestHub_SessionRooms(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	r := gin.New()
+	r.GET("/ws", func(c *gin.Context) {
+		ServeWs(hub, c)
+	})
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	dialer := websocket.Dialer{}
+
+	// Client A subscribes via query parameter
+	connA, _, err := dialer.Dial(wsURL+"?session_uuid=session-a", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer connA.Close()
+
+	// Client B subscribes via message
+	connB, _, err := dialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer connB.Close()
+	if err := connB.WriteJSON(map[string]interface{}{"cmd": "subscribe", "data": map[string]string{"session_uuid": "session-b"}}); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	var ack workflow.StreamEvent
+	_ = connB.SetReadDeadline(time.Now().Add(time.Second))
+	if err := connB.ReadJSON(&ack); err != nil || ack.Type != "command:result" {
+		t.Fatalf("Expected subscribe acknowledgement, got %+v (%v)", ack, err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if hub.Subscribers("session-a") != 1 || hub.Subscribers("session-b") != 1 {
+		t.Fatalf("Expected one subscriber per room, got a=%d b=%d", hub.Subscribers("session-a"), hub.Subscribers("session-b"))
+	}
+
+	hub.Broadcast(workflow.StreamEvent{Type: "for_a", Data: map[string]interface{}{"session_uuid": "session-a"}})
+	hub.Broadcast(workflow.StreamEvent{Type: "for_b", Data: map[string]interface{}{"session_uuid": "session-b"}})
+
+	for name, tc := range map[string]struct {
+		conn     *websocket.Conn
+		expected string
+	}{
+		"A": {connA, "for_a"},
+		"B": {connB, "for_b"},
+	} {
+		var received workflow.StreamEvent
+		_ = tc.conn.SetReadDeadline(time.Now().Add(time.Second))
+		if err := tc.conn.ReadJSON(&received); err != nil {
+			t.Fatalf("Client %s failed to read: %v", name, err)
+		}
+		if received.Type != tc.expected {
+			t.Errorf("Client %s: expected %s, got %s", name, tc.expected, received.Type)
+		}
+	}
+
+	// Nothing else is delivered to A
+	var extra workflow.StreamEvent
+	_ = connA.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if err := connA.ReadJSON(&extra); err == nil {
+		t.Errorf("Client A received an event of another session: %v", extra)
+	}
+}
+
+func TestHub_SlowClientDisconnected(t *testing.T) {
+	hub := NewHub()
+	hub.SendTimeout = 20 * time.Millisecond
+
+	// A client whose write pump never drains its buffer
+	client := &Client{
+		hub:      hub,
+		send:     make(chan workflow.StreamEvent, 1),
+		sessions: map[string]bool{"s1": true},
+		done:     make(chan struct{}),
+	}
+	hub.clients[client] = true
+	hub.subscribe(client, "s1", nil)
+
+	event := workflow.StreamEvent{Type: "token_stream", Data: map[string]interface{}{"session_uuid": "s1"}}
+	hub.Broadcast(event) // Fills the buffer
+	hub.Broadcast(event) // Blocks, then evicts
+
+	select {
+	case <-client.done:
+	default:
+		t.Fatal("Expected slow client to be closed")
+	}
+	if client.closeReason == "" {
+		t.Error("Expected a close reason for the slow client")
+	}
+	if hub.Subscribers("s1") != 0 {
+		t.Errorf("Expected slow client to leave its room")
+	}
+}
+
+// recordingCommands is a CommandHandler that records the commands it runs,
+// and a SessionAuthorizer that denies the "secret" session.
+type recordingCommands struct {
+	calls []string
+}
+
+func (r *recordingCommands) HandleCommand(ctx context.Context, user *auth.User, cmd, sessionID string, data json.RawMessage) (interface{}, error) {
+	r.calls = append(r.calls, cmd+":"+sessionID+":"+user.Username)
+	if cmd == "signal" {
+		return nil, errors.New("node is not waiting")
+	}
+	return map[string]interface{}{"status": "ok"}, nil
+}
+
+func (r *recordingCommands) AuthorizeSubscribe(ctx context.Context, user *auth.User, sessionID string) error {
+	if sessionID == "secret" {
+		return authz.ErrForbidden
+	}
+	return nil
+}
+
+func TestHub_Commands(t *testing.T) {
+	hub := NewHub()
+	commands := &recordingCommands{}
+	hub.Commands = commands
+	hub.Authorizer = commands
+	go hub.Run()
+
+	r := gin.New()
+	r.GET("/ws", func(c *gin.Context) {
+		authz.SetUser(c, &auth.User{Username: "alice"})
+		ServeWs(hub, c)
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	send := func(msg string) workflow.StreamEvent {
+		t.Helper()
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+		var reply workflow.StreamEvent
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		if err := conn.ReadJSON(&reply); err != nil {
+			t.Fatalf("Failed to read reply: %v", err)
+		}
+		return reply
+	}
+
+	reply := send(`{"id": "1", "cmd": "ping"}`)
+	if reply.Type != "command:result" || reply.Data["id"] != "1" {
+		t.Errorf("Unexpected ping reply: %+v", reply)
+	}
+
+	reply = send(`{"id": "2", "cmd": "control", "data": {"session_uuid": "s1", "action": "pause"}}`)
+	if reply.Type != "command:result" || reply.Data["id"] != "2" || reply.Data["result"].(map[string]interface{})["status"] != "ok" {
+		t.Errorf("Unexpected control reply: %+v", reply)
+	}
+
+	reply = send(`{"id": "3", "cmd": "signal", "data": {"session_uuid": "s1", "node_id": "n"}}`)
+	if reply.Type != "command:error" || reply.Data["id"] != "3" || reply.Data["error"] != "node is not waiting" {
+		t.Errorf("Unexpected signal reply: %+v", reply)
+	}
+
+	reply = send(`{"id": "4", "cmd": "subscribe", "data": {"session_uuid": "secret"}}`)
+	if reply.Type != "command:error" || reply.Data["error"] != authz.ErrForbidden.Error() {
+		t.Errorf("Expected forbidden subscription, got %+v", reply)
+	}
+	if hub.Subscribers("secret") != 0 {
+		t.Error("Expected no subscriber for a forbidden session")
+	}
+
+	reply = send(`{"id": "5", "cmd": "teleport"}`)
+	if reply.Type != "command:error" {
+		t.Errorf("Expected error for unknown command, got %+v", reply)
+	}
+
+	reply = send(`not json`)
+	if reply.Type != "command:error" {
+		t.Errorf("Expected error for malformed message, got %+v", reply)
+	}
+
+	if strings.Join(commands.calls, ",") != "control:s1:alice,signal:s1:alice" {
+		t.Errorf("Unexpected dispatched commands: %v", commands.calls)
+	}
 }
@@ -0,0 +1,145 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/cache"
+)
+
+// DefaultReplaySize is the number of recent events kept in memory per session.
+const DefaultReplaySize = 1000
+
+// ReplayBuffer keeps the most recent events of a session, ordered by Seq.
+// When full, the oldest quarter is evicted at once so that it can be handed
+// to an EventStore in a single batch.
+type ReplayBuffer struct {
+	events   []workflow.StreamEvent
+	capacity int
+}
+
+func NewReplayBuffer(capacity int) *ReplayBuffer {
+	if capacity <= 0 {
+		capacity = DefaultReplaySize
+	}
+	return &ReplayBuffer{capacity: capacity}
+}
+
+// Add appends an event and returns the events evicted to make room, if any.
+func (b *ReplayBuffer) Add(event workflow.StreamEvent) []workflow.StreamEvent {
+	var evicted []workflow.StreamEvent
+	if len(b.events) >= b.capacity {
+		n := b.capacity / 4
+		if n == 0 {
+			n = 1
+		}
+		evicted = append(evicted, b.events[:n]...)
+		b.events = append(b.events[:0], b.events[n:]...)
+	}
+	b.events = append(b.events, event)
+	return evicted
+}
+
+// Since returns the buffered events with Seq > seq.
+func (b *ReplayBuffer) Since(seq uint64) []workflow.StreamEvent {
+	for i, e := range b.events {
+		if e.Seq > seq {
+			return append([]workflow.StreamEvent(nil), b.events[i:]...)
+		}
+	}
+	return nil
+}
+
+// Oldest returns the lowest buffered Seq, or 0 when empty.
+func (b *ReplayBuffer) Oldest() uint64 {
+	if len(b.events) == 0 {
+		return 0
+	}
+	return b.events[0].Seq
+}
+
+// EventStore keeps events evicted from the replay buffer, extending how far
+// back a reconnecting client can resume. Its highest Seq lets a recreated
+// room continue the session's numbering.
+type EventStore interface {
+	Append(ctx context.Context, sessionID string, events []workflow.StreamEvent) error
+	Since(ctx context.Context, sessionID string, seq uint64) ([]workflow.StreamEvent, error)
+	LastSeq(ctx context.Context, sessionID string) (uint64, error)
+}
+
+// RedisEventStore spills events to a capped Redis list per session.
+type RedisEventStore struct {
+	cache     cache.Cache
+	MaxEvents int64         // Events kept per session (default 10000)
+	TTL       time.Duration // Expiry of a session's list (default 24h)
+}
+
+func NewRedisEventStore(c cache.Cache) *RedisEventStore {
+	return &RedisEventStore{cache: c, MaxEvents: 10000, TTL: 24 * time.Hour}
+}
+
+func (s *RedisEventStore) key(sessionID string) string {
+	return fmt.Sprintf("stream:%s", sessionID)
+}
+
+// Append pushes events newest-first, so that the list head is the latest event.
+func (s *RedisEventStore) Append(ctx context.Context, sessionID string, events []workflow.StreamEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	values := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %d: %w", e.Seq, err)
+		}
+		values = append(values, string(data))
+	}
+
+	key := s.key(sessionID)
+	if err := s.cache.LPush(ctx, key, values...).Err(); err != nil {
+		return fmt.Errorf("failed to spill events: %w", err)
+	}
+	s.cache.LTrim(ctx, key, 0, s.MaxEvents-1)
+	s.cache.Expire(ctx, key, s.TTL)
+	return nil
+}
+
+// Since returns the stored events with Seq > seq, oldest first.
+func (s *RedisEventStore) Since(ctx context.Context, sessionID string, seq uint64) ([]workflow.StreamEvent, error) {
+	values, err := s.cache.LRange(ctx, s.key(sessionID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spilled events: %w", err)
+	}
+
+	var events []workflow.StreamEvent
+	for i := len(values) - 1; i >= 0; i-- {
+		var e workflow.StreamEvent
+		if err := json.Unmarshal([]byte(values[i]), &e); err != nil {
+			continue
+		}
+		if e.Seq > seq {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// LastSeq returns the Seq of the list head, or 0 when nothing is stored.
+func (s *RedisEventStore) LastSeq(ctx context.Context, sessionID string) (uint64, error) {
+	values, err := s.cache.LRange(ctx, s.key(sessionID), 0, 0).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read spilled events: %w", err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+	var e workflow.StreamEvent
+	if err := json.Unmarshal([]byte(values[0]), &e); err != nil {
+		return 0, fmt.Errorf("failed to decode spilled event: %w", err)
+	}
+	return e.Seq, nil
+}
@@ -0,0 +1,225 @@
+package ws
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// memoryEventStore is an in-memory EventStore.
+type memoryEventStore struct {
+	events map[string][]workflow.StreamEvent
+}
+
+func (s *memoryEventStore) Append(ctx context.Context, sessionID string, events []workflow.StreamEvent) error {
+	s.events[sessionID] = append(s.events[sessionID], events...)
+	return nil
+}
+
+func (s *memoryEventStore) Since(ctx context.Context, sessionID string, seq uint64) ([]workflow.StreamEvent, error) {
+	var out []workflow.StreamEvent
+	for _, e := range s.events[sessionID] {
+		if e.Seq > seq {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryEventStore) LastSeq(ctx context.Context, sessionID string) (uint64, error) {
+	var seq uint64
+	for _, e := range s.events[sessionID] {
+		seq = max(seq, e.Seq)
+	}
+	return seq, nil
+}
+
+func sessionEvent(sessionID string) workflow.StreamEvent {
+	return workflow.StreamEvent{Type: "token_stream", Data: map[string]interface{}{"session_uuid": sessionID}}
+}
+
+func newTestClient(hub *Hub) *Client {
+	return &Client{
+		hub:      hub,
+		send:     make(chan workflow.StreamEvent, 100),
+		sessions: make(map[string]bool),
+		done:     make(chan struct{}),
+	}
+}
+
+func drain(c *Client) []workflow.StreamEvent {
+	var events []workflow.StreamEvent
+	for {
+		select {
+		case e := <-c.send:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestHub_ResumeWithLastSeq(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	r := gin.New()
+	r.GET("/ws", func(c *gin.Context) {
+		ServeWs(hub, c)
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	// Events sent while nobody is connected
+	for i := 0; i < 3; i++ {
+		hub.Broadcast(sessionEvent("s1"))
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?session_uuid=s1&last_seq=1"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	hub.Broadcast(sessionEvent("s1"))
+
+	for _, expected := range []uint64{2, 3, 4} {
+		var e workflow.StreamEvent
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		if err := conn.ReadJSON(&e); err != nil {
+			t.Fatalf("Failed to read: %v", err)
+		}
+		if e.Seq != expected {
+			t.Errorf("Expected seq %d, got %d", expected, e.Seq)
+		}
+	}
+}
+
+func TestHub_ReplayFromSpill(t *testing.T) {
+	hub := NewHub()
+	hub.ReplaySize = 4
+	hub.Spill = &memoryEventStore{events: make(map[string][]workflow.StreamEvent)}
+
+	for i := 0; i < 10; i++ {
+		hub.Broadcast(sessionEvent("s1"))
+	}
+
+	client := newTestClient(hub)
+	lastSeq := uint64(0)
+	hub.subscribe(client, "s1", &lastSeq)
+
+	events := drain(client)
+	if len(events) != 10 {
+		t.Fatalf("Expected all 10 events, got %d", len(events))
+	}
+	for i, e := range events {
+		if e.Seq != uint64(i+1) {
+			t.Errorf("Expected seq %d at %d, got %d", i+1, i, e.Seq)
+		}
+	}
+}
+
+func TestHub_SeqSurvivesDroppedRoom(t *testing.T) {
+	hub := NewHub()
+	hub.ReplaySize = 4
+	hub.Spill = &memoryEventStore{events: make(map[string][]workflow.StreamEvent)}
+
+	for i := 0; i < 6; i++ {
+		hub.Broadcast(sessionEvent("s1"))
+	}
+	hub.sweepRooms(time.Now().Add(hub.RoomTTL + time.Minute))
+	if hub.Subscribers("s1") != 0 || len(hub.rooms) != 0 {
+		t.Fatal("Expected the idle room to be dropped")
+	}
+
+	// The recreated room continues the numbering and replays the dropped events
+	hub.Broadcast(sessionEvent("s1"))
+	client := newTestClient(hub)
+	lastSeq := uint64(3)
+	hub.subscribe(client, "s1", &lastSeq)
+
+	events := drain(client)
+	if len(events) != 4 {
+		t.Fatalf("Expected events 4 to 7, got %v", events)
+	}
+	for i, e := range events {
+		if e.Seq != uint64(i+4) {
+			t.Errorf("Expected seq %d at %d, got %d (%s)", i+4, i, e.Seq, e.Type)
+		}
+	}
+}
+
+func TestHub_ReplayGap(t *testing.T) {
+	hub := NewHub()
+	hub.ReplaySize = 4
+
+	for i := 0; i < 10; i++ {
+		hub.Broadcast(sessionEvent("s1"))
+	}
+
+	client := newTestClient(hub)
+	lastSeq := uint64(2)
+	hub.subscribe(client, "s1", &lastSeq)
+
+	events := drain(client)
+	if len(events) == 0 || events[0].Type != "stream:gap" {
+		t.Fatalf("Expected a gap event first, got %v", events)
+	}
+	if events[0].Data["from_seq"] != uint64(3) || events[0].Data["to_seq"] != events[1].Seq-1 {
+		t.Errorf("Unexpected gap range: %v (next seq %d)", events[0].Data, events[1].Seq)
+	}
+	if events[len(events)-1].Seq != 10 {
+		t.Errorf("Expected replay to end with seq 10, got %d", events[len(events)-1].Seq)
+	}
+}
+
+func TestRedisEventStore(t *testing.T) {
+	var list []string // Head is the newest entry, as in Redis
+	mockCache := &cache.MockCache{
+		LPushFunc: func(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+			for _, v := range values {
+				list = append([]string{v.(string)}, list...)
+			}
+			return redis.NewIntCmd(ctx)
+		},
+		LRangeFunc: func(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+			cmd := redis.NewStringSliceCmd(ctx)
+			if stop >= 0 && int(stop) < len(list) {
+				cmd.SetVal(list[start : stop+1])
+			} else {
+				cmd.SetVal(list)
+			}
+			return cmd
+		},
+	}
+	store := NewRedisEventStore(mockCache)
+
+	err := store.Append(context.Background(), "s1", []workflow.StreamEvent{
+		{Type: "a", Seq: 1}, {Type: "b", Seq: 2}, {Type: "c", Seq: 3},
+	})
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	events, err := store.Since(context.Background(), "s1", 1)
+	if err != nil {
+		t.Fatalf("Since failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Seq != 2 || events[1].Seq != 3 {
+		t.Errorf("Expected events 2 and 3 oldest first, got %v", events)
+	}
+
+	if seq, err := store.LastSeq(context.Background(), "s1"); err != nil || seq != 3 {
+		t.Errorf("Expected last seq 3, got %d, %v", seq, err)
+	}
+}
@@ -26,6 +26,16 @@ type ModelConfig struct {
 	Temperature float64 `json:"temperature"`
 	TopP        float64 `json:"top_p"`
 	MaxTokens   int     `json:"max_tokens"`
+	// Fallbacks are tried in order when the provider fails; empty uses the
+	// global LLM_FALLBACKS chain.
+	Fallbacks []ModelFallback `json:"fallbacks,omitempty"`
+}
+
+// ModelFallback is a provider of a fallback chain and the model to ask it
+// for; an empty model keeps the agent's model.
+type ModelFallback struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
 }
 
 // Capabilities defines what this agent can do.
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{RoleOwner, RoleEditor, true},
+		{RoleEditor, RoleEditor, true},
+		{RoleReviewer, RoleEditor, false},
+		{RoleReviewer, RoleViewer, true},
+		{RoleViewer, RoleReviewer, false},
+		{Role("admin"), RoleViewer, false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Allows(tt.required); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestTokenIssuer_RoundTrip(t *testing.T) {
+	issuer := NewTokenIssuer("secret")
+	id := uuid.New()
+
+	token, expires, err := issuer.Issue(id)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if time.Until(expires) <= 0 {
+		t.Errorf("expected a future expiry, got %v", expires)
+	}
+
+	got, err := issuer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got != id {
+		t.Errorf("expected subject %v, got %v", id, got)
+	}
+}
+
+func TestTokenIssuer_Rejects(t *testing.T) {
+	issuer := NewTokenIssuer("secret")
+	token, _, _ := issuer.Issue(uuid.New())
+
+	if _, err := NewTokenIssuer("other").Verify(token); err != ErrInvalidToken {
+		t.Errorf("expected forged token to fail, got %v", err)
+	}
+
+	body, sig, _ := strings.Cut(token, ".")
+	if _, err := issuer.Verify(body + "x." + sig); err != ErrInvalidToken {
+		t.Errorf("expected tampered token to fail, got %v", err)
+	}
+
+	expired := NewTokenIssuer("secret")
+	expired.TTL = -time.Minute
+	old, _, _ := expired.Issue(uuid.New())
+	if _, err := issuer.Verify(old); err != ErrInvalidToken {
+		t.Errorf("expected expired token to fail, got %v", err)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	hash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &User{PasswordHash: &hash}
+	if !CheckPassword(u, "hunter2") {
+		t.Error("expected password to match")
+	}
+	if CheckPassword(u, "wrong") || CheckPassword(&User{}, "") {
+		t.Error("expected wrong or missing password to fail")
+	}
+
+	plain, key, err := GenerateAPIKey("ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(plain, APIKeyPrefix) || !strings.HasPrefix(plain, key.Prefix) {
+		t.Errorf("unexpected key %q with prefix %q", plain, key.Prefix)
+	}
+	if key.KeyHash != HashAPIKey(plain) || key.KeyHash == plain {
+		t.Error("expected the record to hold the key's hash only")
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// APIKeyPrefix marks API keys so that they are recognisable in configs and logs.
+const APIKeyPrefix = "ck_"
+
+// HashPassword returns the bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the user's stored hash.
+func CheckPassword(u *User, password string) bool {
+	if u.PasswordHash == nil || *u.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(*u.PasswordHash), []byte(password)) == nil
+}
+
+// GenerateAPIKey returns a new random key and its record. The plain key is
+// only available here; the record keeps its hash and prefix.
+func GenerateAPIKey(name string) (string, *APIKey, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	plain := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return plain, &APIKey{
+		Name:    name,
+		Prefix:  plain[:len(APIKeyPrefix)+6],
+		KeyHash: HashAPIKey(plain),
+	}, nil
+}
+
+// HashAPIKey returns the stored form of an API key.
+func HashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// EnsureAdmin creates the bootstrap administrator if no user has that name yet.
+// An existing user is left untouched, so changing the password in the
+// environment does not overwrite one set later.
+func EnsureAdmin(ctx context.Context, repo Repository, username, password string) error {
+	_, err := repo.GetUserByUsername(ctx, username)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("admin user %q does not exist and no password is set", username)
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return repo.CreateUser(ctx, &User{Username: username, PasswordHash: &hash, IsAdmin: true})
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/tenant"
+)
+
+// ErrNotFound is returned by the repository when a user, key or membership does not exist.
+var ErrNotFound = errors.New("not found")
+
+// User is an account that can sign in with a password or an API key. It
+// belongs to exactly one tenant, which every request it makes acts for.
+type User struct {
+	ID           uuid.UUID `json:"user_uuid" db:"user_uuid"`
+	TenantID     uuid.UUID `json:"tenant_uuid" db:"tenant_uuid"`
+	Username     string    `json:"username" db:"username"`
+	DisplayName  *string   `json:"display_name" db:"display_name"`
+	PasswordHash *string   `json:"-" db:"password_hash"`
+	IsAdmin      bool      `json:"is_admin" db:"is_admin"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Name returns the name shown in review outputs and audit logs.
+func (u *User) Name() string {
+	if u.DisplayName != nil && *u.DisplayName != "" {
+		return *u.DisplayName
+	}
+	return u.Username
+}
+
+// LocalUser is the principal used for every request when authentication is
+// disabled, so that single-user deployments keep working unchanged.
+var LocalUser = &User{Username: "local", TenantID: tenant.DefaultID, IsAdmin: true}
+
+// APIKey is a long-lived credential. Only the SHA-256 of the key is stored;
+// Prefix keeps the first characters so that keys can be told apart.
+type APIKey struct {
+	ID         uuid.UUID  `json:"key_uuid" db:"key_uuid"`
+	UserID     uuid.UUID  `json:"user_uuid" db:"user_uuid"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+}
+
+// Role is a user's permission level within a group.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // Read agents, workflows, sessions and memory
+	RoleReviewer Role = "reviewer" // Viewer, plus approve or reject human reviews
+	RoleEditor   Role = "editor"   // Reviewer, plus run and control sessions and edit resources
+	RoleOwner    Role = "owner"    // Editor, plus manage the group and its members
+)
+
+var roleRank = map[Role]int{
+	RoleViewer:   1,
+	RoleReviewer: 2,
+	RoleEditor:   3,
+	RoleOwner:    4,
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Allows reports whether r grants at least the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleRank[r] >= roleRank[required]
+}
+
+// Membership grants a user a role in a group.
+type Membership struct {
+	GroupID   uuid.UUID `json:"group_uuid" db:"group_uuid"`
+	UserID    uuid.UUID `json:"user_uuid" db:"user_uuid"`
+	Username  string    `json:"username,omitempty" db:"username"`
+	Role      Role      `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// AuditEvent records who performed a state-changing action.
+type AuditEvent struct {
+	ID        int64                  `json:"audit_id" db:"audit_id"`
+	UserID    *uuid.UUID             `json:"user_uuid" db:"user_uuid"`
+	Username  string                 `json:"username" db:"username"`
+	Action    string                 `json:"action" db:"action"`
+	GroupID   *uuid.UUID             `json:"group_uuid,omitempty" db:"group_uuid"`
+	SessionID *uuid.UUID             `json:"session_uuid,omitempty" db:"session_uuid"`
+	Details   map[string]interface{} `json:"details" db:"details"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for users, API keys, memberships and audit logs.
+type Repository interface {
+	CreateUser(ctx context.Context, user *User) error
+	GetUser(ctx context.Context, id uuid.UUID) (*User, error)
+	GetUserByUsername(ctx context.Context, username string) (*User, error)
+
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	// GetUserByAPIKey resolves a key by its hash and records its use.
+	GetUserByAPIKey(ctx context.Context, keyHash string) (*User, error)
+
+	SetMembership(ctx context.Context, m *Membership) error
+	RemoveMembership(ctx context.Context, groupID, userID uuid.UUID) error
+	// GetRole returns ErrNotFound when the user is not a member of the group.
+	GetRole(ctx context.Context, groupID, userID uuid.UUID) (Role, error)
+	ListMemberships(ctx context.Context, userID uuid.UUID) ([]*Membership, error)
+	ListMembers(ctx context.Context, groupID uuid.UUID) ([]*Membership, error)
+
+	RecordAudit(ctx context.Context, event *AuditEvent) error
+	ListAudit(ctx context.Context, sessionID uuid.UUID) ([]*AuditEvent, error)
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTokenTTL is how long a session token issued at login stays valid.
+const DefaultTokenTTL = 24 * time.Hour
+
+// ErrInvalidToken is returned for malformed, forged or expired tokens.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// TokenIssuer signs session tokens with HMAC-SHA256. A token is
+// base64url(claims) + "." + base64url(signature).
+type TokenIssuer struct {
+	secret []byte
+	TTL    time.Duration
+}
+
+type tokenClaims struct {
+	Subject   uuid.UUID `json:"sub"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+func NewTokenIssuer(secret string) *TokenIssuer {
+	return &TokenIssuer{secret: []byte(secret), TTL: DefaultTokenTTL}
+}
+
+// Issue returns a token for the user and its expiry.
+func (t *TokenIssuer) Issue(userID uuid.UUID) (string, time.Time, error) {
+	expires := time.Now().Add(t.TTL)
+	payload, err := json.Marshal(tokenClaims{Subject: userID, ExpiresAt: expires.Unix()})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode token: %w", err)
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + t.sign(body), expires, nil
+}
+
+// Verify checks the token's signature and expiry and returns its user ID.
+func (t *TokenIssuer) Verify(token string) (uuid.UUID, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(t.sign(body))) {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
+
+func (t *TokenIssuer) sign(body string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
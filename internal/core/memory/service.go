@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/infrastructure/cache"
 	"github.com/hrygo/council/internal/infrastructure/db"
 	"github.com/hrygo/council/internal/infrastructure/llm"
@@ -57,11 +59,21 @@ func (s *Service) LogQuarantine(ctx context.Context, sessionID string, nodeID st
 	return nil
 }
 
+// workingMemoryKey is the Redis list holding a group's working memory. It is
+// namespaced by tenant so that a group ID from another tenant reads nothing.
+func workingMemoryKey(tenantID uuid.UUID, groupID string) string {
+	return fmt.Sprintf("wm:%s:%s", tenantID, groupID)
+}
+
 // UpdateWorkingMemory writes to Redis with Ingress Filter (Tier 2)
 func (s *Service) UpdateWorkingMemory(ctx context.Context, groupID string, content string, metadata map[string]interface{}) error {
 	if s.cache == nil {
 		return fmt.Errorf("redis client not initialized")
 	}
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 
 	// 1. Ingress Filter: Check confidence score
 	confidence, ok := metadata["confidence"].(float64)
@@ -81,7 +93,7 @@ func (s *Service) UpdateWorkingMemory(ctx context.Context, groupID string, conte
 	}
 
 	// 3. Write to Redis List
-	key := fmt.Sprintf("wm:%s", groupID)
+	key := workingMemoryKey(tenantID, groupID)
 
 	if err := s.cache.LPush(ctx, key, content).Err(); err != nil {
 		return fmt.Errorf("failed to push to working memory: %w", err)
@@ -124,21 +136,25 @@ func (s *Service) Promote(ctx context.Context, groupID string, content string) e
 	if s.pool == nil {
 		return fmt.Errorf("database pool not initialized")
 	}
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 
-	// 2. Embed and Store Loop
-	// Optimization: Batch embedding if provider supports it, but for now loop is simpler for MVP
-	for _, chunk := range chunks {
-		embedding, err := s.Embedder.Embed(ctx, "default", chunk) // Use default model from config (implied)
-		if err != nil {
-			return fmt.Errorf("failed to embed chunk: %w", err)
-		}
+	// 2. Embed all chunks in batches, then store them
+	embeddings, err := s.Embedder.EmbedBatch(ctx, "default", chunks) // Use default model from config (implied)
+	if err != nil {
+		return fmt.Errorf("failed to embed chunks: %w", err)
+	}
 
-		vecBytes, _ := json.Marshal(embedding)
+	for i, chunk := range chunks {
+		vecBytes, _ := json.Marshal(embeddings[i])
 		vecStr := string(vecBytes)
 
 		query := `
-			INSERT INTO memories (group_id, content, embedding, metadata)
-			VALUES ($1, $2, $3, $4)
+			INSERT INTO memories (group_uuid, content, embedding, metadata, tenant_uuid)
+			SELECT $1, $2, $3, $4, $5
+			WHERE EXISTS (SELECT 1 FROM groups WHERE group_uuid = $1 AND tenant_uuid = $5)
 		`
 		// Metadata can store source info
 		meta := map[string]interface{}{
@@ -147,21 +163,30 @@ func (s *Service) Promote(ctx context.Context, groupID string, content string) e
 		}
 		metaJSON, _ := json.Marshal(meta)
 
-		_, err = s.pool.Exec(ctx, query, groupID, chunk, vecStr, metaJSON)
+		tag, err := s.pool.Exec(ctx, query, groupID, chunk, vecStr, metaJSON, tenantID)
 		if err != nil {
 			return fmt.Errorf("failed to store memory chunk: %w", err)
 		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("group %s not found", groupID)
+		}
 	}
 
 	return nil
 }
 
+// Retrieve only ever reads memories of the context's tenant, whatever group
+// or session IDs it is given.
 func (s *Service) Retrieve(ctx context.Context, query string, groupID string, sessionID string) ([]ContextItem, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var items []ContextItem
 
 	// 1. Hot Working Memory (Redis)
 	if s.cache != nil {
-		key := fmt.Sprintf("wm:%s", groupID)
+		key := workingMemoryKey(tenantID, groupID)
 		// Get last 10 items
 		vals, err := s.cache.LRange(ctx, key, 0, 10).Result()
 		if err == nil {
@@ -188,10 +213,10 @@ func (s *Service) Retrieve(ctx context.Context, query string, groupID string, se
 			// Query
 			// Using <-> (L2 distance) or <=> (Cosine distance).
 			// Cosine distance is 1 - Cosine Similarity.
-			q := `SELECT content, 1 - (embedding <=> $1) as score FROM memories WHERE group_id = $2::uuid`
-			params := []interface{}{vecStr, groupID}
+			q := `SELECT content, 1 - (embedding <=> $1) as score FROM memories WHERE tenant_uuid = $2 AND group_uuid = $3::uuid`
+			params := []interface{}{vecStr, tenantID, groupID}
 			if sessionID != "" {
-				q += ` AND session_id = $3::uuid`
+				q += ` AND session_uuid = $4::uuid`
 				params = append(params, sessionID)
 			}
 			q += ` ORDER BY embedding <=> $1 LIMIT 5`
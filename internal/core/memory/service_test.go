@@ -2,8 +2,10 @@ package memory
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/infrastructure/cache"
 	"github.com/hrygo/council/internal/infrastructure/llm"
 	"github.com/pashagolub/pgxmock/v3"
@@ -49,13 +51,13 @@ func TestService_UpdateWorkingMemory(t *testing.T) {
 	calledLPush := false
 	mockCache.LPushFunc = func(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
 		calledLPush = true
-		if key != "wm:group-1" {
-			t.Errorf("expected key wm:group-1, got %s", key)
+		if key != "wm:"+tenant.DefaultID.String()+":group-1" {
+			t.Errorf("expected tenant-scoped key for group-1, got %s", key)
 		}
 		return redis.NewIntCmd(ctx)
 	}
 
-	err := svc.UpdateWorkingMemory(context.Background(), groupID, content, metadata)
+	err := svc.UpdateWorkingMemory(tenant.WithID(context.Background(), tenant.DefaultID), groupID, content, metadata)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -76,13 +78,50 @@ func TestService_Promote(t *testing.T) {
 	groupID := "group-1"
 
 	mockDB.ExpectExec("INSERT INTO memories").
-		WithArgs(groupID, content, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs(groupID, content, pgxmock.AnyArg(), pgxmock.AnyArg(), tenant.DefaultID).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
-	err := svc.Promote(context.Background(), groupID, content)
+	err := svc.Promote(tenant.WithID(context.Background(), tenant.DefaultID), groupID, content)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
+
+	// A group of another tenant stores nothing
+	mockDB.ExpectExec("INSERT INTO memories").
+		WithArgs(groupID, content, pgxmock.AnyArg(), pgxmock.AnyArg(), tenant.DefaultID).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	if err := svc.Promote(tenant.WithID(context.Background(), tenant.DefaultID), groupID, content); err == nil {
+		t.Error("expected an error for a group outside the tenant")
+	}
+}
+
+func TestService_PromoteBatchesEmbeddings(t *testing.T) {
+	mockDB, _ := pgxmock.NewPool()
+	mockEmbedder := &llm.MockProvider{
+		EmbedResponse: []float32{0.1, 0.2, 0.3},
+	}
+	svc := NewService(mockEmbedder, mockDB, nil)
+
+	content := strings.Repeat("word ", 80) + "\n\n" + strings.Repeat("more ", 80) + "\n\n" + strings.Repeat("last ", 80)
+	chunks := NewRecursiveCharacterSplitter(500, 50).SplitText(content)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		mockDB.ExpectExec("INSERT INTO memories").
+			WithArgs("group-1", chunk, pgxmock.AnyArg(), pgxmock.AnyArg(), tenant.DefaultID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	}
+
+	if err := svc.Promote(tenant.WithID(context.Background(), tenant.DefaultID), "group-1", content); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if mockEmbedder.EmbedCalls != 1 || len(mockEmbedder.EmbeddedTexts) != len(chunks) {
+		t.Errorf("expected the %d chunks embedded in one call, got %d calls", len(chunks), mockEmbedder.EmbedCalls)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
 }
 
 func TestService_Retrieve(t *testing.T) {
@@ -98,17 +137,24 @@ func TestService_Retrieve(t *testing.T) {
 
 	// Mock Cache LRange
 	mockCache.LRangeFunc = func(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+		if key != "wm:"+tenant.DefaultID.String()+":group-1" {
+			t.Errorf("expected tenant-scoped key for group-1, got %s", key)
+		}
 		cmd := redis.NewStringSliceCmd(ctx)
 		cmd.SetVal([]string{"hot data"})
 		return cmd
 	}
 
 	// Mock DB Query for PGVector
-	mockDB.ExpectQuery("SELECT content, 1 - \\(embedding <=> \\$1\\) as score FROM memories").
-		WithArgs(pgxmock.AnyArg(), groupID).
+	mockDB.ExpectQuery("SELECT content, 1 - \\(embedding <=> \\$1\\) as score FROM memories WHERE tenant_uuid = \\$2").
+		WithArgs(pgxmock.AnyArg(), tenant.DefaultID, groupID).
 		WillReturnRows(pgxmock.NewRows([]string{"content", "score"}).AddRow("cold data", 0.95))
 
-	items, err := svc.Retrieve(context.Background(), query, groupID, "")
+	if _, err := svc.Retrieve(context.Background(), query, groupID, ""); err != tenant.ErrMissing {
+		t.Fatalf("expected ErrMissing without a tenant, got %v", err)
+	}
+
+	items, err := svc.Retrieve(tenant.WithID(context.Background(), tenant.DefaultID), query, groupID, "")
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
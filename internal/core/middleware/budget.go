@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+)
+
+// BudgetGuard pauses a session before the next node once it has reached its
+// session or group budget. Raising the limit and resuming lets it continue;
+// resuming without raising it pauses the session again.
+type BudgetGuard struct {
+	events chan<- workflow.StreamEvent
+}
+
+// NewBudgetGuard reports exceeded budgets on events, usually the engine's StreamChannel.
+func NewBudgetGuard(events chan<- workflow.StreamEvent) *BudgetGuard {
+	return &BudgetGuard{events: events}
+}
+
+func (g *BudgetGuard) Name() string {
+	return "BudgetGuard"
+}
+
+func (g *BudgetGuard) BeforeNodeExecution(ctx context.Context, session *workflow.Session, node *workflow.Node) error {
+	for {
+		exceeded := session.CheckBudget()
+		if exceeded == nil {
+			return nil
+		}
+		if !session.Pause() {
+			// Stopped or finished meanwhile: nobody can resume it
+			return exceeded
+		}
+		g.events <- workflow.StreamEvent{
+			Type:      "execution:budget_exceeded",
+			Timestamp: time.Now(),
+			NodeID:    node.ID,
+			Data: map[string]interface{}{
+				"scope":             exceeded.Scope,
+				"max_tokens":        exceeded.Limit.MaxTokens,
+				"max_cost_usd":      exceeded.Limit.MaxCostUSD,
+				"spent_tokens":      exceeded.Spent.TotalTokens(),
+				"spent_cost_usd":    exceeded.Spent.CostUSD,
+				"prompt_tokens":     exceeded.Spent.PromptTokens,
+				"completion_tokens": exceeded.Spent.CompletionTokens,
+			},
+		}
+		if err := session.WaitIfPaused(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (g *BudgetGuard) AfterNodeExecution(ctx context.Context, session *workflow.Session, node *workflow.Node, output map[string]interface{}) (map[string]interface{}, error) {
+	return output, nil
+}
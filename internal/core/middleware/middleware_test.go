@@ -60,3 +60,62 @@ func TestMemoryMiddleware(t *testing.T) {
 		t.Errorf("Expected quarantine log, got %v", mockManager.CapturedQuarantine)
 	}
 }
+
+func TestBudgetGuard(t *testing.T) {
+	events := make(chan workflow.StreamEvent, 4)
+	guard := NewBudgetGuard(events)
+	node := &workflow.Node{ID: "n1"}
+
+	session := workflow.NewSession(&workflow.GraphDefinition{ID: "g"}, nil)
+	session.Start(context.Background())
+	session.SetBudgets(workflow.Budgets{Session: &workflow.Budget{MaxTokens: 1000}})
+	session.AddUsage(workflow.Usage{PromptTokens: 200, CompletionTokens: 100})
+
+	if err := guard.BeforeNodeExecution(context.Background(), session, node); err != nil {
+		t.Fatalf("Expected a session within budget to pass, got %v", err)
+	}
+
+	session.AddUsage(workflow.Usage{PromptTokens: 600, CompletionTokens: 100})
+	done := make(chan error, 1)
+	go func() { done <- guard.BeforeNodeExecution(context.Background(), session, node) }()
+
+	event := <-events
+	if event.Type != "execution:budget_exceeded" || event.Data["scope"] != "session" || event.Data["spent_tokens"] != 1000 {
+		t.Fatalf("Unexpected event %+v", event)
+	}
+	if session.Status != workflow.SessionPaused {
+		t.Fatalf("Expected the session to be paused, got %s", session.Status)
+	}
+
+	// Resuming without raising the limit pauses again
+	session.Resume()
+	if event := <-events; event.Type != "execution:budget_exceeded" {
+		t.Fatalf("Expected a second budget_exceeded event, got %s", event.Type)
+	}
+
+	session.SetBudgets(session.Budgets().Merge(workflow.Budgets{Session: &workflow.Budget{MaxTokens: 5000}}))
+	session.Resume()
+	if err := <-done; err != nil {
+		t.Errorf("Expected the guard to let the node run after raising the limit, got %v", err)
+	}
+}
+
+func TestBudgetGuard_GroupSpend(t *testing.T) {
+	session := workflow.NewSession(&workflow.GraphDefinition{ID: "g"}, nil)
+	session.Start(context.Background())
+	session.SetBudgets(workflow.Budgets{Group: &workflow.Budget{MaxCostUSD: 1}})
+	session.SetGroupSpend(workflow.Usage{CostUSD: 0.9})
+	session.AddUsage(workflow.Usage{CostUSD: 0.1})
+
+	exceeded := session.CheckBudget()
+	if exceeded == nil || exceeded.Scope != "group" {
+		t.Fatalf("Expected the group budget to be exceeded, got %v", exceeded)
+	}
+
+	// A stopped session cannot be resumed, so the guard fails the node
+	session.Stop()
+	events := make(chan workflow.StreamEvent, 1)
+	if err := NewBudgetGuard(events).BeforeNodeExecution(context.Background(), session, &workflow.Node{ID: "n1"}); err == nil {
+		t.Error("Expected an error for a stopped session over budget")
+	}
+}
@@ -0,0 +1,74 @@
+// Package tenant isolates workspaces. Every request and every background
+// session carries the tenant it acts for in its context; repositories scope
+// all reads and writes by it and refuse to run without one.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultID is the tenant that owns data created before tenants existed,
+// and the one used when authentication is disabled.
+var DefaultID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// ErrMissing is returned by repositories called without a tenant in the context.
+var ErrMissing = errors.New("no tenant in context")
+
+// Tenant is a workspace owning its users, groups, agents, workflows,
+// sessions and memories.
+type Tenant struct {
+	ID        uuid.UUID `json:"tenant_uuid" db:"tenant_uuid"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Repository defines the interface for tenant persistence. It is the only
+// repository not scoped by a tenant.
+type Repository interface {
+	Create(ctx context.Context, t *Tenant) error
+	List(ctx context.Context) ([]*Tenant, error)
+}
+
+type contextKey struct{}
+
+// WithID returns a context acting for the tenant.
+func WithID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant of ctx, if any.
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(contextKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
+
+// Require returns the tenant of ctx or ErrMissing.
+func Require(ctx context.Context) (uuid.UUID, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrMissing
+	}
+	return id, nil
+}
+
+// Detach returns a background context for the same tenant, for work that
+// outlives the request that started it (e.g. a workflow session).
+func Detach(ctx context.Context) context.Context {
+	if id, ok := FromContext(ctx); ok {
+		return WithID(context.Background(), id)
+	}
+	return context.Background()
+}
+
+// Same reports whether both contexts act for the same tenant, or both for
+// none. It guards state held outside the repositories, such as running
+// sessions, which always carry a tenant once the authz middleware is in place.
+func Same(a, b context.Context) bool {
+	ta, _ := FromContext(a)
+	tb, _ := FromContext(b)
+	return ta == tb
+}
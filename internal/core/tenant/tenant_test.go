@@ -0,0 +1,36 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestContext(t *testing.T) {
+	if _, err := Require(context.Background()); err != ErrMissing {
+		t.Errorf("expected ErrMissing, got %v", err)
+	}
+	if _, ok := FromContext(WithID(context.Background(), uuid.Nil)); ok {
+		t.Error("expected the nil tenant to be treated as missing")
+	}
+
+	id := uuid.New()
+	ctx, cancel := context.WithCancel(WithID(context.Background(), id))
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil {
+		t.Error("expected the detached context not to be canceled")
+	}
+	if got, err := Require(detached); err != nil || got != id {
+		t.Errorf("expected tenant %v, got %v (%v)", id, got, err)
+	}
+
+	if !Same(ctx, detached) {
+		t.Error("expected contexts of the same tenant to match")
+	}
+	if Same(ctx, WithID(context.Background(), uuid.New())) || Same(ctx, context.Background()) {
+		t.Error("expected different or missing tenants not to match")
+	}
+}
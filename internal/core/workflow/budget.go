@@ -0,0 +1,123 @@
+package workflow
+
+import "fmt"
+
+// Budget caps the spend of a session or of a group. Zero fields are unlimited.
+type Budget struct {
+	MaxTokens  int     `json:"max_tokens,omitempty"`
+	MaxCostUSD float64 `json:"max_cost_usd,omitempty"`
+}
+
+// Budgets holds the limits set at execute time. The session limit applies to
+// the session alone, the group limit to everything the session's group has
+// spent, this session included. A nil limit is unlimited.
+type Budgets struct {
+	Session *Budget `json:"session,omitempty"`
+	Group   *Budget `json:"group,omitempty"`
+}
+
+// Merge returns b with the limits present in update replaced.
+func (b Budgets) Merge(update Budgets) Budgets {
+	if update.Session != nil {
+		b.Session = update.Session
+	}
+	if update.Group != nil {
+		b.Group = update.Group
+	}
+	return b
+}
+
+// Usage is the token spend and its USD price, as reported by the LLM providers.
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		CostUSD:          u.CostUSD + other.CostUSD,
+	}
+}
+
+func (u Usage) Sub(other Usage) Usage {
+	return u.Add(Usage{
+		PromptTokens:     -other.PromptTokens,
+		CompletionTokens: -other.CompletionTokens,
+		CostUSD:          -other.CostUSD,
+	})
+}
+
+// Exceeds reports whether u has reached a limit of b.
+func (u Usage) Exceeds(b *Budget) bool {
+	if b == nil {
+		return false
+	}
+	return (b.MaxTokens > 0 && u.TotalTokens() >= b.MaxTokens) ||
+		(b.MaxCostUSD > 0 && u.CostUSD >= b.MaxCostUSD)
+}
+
+// BudgetExceeded describes the limit a session ran into.
+type BudgetExceeded struct {
+	Scope string // "session" or "group"
+	Limit Budget
+	Spent Usage
+}
+
+func (e *BudgetExceeded) Error() string {
+	return fmt.Sprintf("%s budget exceeded: spent %d tokens / $%.4f", e.Scope, e.Spent.TotalTokens(), e.Spent.CostUSD)
+}
+
+// SetBudgets replaces the limits of the session.
+func (s *Session) SetBudgets(b Budgets) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.budgets = b
+}
+
+func (s *Session) Budgets() Budgets {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.budgets
+}
+
+// SetGroupSpend records what the session's group spent in other sessions,
+// counted against the group limit together with the session's own usage.
+func (s *Session) SetGroupSpend(u Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groupSpend = u
+}
+
+// AddUsage accounts model usage to the session.
+func (s *Session) AddUsage(u Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = s.usage.Add(u)
+}
+
+// Usage returns what the session has spent so far.
+func (s *Session) Usage() Usage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.usage
+}
+
+// CheckBudget returns the first limit the session has reached, or nil.
+func (s *Session) CheckBudget() *BudgetExceeded {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.usage.Exceeds(s.budgets.Session) {
+		return &BudgetExceeded{Scope: "session", Limit: *s.budgets.Session, Spent: s.usage}
+	}
+	if group := s.groupSpend.Add(s.usage); group.Exceeds(s.budgets.Group) {
+		return &BudgetExceeded{Scope: "group", Limit: *s.budgets.Group, Spent: group}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ type StreamEvent struct {
 	Timestamp time.Time              `json:"timestamp"`
 	NodeID    string                 `json:"node_id,omitempty"`
 	Data      map[string]interface{} `json:"data,omitempty"`
+	Seq       uint64                 `json:"seq,omitempty"` // Per-session sequence number, assigned on delivery
 }
 
 // WorkflowContext handles state and data flow during execution
@@ -78,73 +78,46 @@ func GetModelPricing() map[string]struct {
 	return ModelPricing
 }
 
-// EstimateWorkflowCost calculates the approximate cost of a workflow execution.
-// This is a heuristic estimation based on node types and configured models.
-func EstimateWorkflowCost(graph *GraphDefinition) *CostEstimate {
-	estimate := &CostEstimate{
-		TotalCostUSD:   0,
-		TotalTokens:    0,
-		NodeBreakdown:  make(map[string]NodeCostEstimate),
-		AgentBreakdown: make(map[string]float64),
-	}
-
-	for _, node := range graph.Nodes {
-		nodeCost := estimateNodeCost(node)
-		estimate.TotalCostUSD += nodeCost.CostUSD
-		estimate.TotalTokens += nodeCost.Tokens
-		estimate.NodeBreakdown[node.ID] = nodeCost
-
-		if agentName, ok := node.Properties["agent_name"].(string); ok {
-			estimate.AgentBreakdown[agentName] += nodeCost.CostUSD
-		}
-	}
-
-	return estimate
-}
-
-func estimateNodeCost(node *Node) NodeCostEstimate {
-	// Base assumption: 1000 input tokens, 500 output tokens per "LLM interaction"
-	// This is very rough and implies the user knows the prompt size.
-	// We could try to count tokens in the prompt property if it exists.
-
-	avgInput := 1000
-	avgOutput := 500
-
-	// Logic nodes are free
-	if node.Type == NodeTypeStart || node.Type == NodeTypeEnd || node.Type == NodeTypeParallel || node.Type == NodeTypeVote || node.Type == NodeTypeLoop {
-		return NodeCostEstimate{CostUSD: 0, Tokens: 0}
-	}
-
-	// Try to get model from properties to find price
+// PriceUsage returns the USD cost of a model call from model_pricing.csv.
+// Unknown models are priced at the "default" rate.
+func PriceUsage(model string, promptTokens, completionTokens int) float64 {
 	pricing := GetModelPricing()
-	model := "default"
-	if m, ok := node.Properties["model"].(string); ok {
-		model = m
-	}
-	// Note: If agent node, logic usually comes from the Agent definition.
-	// For MVP, if property "model" is missing, we fall through to default.
-
 	price, ok := pricing[model]
 	if !ok {
 		price = pricing["default"]
 	}
-
-	cost := (float64(avgInput)/1000)*price.InputCostPer1K + (float64(avgOutput)/1000)*price.OutputCostPer1K
-
-	return NodeCostEstimate{
-		CostUSD: cost,
-		Tokens:  avgInput + avgOutput,
-	}
+	return (float64(promptTokens)/1000)*price.InputCostPer1K + (float64(completionTokens)/1000)*price.OutputCostPer1K
 }
 
+// CostEstimate is the expected spend of a workflow run, with the range it may
+// fall in. TotalCostUSD and TotalTokens are the expected figures.
 type CostEstimate struct {
 	TotalCostUSD   float64                     `json:"total_cost_usd"`
 	TotalTokens    int                         `json:"total_tokens"`
+	CostRange      CostRange                   `json:"cost_range"`
+	TokenRange     TokenRange                  `json:"token_range"`
 	NodeBreakdown  map[string]NodeCostEstimate `json:"node_breakdown"`
 	AgentBreakdown map[string]float64          `json:"agent_breakdown"`
+	Warnings       []string                    `json:"warnings,omitempty"` // Assumptions made, e.g. unknown agents
 }
 
 type NodeCostEstimate struct {
-	CostUSD float64 `json:"cost_usd"`
-	Tokens  int     `json:"tokens"`
+	CostUSD    float64    `json:"cost_usd"`
+	Tokens     int        `json:"tokens"`
+	Model      string     `json:"model,omitempty"`
+	Runs       int        `json:"runs"` // Maximum runs, > 1 inside loops
+	CostRange  CostRange  `json:"cost_range"`
+	TokenRange TokenRange `json:"token_range"`
+}
+
+type CostRange struct {
+	Min      float64 `json:"min"`
+	Expected float64 `json:"expected"`
+	Max      float64 `json:"max"`
+}
+
+type TokenRange struct {
+	Min      int `json:"min"`
+	Expected int `json:"expected"`
+	Max      int `json:"max"`
 }
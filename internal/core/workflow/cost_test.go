@@ -1,75 +1,126 @@
 package workflow
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/agent"
 )
 
-func TestEstimateWorkflowCost(t *testing.T) {
-	// Define a simple graph
+// agentRepo serves agents for estimates; other agent.Repository methods are no-ops.
+type agentRepo map[uuid.UUID]*agent.Agent
+
+func (r agentRepo) Create(ctx context.Context, a *agent.Agent) error { return nil }
+func (r agentRepo) GetByID(ctx context.Context, id uuid.UUID) (*agent.Agent, error) {
+	if a, ok := r[id]; ok {
+		return a, nil
+	}
+	return nil, fmt.Errorf("agent %s not found", id)
+}
+func (r agentRepo) List(ctx context.Context) ([]*agent.Agent, error) { return nil, nil }
+func (r agentRepo) Update(ctx context.Context, a *agent.Agent) error { return nil }
+func (r agentRepo) Delete(ctx context.Context, id uuid.UUID) error   { return nil }
+
+// countWords stands in for a tokenizer: one token per word.
+func countWords(model, text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestEstimator_AgentAware(t *testing.T) {
+	writer, reviewer := uuid.New(), uuid.New()
+	estimator := &Estimator{
+		Agents: agentRepo{
+			writer: {Name: "Writer", PersonaPrompt: "You write clear and short answers for busy readers",
+				ModelConfig: agent.ModelConfig{Model: "gpt-4"}},
+			reviewer: {Name: "Reviewer", PersonaPrompt: "You review the draft carefully",
+				ModelConfig: agent.ModelConfig{Model: "gemini-1.5-flash", MaxTokens: 200}},
+		},
+		DefaultModel: "gpt-4o",
+		CountTokens:  countWords,
+	}
 	graph := &GraphDefinition{
+		StartNodeID: "start",
 		Nodes: map[string]*Node{
-			"start": {
-				ID:   "start",
-				Type: NodeTypeStart,
-			},
-			"agent_1": {
-				ID:   "agent_1",
-				Type: NodeTypeAgent,
-				Properties: map[string]interface{}{
-					"agent_name": "Writer",
-					"model":      "gpt-4",
-				},
-			},
-			"agent_2": {
-				ID:   "agent_2",
-				Type: NodeTypeAgent,
-				Properties: map[string]interface{}{
-					"agent_name": "Reviewer",
-					"model":      "gemini-1.5-flash", // Should be cheap
-				},
-			},
-			"vote": {
-				ID:   "vote",
-				Type: NodeTypeVote,
-			},
-			"end": {
-				ID:   "end",
-				Type: NodeTypeEnd,
-			},
+			"start":  {ID: "start", Type: NodeTypeStart, NextIDs: []string{"write"}},
+			"write":  {ID: "write", Type: NodeTypeAgent, Properties: map[string]interface{}{"agent_uuid": writer.String()}, NextIDs: []string{"review"}},
+			"review": {ID: "review", Type: NodeTypeAgent, Properties: map[string]interface{}{"agent_uuid": reviewer.String()}},
 		},
 	}
+	input := map[string]interface{}{"proposal": "adopt a four day week", "group_uuid": "g1"}
 
-	estimate := EstimateWorkflowCost(graph)
+	estimate := estimator.Estimate(context.Background(), graph, input)
 
-	if estimate.TotalTokens != 3000 { // 2 Agents * (1000 input + 500 output) = 3000
-		t.Errorf("Expected 3000 tokens, got %d", estimate.TotalTokens)
+	// write: persona (9) + input (6) prompt tokens, 500 expected completion tokens on gpt-4
+	write := estimate.NodeBreakdown["write"]
+	if write.Model != "gpt-4" || write.Tokens != 9+6+500 {
+		t.Errorf("Expected gpt-4 with 515 tokens, got %+v", write)
+	}
+	if want := PriceUsage("gpt-4", 15, 500); write.CostUSD != want || estimate.AgentBreakdown["Writer"] != want {
+		t.Errorf("Expected writer cost %f, got %f / %v", want, write.CostUSD, estimate.AgentBreakdown)
 	}
 
-	// Calculate expected cost from CSV pricing
-	// gpt-4: (1*0.03) + (0.5*0.06) = 0.03 + 0.03 = 0.06
-	// gemini-1.5-flash: (1*0.000075) + (0.5*0.0003) = 0.000075 + 0.00015 = 0.000225
-	// Total: 0.060225
-	expectedCost := 0.060225
-	if estimate.TotalCostUSD != expectedCost {
-		t.Errorf("Expected cost %f, got %f", expectedCost, estimate.TotalCostUSD)
+	// review also reads the writer's output and is capped at 200 completion tokens
+	review := estimate.NodeBreakdown["review"]
+	if review.Model != "gemini-1.5-flash" || review.Tokens != 5+6+500+200 {
+		t.Errorf("Expected gemini-1.5-flash with 711 tokens, got %+v", review)
+	}
+	if review.TokenRange.Min != 5+6+100+100 || review.TokenRange.Max != 5+6+2048+200 {
+		t.Errorf("Unexpected review token range %+v", review.TokenRange)
 	}
 
-	// Check breakdown
-	if val, ok := estimate.AgentBreakdown["Writer"]; !ok || val != 0.06 {
-		t.Errorf("Writer cost incorrect, got %v", val)
+	if _, ok := estimate.NodeBreakdown["start"]; ok {
+		t.Error("Logic nodes should be free")
+	}
+	r := estimate.CostRange
+	if !(r.Min < r.Expected && r.Expected < r.Max) || estimate.TotalCostUSD != r.Expected {
+		t.Errorf("Expected min < expected < max with the total at expected, got %+v / %f", r, estimate.TotalCostUSD)
+	}
+	if len(estimate.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", estimate.Warnings)
 	}
 }
 
-func TestEstimateWorkflowCost_LogicNodesFree(t *testing.T) {
+func TestEstimator_LoopsAndUnknownAgents(t *testing.T) {
+	estimator := &Estimator{DefaultModel: "gpt-4o", CountTokens: countWords}
 	graph := &GraphDefinition{
+		StartNodeID: "start",
 		Nodes: map[string]*Node{
-			"loop": {ID: "loop", Type: NodeTypeLoop},
-			"vote": {ID: "vote", Type: NodeTypeVote},
+			"start": {ID: "start", Type: NodeTypeStart, NextIDs: []string{"draft"}},
+			"draft": {ID: "draft", Type: NodeTypeAgent, Properties: map[string]interface{}{"agent_uuid": "system_surgeon"}, NextIDs: []string{"loop"}},
+			"loop":  {ID: "loop", Type: NodeTypeLoop, Properties: map[string]interface{}{"max_rounds": float64(5)}, NextIDs: []string{"draft", "vote"}},
+			"vote":  {ID: "vote", Type: NodeTypeVote},
 		},
 	}
 
-	estimate := EstimateWorkflowCost(graph)
-	if estimate.TotalCostUSD != 0 {
-		t.Errorf("Logic nodes should be free, got %f", estimate.TotalCostUSD)
+	estimate := estimator.Estimate(context.Background(), graph, nil)
+
+	draft := estimate.NodeBreakdown["draft"]
+	if draft.Runs != 5 || draft.Model != "gpt-4o" {
+		t.Fatalf("Expected 5 runs on the default model, got %+v", draft)
+	}
+	if draft.TokenRange.Min != 100 || draft.TokenRange.Expected != 3*500 || draft.TokenRange.Max != 5*2048 {
+		t.Errorf("Expected 1 / 3 / 5 rounds, got %+v", draft.TokenRange)
+	}
+	if len(estimate.NodeBreakdown) != 1 {
+		t.Errorf("Loop and vote nodes should be free, got %v", estimate.NodeBreakdown)
+	}
+	if len(estimate.Warnings) != 1 {
+		t.Errorf("Expected a warning for the unresolved agent, got %v", estimate.Warnings)
+	}
+}
+
+func TestPriceUsage(t *testing.T) {
+	// gpt-4: $0.03 in / $0.06 out per 1K tokens
+	if got := PriceUsage("gpt-4", 2000, 1000); got < 0.1199 || got > 0.1201 {
+		t.Errorf("Expected $0.12 for gpt-4, got %f", got)
+	}
+
+	unknown := PriceUsage("no-such-model", 1000, 1000)
+	fallback := PriceUsage("default", 1000, 1000)
+	if unknown != fallback || unknown == 0 {
+		t.Errorf("Expected unknown models at the default rate, got %f vs %f", unknown, fallback)
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"github.com/hrygo/council/internal/core/tenant"
 )
 
 // Engine orchestrates the workflow execution
@@ -29,6 +31,13 @@ type Engine struct {
 	joinMu        sync.Mutex                          // Mutex for join operations
 	MergeStrategy MergeStrategy                       // Pluggable merge strategy
 	SessionRepo   SessionRepository                   // Injected persistence
+	Ledger        CostLedger                          // Records every LLM call; optional
+
+	// Durable state for recovery
+	outputs    map[string]map[string]interface{} // Last output per completed node
+	iterations map[string]int                    // Run count per node, journaled as loop iteration
+	inflight   int                               // Resumed branches still executing
+	settle     chan struct{}                     // Wakes Wait() when suspension/inflight state changes
 }
 
 // NewEngine creates a new workflow engine
@@ -42,6 +51,9 @@ func NewEngine(session *Session) *Engine {
 		pendingInputs: make(map[string][]map[string]interface{}),
 		MergeStrategy: &DefaultMergeStrategy{}, // Default strategy, can be overridden
 		NodeFactory:   &DefaultNodeFactory{},   // Default Factory
+		outputs:       make(map[string]map[string]interface{}),
+		iterations:    make(map[string]int),
+		settle:        make(chan struct{}, 1),
 	}
 	// Resume status from session if available
 	if session.NodeStatuses != nil {
@@ -49,6 +61,9 @@ func NewEngine(session *Session) *Engine {
 			e.Status[k] = v
 		}
 	}
+	for k, v := range session.NodeOutputs {
+		e.outputs[k] = v
+	}
 	e.computeInDegrees()
 	return e
 }
@@ -57,6 +72,10 @@ func (e *Engine) SetSessionRepository(repo SessionRepository) {
 	e.SessionRepo = repo
 }
 
+func (e *Engine) SetCostLedger(ledger CostLedger) {
+	e.Ledger = ledger
+}
+
 // Run executes the workflow from the start node
 func (e *Engine) Run(ctx context.Context) error {
 	// 1. Basic Validation
@@ -127,6 +146,19 @@ func (e *Engine) executeNodeWithoutDownstream(ctx context.Context, nodeID string
 	}
 	input["session_id"] = e.Session.ID
 
+	// Nested sequence: runs as one composite step
+	if node.Type == NodeTypeSequence {
+		output, err := e.runSequence(ctx, node, input, nil)
+		if err != nil {
+			e.emitError(nodeID, err)
+			e.updateStatus(nodeID, StatusFailed)
+			return nil, err
+		}
+		e.recordOutput(nodeID, output)
+		e.updateStatus(nodeID, StatusCompleted)
+		return output, nil
+	}
+
 	// Standard Processing using Factory
 	processor, err := e.NodeFactory.CreateNode(node, FactoryDeps{Session: e.Session})
 	if err != nil {
@@ -135,12 +167,17 @@ func (e *Engine) executeNodeWithoutDownstream(ctx context.Context, nodeID string
 		return nil, err
 	}
 
-	output, err := processor.Process(ctx, input, e.StreamChannel)
+	exec := e.beginExecution(node, input)
+	stream, flush := e.tapStream(exec)
+	output, err := processor.Process(ctx, input, stream)
+	flush()
 	if err != nil {
 		if err == ErrSuspended {
+			e.finishExecution(exec, StatusSuspended, nil, nil)
 			e.updateStatus(nodeID, StatusSuspended)
 			return nil, nil // Suspended execution
 		}
+		e.finishExecution(exec, StatusFailed, nil, err)
 		e.updateStatus(nodeID, StatusFailed)
 		e.emitError(nodeID, err)
 		return nil, err
@@ -151,11 +188,14 @@ func (e *Engine) executeNodeWithoutDownstream(ctx context.Context, nodeID string
 		var mwErr error
 		output, mwErr = mw.AfterNodeExecution(ctx, e.Session, node, output)
 		if mwErr != nil {
+			e.finishExecution(exec, StatusFailed, output, mwErr)
 			e.emitError(nodeID, fmt.Errorf("middleware %s failed post-processing: %w", mw.Name(), mwErr))
 			return nil, mwErr
 		}
 	}
 
+	e.finishExecution(exec, StatusCompleted, output, nil)
+	e.recordOutput(nodeID, output)
 	e.updateStatus(nodeID, StatusCompleted)
 
 	// NOTE: Unlike executeNode, we DON'T call deliverToDownstream here
@@ -225,6 +265,9 @@ func (e *Engine) executeNode(ctx context.Context, nodeID string, input map[strin
 		e.handleParallel(ctx, node, input)
 		return nil
 	}
+	if node.Type == NodeTypeSequence {
+		return e.executeSequence(ctx, node, input, nil)
+	}
 
 	// Standard Processing using Factory
 	processor, err := e.NodeFactory.CreateNode(node, FactoryDeps{Session: e.Session})
@@ -234,12 +277,17 @@ func (e *Engine) executeNode(ctx context.Context, nodeID string, input map[strin
 		return err
 	}
 
-	output, err = processor.Process(ctx, input, e.StreamChannel)
+	exec := e.beginExecution(node, input)
+	stream, flush := e.tapStream(exec)
+	output, err = processor.Process(ctx, input, stream)
+	flush()
 	if err != nil {
 		if err == ErrSuspended {
+			e.finishExecution(exec, StatusSuspended, nil, nil)
 			e.updateStatus(nodeID, StatusSuspended)
 			return nil // Suspended execution
 		}
+		e.finishExecution(exec, StatusFailed, nil, err)
 		e.updateStatus(nodeID, StatusFailed)
 		e.emitError(nodeID, err)
 		return err
@@ -250,11 +298,14 @@ func (e *Engine) executeNode(ctx context.Context, nodeID string, input map[strin
 		var mwErr error
 		output, mwErr = mw.AfterNodeExecution(ctx, e.Session, node, output)
 		if mwErr != nil {
+			e.finishExecution(exec, StatusFailed, output, mwErr)
 			e.emitError(nodeID, fmt.Errorf("middleware %s failed post-processing: %w", mw.Name(), mwErr))
 			return mwErr
 		}
 	}
 
+	e.finishExecution(exec, StatusCompleted, output, nil)
+	e.recordOutput(nodeID, output)
 	e.updateStatus(nodeID, StatusCompleted)
 
 	// Determine Routing
@@ -275,6 +326,13 @@ func (e *Engine) executeNode(ctx context.Context, nodeID string, input map[strin
 }
 
 func (e *Engine) handleParallel(ctx context.Context, node *Node, input map[string]interface{}) {
+	e.runParallel(ctx, node, input, nil)
+}
+
+// runParallel executes the branches of a parallel node. Branches present in
+// reuse are not executed again; their recorded output is delivered instead
+// (used when recovering a parallel step that was interrupted).
+func (e *Engine) runParallel(ctx context.Context, node *Node, input map[string]interface{}, reuse map[string]map[string]interface{}) {
 	// Set parallel node status to running
 	log.Printf("[Engine] Setting parallel node %s to RUNNING", node.ID)
 	e.updateStatus(node.ID, StatusRunning)
@@ -300,6 +358,11 @@ func (e *Engine) handleParallel(ctx context.Context, node *Node, input map[strin
 		go func(cid string) {
 			defer wg.Done()
 
+			if out, ok := reuse[cid]; ok {
+				resultsChan <- branchResult{nodeID: cid, output: out}
+				return
+			}
+
 			// Clone input map to avoid Data Race
 			clonedInput := make(map[string]interface{}, len(input))
 			for k, v := range input {
@@ -371,13 +434,13 @@ func (e *Engine) updateStatus(nodeID string, status NodeStatus) {
 
 	log.Printf("[Engine] updateStatus: %s -> %s", nodeID, status)
 
-	// Persist status if repo is injected
+	// Persist status if repo is injected.
+	// Done synchronously so that the stored order matches the execution order,
+	// which session recovery relies on.
 	if e.SessionRepo != nil {
-		go func() {
-			if err := e.SessionRepo.UpdateNodeStatus(context.Background(), e.Session.ID, nodeID, status); err != nil {
-				log.Printf("Failed to persist status for node %s: %v", nodeID, err)
-			}
-		}()
+		if err := e.SessionRepo.UpdateNodeStatus(tenant.Detach(e.Session.Context()), e.Session.ID, nodeID, status); err != nil {
+			log.Printf("Failed to persist status for node %s: %v", nodeID, err)
+		}
 	}
 
 	event := StreamEvent{
@@ -390,6 +453,33 @@ func (e *Engine) updateStatus(nodeID string, status NodeStatus) {
 	e.StreamChannel <- event
 }
 
+// recordOutput keeps the node output and persists it together with the
+// session context, so that a restarted engine can rebuild downstream inputs.
+func (e *Engine) recordOutput(nodeID string, output map[string]interface{}) {
+	e.Mu.Lock()
+	e.outputs[nodeID] = output
+	e.Mu.Unlock()
+
+	if e.SessionRepo == nil {
+		return
+	}
+	ctx := tenant.Detach(e.Session.Context())
+	if err := e.SessionRepo.UpdateNodeOutput(ctx, e.Session.ID, nodeID, output); err != nil {
+		log.Printf("Failed to persist output for node %s: %v", nodeID, err)
+	}
+	if err := e.SessionRepo.UpdateContextData(ctx, e.Session.ID, e.Session.ContextSnapshot()); err != nil {
+		log.Printf("Failed to persist context data for session %s: %v", e.Session.ID, err)
+	}
+}
+
+// GetOutput returns the last recorded output of a node.
+func (e *Engine) GetOutput(nodeID string) (map[string]interface{}, bool) {
+	e.Mu.RLock()
+	defer e.Mu.RUnlock()
+	out, ok := e.outputs[nodeID]
+	return out, ok
+}
+
 func (e *Engine) GetStatus(nodeID string) NodeStatus {
 	e.Mu.RLock()
 	defer e.Mu.RUnlock()
@@ -424,7 +514,12 @@ func (e *Engine) ResumeNode(ctx context.Context, nodeID string, output map[strin
 		return fmt.Errorf("node %s is not suspended (status: %s)", nodeID, status)
 	}
 
+	// Track the resumed branch so Wait() does not return before it finishes
+	e.trackInflight(1)
+
 	// Update Status
+	e.finishExecution(e.resumeExecution(node), StatusCompleted, output, nil)
+	e.recordOutput(nodeID, output)
 	e.updateStatus(nodeID, StatusCompleted)
 
 	e.StreamChannel <- StreamEvent{
@@ -453,6 +548,7 @@ func (e *Engine) ResumeNode(ctx context.Context, nodeID string, output map[strin
 			var errRoute error
 			nextIDs, errRoute = router.GetNextNodes(workflowCtx, output, node.NextIDs)
 			if errRoute != nil {
+				e.trackInflight(-1)
 				e.emitError(nodeID, fmt.Errorf("routing failed on resume: %w", errRoute))
 				return errRoute
 			}
@@ -462,11 +558,56 @@ func (e *Engine) ResumeNode(ctx context.Context, nodeID string, output map[strin
 	}
 
 	// Use deliverToDownstream for consistency with Join mechanism (SPEC-1206)
-	go e.deliverToDownstream(workflowCtx, nodeID, output, nextIDs)
+	go func() {
+		defer e.trackInflight(-1)
+		e.deliverToDownstream(workflowCtx, nodeID, output, nextIDs)
+	}()
 
 	return nil
 }
 
+func (e *Engine) trackInflight(delta int) {
+	e.Mu.Lock()
+	e.inflight += delta
+	e.Mu.Unlock()
+
+	select {
+	case e.settle <- struct{}{}:
+	default:
+	}
+}
+
+// HasSuspendedNodes reports whether any node is waiting for external input.
+func (e *Engine) HasSuspendedNodes() bool {
+	e.Mu.RLock()
+	defer e.Mu.RUnlock()
+	for _, status := range e.Status {
+		if status == StatusSuspended {
+			return true
+		}
+	}
+	return false
+}
+
+// Wait blocks until the engine has settled: no node is suspended and no
+// resumed branch is still executing. It returns early if ctx is cancelled.
+func (e *Engine) Wait(ctx context.Context) error {
+	for {
+		e.Mu.RLock()
+		busy := e.inflight > 0
+		e.Mu.RUnlock()
+
+		if !busy && !e.HasSuspendedNodes() {
+			return nil
+		}
+		select {
+		case <-e.settle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // computeInDegrees calculates the in-degree for each node in the graph.
 func (e *Engine) computeInDegrees() {
 	e.inDegree = make(map[string]int)
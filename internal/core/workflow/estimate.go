@@ -0,0 +1,333 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/agent"
+)
+
+// Estimator prices a workflow before it runs. Agent nodes are priced with the
+// model and persona of their agent, prompts are counted with the model's
+// tokenizer, loop bodies repeat up to max_rounds, and every node reads the
+// outputs of the LLM nodes before it, so a node after a parallel fan-out pays
+// for all of its branches.
+type Estimator struct {
+	Agents       agent.Repository             // Resolves agent nodes; nil prices them at DefaultModel
+	DefaultModel string                       // Model of nodes and agents that configure none
+	CountTokens  func(model, text string) int // Tokenizer; nil assumes 4 characters per token
+}
+
+// Indexes of the low, expected and high figure of a span.
+const (
+	estLow = iota
+	estExpected
+	estHigh
+)
+
+// span holds a low, expected and high count.
+type span [3]int
+
+var (
+	singleRun = span{1, 1, 1}
+
+	// Completion tokens per call, unless the model config caps them lower
+	defaultCompletion = span{100, 500, 2048}
+)
+
+const (
+	maxAgentIterations = 5 // ReAct rounds of AgentProcessor
+	defaultMaxClaims   = 5 // Claims FactCheckProcessor extracts per run
+)
+
+// llmNode describes the LLM calls of a single run of a node.
+type llmNode struct {
+	model  string
+	agent  string // Agent name, for the agent breakdown
+	system int    // Persona or system prompt tokens
+	output span   // Completion tokens per call
+	calls  span   // Calls per run
+}
+
+// Estimate prices a run of graph with the given execute input. Assumptions
+// that could not be checked, such as unknown agents, are listed in Warnings.
+func (e *Estimator) Estimate(ctx context.Context, graph *GraphDefinition, input map[string]interface{}) *CostEstimate {
+	estimate := &CostEstimate{
+		NodeBreakdown:  make(map[string]NodeCostEstimate),
+		AgentBreakdown: make(map[string]float64),
+	}
+
+	nodes := make(map[string]*llmNode)
+	for id, node := range graph.Nodes {
+		if n := e.describe(ctx, node, estimate); n != nil {
+			nodes[id] = n
+		}
+	}
+
+	runs := loopRuns(graph)
+	preds := predecessors(graph)
+	inputText := collectText(input)
+
+	for id, n := range nodes {
+		var upstream span
+		for _, p := range upstreamLLMNodes(id, preds, nodes) {
+			for r := range upstream {
+				upstream[r] += nodes[p].output[r]
+			}
+		}
+		nodeRuns, ok := runs[id]
+		if !ok {
+			nodeRuns = singleRun
+		}
+
+		prompt := n.system + e.count(n.model, inputText)
+		var cost [3]float64
+		var tokens span
+		for r := range tokens {
+			calls := nodeRuns[r] * n.calls[r]
+			tokens[r] = calls * (prompt + upstream[r] + n.output[r])
+			cost[r] = float64(calls) * PriceUsage(n.model, prompt+upstream[r], n.output[r])
+		}
+
+		estimate.NodeBreakdown[id] = NodeCostEstimate{
+			CostUSD:    cost[estExpected],
+			Tokens:     tokens[estExpected],
+			Model:      n.model,
+			Runs:       nodeRuns[estHigh],
+			CostRange:  CostRange{Min: cost[estLow], Expected: cost[estExpected], Max: cost[estHigh]},
+			TokenRange: TokenRange{Min: tokens[estLow], Expected: tokens[estExpected], Max: tokens[estHigh]},
+		}
+		estimate.CostRange.Min += cost[estLow]
+		estimate.CostRange.Expected += cost[estExpected]
+		estimate.CostRange.Max += cost[estHigh]
+		estimate.TokenRange.Min += tokens[estLow]
+		estimate.TokenRange.Expected += tokens[estExpected]
+		estimate.TokenRange.Max += tokens[estHigh]
+		if n.agent != "" {
+			estimate.AgentBreakdown[n.agent] += cost[estExpected]
+		}
+	}
+
+	estimate.TotalCostUSD = estimate.CostRange.Expected
+	estimate.TotalTokens = estimate.TokenRange.Expected
+	sort.Strings(estimate.Warnings)
+	return estimate
+}
+
+// describe returns the LLM calls of a node, or nil for nodes that make none.
+func (e *Estimator) describe(ctx context.Context, node *Node, estimate *CostEstimate) *llmNode {
+	model, _ := node.Properties["model"].(string)
+	if model == "" {
+		model = e.DefaultModel
+	}
+	n := &llmNode{model: model, output: capOutput(node.Properties["max_tokens"]), calls: singleRun}
+
+	switch node.Type {
+	case NodeTypeAgent:
+		n.agent = nodeName(node)
+		if tools, _ := node.Properties["tools"].([]interface{}); len(tools) > 0 {
+			n.calls = span{1, 2, maxAgentIterations}
+		}
+		ag, err := e.lookupAgent(ctx, node)
+		if err != nil {
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("node %s: %v; priced at the default model", node.ID, err))
+			return n
+		}
+		n.agent = ag.Name
+		if ag.ModelConfig.Model != "" {
+			n.model = ag.ModelConfig.Model
+		}
+		n.system = e.count(n.model, ag.PersonaPrompt)
+		n.output = capOutput(ag.ModelConfig.MaxTokens)
+		if ag.Capabilities.WebSearch || ag.Capabilities.CodeExecution {
+			n.calls = span{1, 2, maxAgentIterations}
+		}
+	case NodeTypeLLM, NodeTypeEnd:
+		// The end node writes the final summary
+		var prompts []string
+		for _, key := range []string{"system_prompt", "prompt_template", "summary_prompt"} {
+			if p, ok := node.Properties[key].(string); ok {
+				prompts = append(prompts, p)
+			}
+		}
+		n.system = e.count(n.model, strings.Join(prompts, "\n"))
+	case NodeTypeFactCheck:
+		// One call extracts the claims, then one call verifies each claim
+		maxClaims := toInt(node.Properties["max_claims"])
+		if maxClaims <= 0 {
+			maxClaims = defaultMaxClaims
+		}
+		n.calls = span{1, 1 + (maxClaims+1)/2, 1 + maxClaims}
+	default:
+		return nil
+	}
+	return n
+}
+
+func (e *Estimator) lookupAgent(ctx context.Context, node *Node) (*agent.Agent, error) {
+	agentID, _ := node.Properties["agent_uuid"].(string)
+	if agentID == "" {
+		agentID, _ = node.Properties["agent_id"].(string)
+	}
+	if e.Agents == nil {
+		return nil, fmt.Errorf("agent %q not resolved", agentID)
+	}
+	id, err := uuid.Parse(agentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent id %q", agentID)
+	}
+	ag, err := e.Agents.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("agent %s not found", agentID)
+	}
+	return ag, nil
+}
+
+func (e *Estimator) count(model, text string) int {
+	if e.CountTokens == nil {
+		return len(text) / 4
+	}
+	return e.CountTokens(model, text)
+}
+
+// capOutput returns the completion tokens per call for a configured
+// max_tokens (int or JSON number); zero keeps the defaults.
+func capOutput(maxTokens interface{}) span {
+	limit := toInt(maxTokens)
+	output := defaultCompletion
+	if limit <= 0 {
+		return output
+	}
+	for r := range output {
+		output[r] = min(output[r], limit)
+	}
+	output[estHigh] = limit
+	return output
+}
+
+func nodeName(node *Node) string {
+	if name, ok := node.Properties["agent_name"].(string); ok && name != "" {
+		return name
+	}
+	if node.Name != "" {
+		return node.Name
+	}
+	return node.ID
+}
+
+// loopRuns returns how often each node in a loop body runs. The body of a
+// loop node is everything on a path from its continue branch (first next_id)
+// back to it. Nested loops multiply.
+func loopRuns(graph *GraphDefinition) map[string]span {
+	runs := make(map[string]span)
+	preds := predecessors(graph)
+	for id, node := range graph.Nodes {
+		if node.Type != NodeTypeLoop || len(node.NextIDs) < 2 {
+			continue
+		}
+		rounds := toInt(node.Properties["max_rounds"])
+		if rounds < 1 {
+			rounds = 1
+		}
+		reachesLoop := reachable([]string{id}, func(n string) []string { return preds[n] }, "")
+		body := reachable([]string{node.NextIDs[0]}, func(n string) []string {
+			if next, ok := graph.Nodes[n]; ok {
+				return next.NextIDs
+			}
+			return nil
+		}, id)
+		body[id] = true
+
+		for n := range body {
+			if !reachesLoop[n] {
+				continue
+			}
+			r, ok := runs[n]
+			if !ok {
+				r = singleRun
+			}
+			runs[n] = span{r[estLow], r[estExpected] * ((rounds + 1) / 2), r[estHigh] * rounds}
+		}
+	}
+	return runs
+}
+
+func predecessors(graph *GraphDefinition) map[string][]string {
+	preds := make(map[string][]string)
+	for id, node := range graph.Nodes {
+		for _, next := range node.NextIDs {
+			preds[next] = append(preds[next], id)
+		}
+	}
+	return preds
+}
+
+// reachable returns the nodes reachable from start along edges, without
+// passing through stop.
+func reachable(start []string, edges func(string) []string, stop string) map[string]bool {
+	seen := make(map[string]bool)
+	queue := append([]string(nil), start...)
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if seen[n] || n == stop {
+			continue
+		}
+		seen[n] = true
+		queue = append(queue, edges(n)...)
+	}
+	return seen
+}
+
+// upstreamLLMNodes returns the nearest LLM nodes before id, looking through
+// logic nodes such as parallel, vote or loop.
+func upstreamLLMNodes(id string, preds map[string][]string, nodes map[string]*llmNode) []string {
+	var found []string
+	seen := map[string]bool{id: true}
+	queue := append([]string(nil), preds[id]...)
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		if _, ok := nodes[n]; ok {
+			found = append(found, n)
+			continue
+		}
+		queue = append(queue, preds[n]...)
+	}
+	return found
+}
+
+// collectText joins the text values of the execute input, i.e. the documents
+// and proposal the first nodes will read.
+func collectText(v interface{}) string {
+	var parts []string
+	switch t := v.(type) {
+	case string:
+		return t
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if s := collectText(t[k]); s != "" {
+				parts = append(parts, s)
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			if s := collectText(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,153 @@
+package workflow
+
+import (
+	"log"
+	"time"
+
+	"github.com/hrygo/council/internal/core/tenant"
+)
+
+// NodeExecution is an append-only journal record of a single node run:
+// what the node saw, what it produced and what it cost.
+type NodeExecution struct {
+	ID               int64                  `json:"execution_id"`
+	SessionID        string                 `json:"session_uuid"`
+	NodeID           string                 `json:"node_id"`
+	NodeType         NodeType               `json:"node_type"`
+	Iteration        int                    `json:"iteration"` // 1-based run count of this node (loop round)
+	Status           NodeStatus             `json:"status"`
+	Input            map[string]interface{} `json:"input"`
+	Output           map[string]interface{} `json:"output,omitempty"`
+	Error            string                 `json:"error,omitempty"`
+	PromptTokens     int                    `json:"prompt_tokens"`
+	CompletionTokens int                    `json:"completion_tokens"`
+	TotalTokens      int                    `json:"total_tokens"`
+	CostUSD          float64                `json:"cost_usd"`
+	StartedAt        time.Time              `json:"started_at"`
+	EndedAt          time.Time              `json:"ended_at"`
+	DurationMs       int64                  `json:"duration_ms"`
+}
+
+// beginExecution opens a journal record for a node run.
+func (e *Engine) beginExecution(node *Node, input map[string]interface{}) *NodeExecution {
+	e.Mu.Lock()
+	e.iterations[node.ID]++
+	iteration := e.iterations[node.ID]
+	e.Mu.Unlock()
+
+	return &NodeExecution{
+		SessionID: e.Session.ID,
+		NodeID:    node.ID,
+		NodeType:  node.Type,
+		Iteration: iteration,
+		Input:     cloneMap(input),
+		StartedAt: time.Now(),
+	}
+}
+
+// resumeExecution opens a record completing the current run of a suspended
+// node (e.g. a HumanReview decision). It does not start a new iteration.
+func (e *Engine) resumeExecution(node *Node) *NodeExecution {
+	e.Mu.RLock()
+	iteration := e.iterations[node.ID]
+	e.Mu.RUnlock()
+
+	return &NodeExecution{
+		SessionID: e.Session.ID,
+		NodeID:    node.ID,
+		NodeType:  node.Type,
+		Iteration: iteration,
+		StartedAt: time.Now(),
+	}
+}
+
+// finishExecution closes the record and appends it to the journal.
+func (e *Engine) finishExecution(exec *NodeExecution, status NodeStatus, output map[string]interface{}, err error) {
+	exec.Status = status
+	exec.Output = output
+	if err != nil {
+		exec.Error = err.Error()
+	}
+	exec.EndedAt = time.Now()
+	exec.DurationMs = exec.EndedAt.Sub(exec.StartedAt).Milliseconds()
+	if exec.TotalTokens == 0 {
+		exec.TotalTokens = exec.PromptTokens + exec.CompletionTokens
+	}
+
+	if e.SessionRepo == nil {
+		return
+	}
+	if err := e.SessionRepo.AppendExecution(tenant.Detach(e.Session.Context()), exec); err != nil {
+		log.Printf("Failed to journal execution of node %s: %v", exec.NodeID, err)
+	}
+}
+
+// tapStream returns a channel to hand to a processor. Events are forwarded to
+// StreamChannel in order while token usage is accumulated into exec,
+// accounted to the session and recorded in the cost ledger.
+// The returned function must be called once the processor has returned; it
+// waits until all events have been forwarded.
+func (e *Engine) tapStream(exec *NodeExecution) (chan StreamEvent, func()) {
+	tap := make(chan StreamEvent, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range tap {
+			if event.Type == "token_usage" {
+				usage := Usage{
+					PromptTokens:     toInt(event.Data["input_tokens"]),
+					CompletionTokens: toInt(event.Data["output_tokens"]),
+				}
+				usage.CostUSD, _ = event.Data["estimated_cost_usd"].(float64)
+				exec.PromptTokens += usage.PromptTokens
+				exec.CompletionTokens += usage.CompletionTokens
+				exec.CostUSD += usage.CostUSD
+				e.Session.AddUsage(usage)
+				e.recordCost(exec, event, usage)
+			}
+			e.StreamChannel <- event
+		}
+	}()
+	return tap, func() {
+		close(tap)
+		<-done
+	}
+}
+
+// recordCost appends the LLM call reported by a token_usage event to the ledger.
+func (e *Engine) recordCost(exec *NodeExecution, event StreamEvent, usage Usage) {
+	if e.Ledger == nil {
+		return
+	}
+	entry := &LedgerEntry{
+		SessionID:        exec.SessionID,
+		NodeID:           exec.NodeID,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CostUSD:          usage.CostUSD,
+		LatencyMs:        int64(toInt(event.Data["latency_ms"])),
+		CreatedAt:        event.Timestamp,
+	}
+	entry.AgentID, _ = event.Data["agent_id"].(string)
+	entry.Provider, _ = event.Data["provider"].(string)
+	entry.Model, _ = event.Data["model"].(string)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if err := e.Ledger.Record(tenant.Detach(e.Session.Context()), entry); err != nil {
+		log.Printf("Failed to record cost of node %s: %v", exec.NodeID, err)
+	}
+}
+
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,169 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// journalRepo captures journal records and node statuses; other
+// SessionRepository methods are no-ops.
+type journalRepo struct {
+	mu       sync.Mutex
+	execs    []*NodeExecution
+	statuses map[string]NodeStatus
+}
+
+func (r *journalRepo) Create(ctx context.Context, session *Session, groupID string, workflowID string) error {
+	return nil
+}
+func (r *journalRepo) Get(ctx context.Context, id string) (*SessionEntity, error) { return nil, nil }
+func (r *journalRepo) UpdateStatus(ctx context.Context, id string, status SessionStatus) error {
+	return nil
+}
+func (r *journalRepo) UpdateNodeStatus(ctx context.Context, sessionID string, nodeID string, status NodeStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.statuses == nil {
+		r.statuses = make(map[string]NodeStatus)
+	}
+	r.statuses[nodeID] = status
+	return nil
+}
+func (r *journalRepo) UpdateNodeOutput(ctx context.Context, sessionID string, nodeID string, output map[string]interface{}) error {
+	return nil
+}
+func (r *journalRepo) UpdateContextData(ctx context.Context, sessionID string, data map[string]interface{}) error {
+	return nil
+}
+func (r *journalRepo) ListRecoverable(ctx context.Context) ([]*SessionEntity, error) { return nil, nil }
+func (r *journalRepo) AppendExecution(ctx context.Context, exec *NodeExecution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.execs = append(r.execs, exec)
+	return nil
+}
+func (r *journalRepo) ListExecutions(ctx context.Context, sessionID string) ([]*NodeExecution, error) {
+	return r.execs, nil
+}
+func (r *journalRepo) UpdateBudgets(ctx context.Context, sessionID string, budgets Budgets) error {
+	return nil
+}
+func (r *journalRepo) GroupUsage(ctx context.Context, groupID string) (Usage, error) {
+	return Usage{}, nil
+}
+
+// ledgerRecorder captures recorded ledger entries.
+type ledgerRecorder struct {
+	mu      sync.Mutex
+	entries []*LedgerEntry
+}
+
+func (l *ledgerRecorder) Record(ctx context.Context, entry *LedgerEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	return nil
+}
+func (l *ledgerRecorder) List(ctx context.Context, filter CostFilter) ([]*LedgerEntry, error) {
+	return l.entries, nil
+}
+func (l *ledgerRecorder) Report(ctx context.Context, filter CostFilter, dimensions []string) ([]*CostReportRow, error) {
+	return nil, nil
+}
+
+type usageProcessor struct{}
+
+func (p *usageProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- StreamEvent) (map[string]interface{}, error) {
+	stream <- StreamEvent{
+		Type:      "token_usage",
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"agent_id": "a1", "provider": "openai", "model": "gpt-4o", "latency_ms": int64(420),
+			"input_tokens": 120, "output_tokens": 30, "estimated_cost_usd": 0.01,
+		},
+	}
+	return map[string]interface{}{"agent_output": "done"}, nil
+}
+
+func TestEngine_Journal(t *testing.T) {
+	graph := &GraphDefinition{
+		ID:          "journal-graph",
+		StartNodeID: "start",
+		Nodes: map[string]*Node{
+			"start":  {ID: "start", Type: "test", NextIDs: []string{"agent"}},
+			"agent":  {ID: "agent", Type: "usage", NextIDs: []string{"review"}},
+			"review": {ID: "review", Type: "suspending_node"},
+		},
+	}
+	session := NewSession(graph, map[string]interface{}{"proposal": "p"})
+	engine := NewEngine(session)
+	repo := &journalRepo{}
+	engine.SetSessionRepository(repo)
+	ledger := &ledgerRecorder{}
+	engine.SetCostLedger(ledger)
+	engine.NodeFactory = SimpleFuncNodeFactory(func(n *Node) (NodeProcessor, error) {
+		switch n.Type {
+		case "usage":
+			return &usageProcessor{}, nil
+		case "suspending_node":
+			return &suspendingProcessor{}, nil
+		}
+		return &MockProcessor{Output: map[string]interface{}{"proposal": "p"}}, nil
+	})
+
+	ctx := context.Background()
+	session.Start(ctx)
+	if err := engine.Run(ctx); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if err := engine.ResumeNode(ctx, "review", map[string]interface{}{"review_action": "approve"}); err != nil {
+		t.Fatalf("ResumeNode failed: %v", err)
+	}
+	if err := engine.Wait(ctx); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if len(repo.execs) != 4 {
+		t.Fatalf("expected 4 journal records (start, agent, review suspended, review resumed), got %d", len(repo.execs))
+	}
+
+	agent := repo.execs[1]
+	if agent.NodeID != "agent" || agent.Status != StatusCompleted {
+		t.Errorf("unexpected agent record: %+v", agent)
+	}
+	if agent.PromptTokens != 120 || agent.CompletionTokens != 30 || agent.TotalTokens != 150 {
+		t.Errorf("expected token usage 120/30/150, got %d/%d/%d", agent.PromptTokens, agent.CompletionTokens, agent.TotalTokens)
+	}
+	if agent.CostUSD != 0.01 || session.Usage() != (Usage{PromptTokens: 120, CompletionTokens: 30, CostUSD: 0.01}) {
+		t.Errorf("expected the cost to be journaled and accounted to the session, got %v / %+v", agent.CostUSD, session.Usage())
+	}
+	if agent.Input["proposal"] != "p" || agent.Output["agent_output"] != "done" {
+		t.Errorf("expected input and output to be journaled, got %v -> %v", agent.Input, agent.Output)
+	}
+	if agent.Iteration != 1 {
+		t.Errorf("expected iteration 1, got %d", agent.Iteration)
+	}
+
+	if repo.execs[2].Status != StatusSuspended || repo.execs[3].Output["review_action"] != "approve" {
+		t.Errorf("expected suspension and review decision to be journaled, got %+v / %+v", repo.execs[2], repo.execs[3])
+	}
+
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+	if len(ledger.entries) != 1 {
+		t.Fatalf("expected the LLM call to be recorded in the ledger, got %d entries", len(ledger.entries))
+	}
+	want := LedgerEntry{
+		SessionID: session.ID, NodeID: "agent", AgentID: "a1", Provider: "openai", Model: "gpt-4o",
+		PromptTokens: 120, CompletionTokens: 30, CostUSD: 0.01, LatencyMs: 420,
+	}
+	got := *ledger.entries[0]
+	got.CreatedAt = time.Time{}
+	if got != want {
+		t.Errorf("unexpected ledger entry %+v", got)
+	}
+}
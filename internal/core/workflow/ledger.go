@@ -0,0 +1,75 @@
+package workflow
+
+import (
+	"context"
+	"time"
+)
+
+// LedgerEntry records a single LLM call and its priced cost.
+type LedgerEntry struct {
+	ID               int64     `json:"entry_id"`
+	SessionID        string    `json:"session_uuid"`
+	GroupID          string    `json:"group_uuid,omitempty"`    // Copied from the session when recorded
+	WorkflowID       string    `json:"workflow_uuid,omitempty"` // Copied from the session when recorded
+	NodeID           string    `json:"node_id"`
+	AgentID          string    `json:"agent_uuid,omitempty"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	LatencyMs        int64     `json:"latency_ms"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Report dimensions a ledger can be aggregated by.
+const (
+	CostByGroup    = "group"
+	CostByWorkflow = "workflow"
+	CostByAgent    = "agent"
+	CostByProvider = "provider"
+	CostByModel    = "model"
+	CostByDay      = "day"   // YYYY-MM-DD, UTC
+	CostByMonth    = "month" // YYYY-MM, UTC
+)
+
+// CostDimensions lists the valid report dimensions.
+var CostDimensions = []string{CostByGroup, CostByWorkflow, CostByAgent, CostByProvider, CostByModel, CostByDay, CostByMonth}
+
+// CostFilter selects ledger entries. Zero fields do not filter.
+type CostFilter struct {
+	From    time.Time // Inclusive
+	To      time.Time // Exclusive
+	GroupID string
+	Limit   int // Entries returned by List; zero returns all
+}
+
+// CostReportRow is the spend of one combination of report dimensions.
+type CostReportRow struct {
+	Keys             map[string]string `json:"keys"` // Dimension -> value, e.g. {"month": "2025-01", "group": "<uuid>"}
+	Calls            int               `json:"calls"`
+	PromptTokens     int               `json:"prompt_tokens"`
+	CompletionTokens int               `json:"completion_tokens"`
+	CostUSD          float64           `json:"cost_usd"`
+}
+
+// CostLedger stores LLM calls for spend reporting. Entries outlive their
+// session, so reports stay complete after sessions are deleted.
+type CostLedger interface {
+	// Record appends a call to the ledger.
+	Record(ctx context.Context, entry *LedgerEntry) error
+	// List returns the entries matching filter, newest first.
+	List(ctx context.Context, filter CostFilter) ([]*LedgerEntry, error)
+	// Report sums the entries matching filter by the given dimensions, ordered by them.
+	Report(ctx context.Context, filter CostFilter, dimensions []string) ([]*CostReportRow, error)
+}
+
+// IsCostDimension reports whether d is a valid report dimension.
+func IsCostDimension(d string) bool {
+	for _, valid := range CostDimensions {
+		if d == valid {
+			return true
+		}
+	}
+	return false
+}
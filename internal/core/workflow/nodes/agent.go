@@ -3,7 +3,9 @@ package nodes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
 	"github.com/hrygo/council/internal/infrastructure/llm"
+	"github.com/hrygo/council/internal/infrastructure/search"
 )
 
 type AgentProcessor struct {
@@ -20,16 +23,18 @@ type AgentProcessor struct {
 	AgentID         string // Agent UUID from database
 	AgentRepo       agent.Repository
 	Registry        *llm.Registry
-	Tools           []tools.Tool             // Injected tools
+	Tools           []tools.Tool             // Injected tools (node-level, override capability tools)
+	ToolRegistry    *tools.Registry          // Source of tools granted by agent capabilities
 	Session         *workflow.Session        // Injected Session
 	PassthroughKeys []string                 // Configuration: Keys to pass to output
 	PromptSections  []workflow.PromptSection // Configuration: Input keys to build prompt
 	OutputKey       string                   // Configuration: Key for response content (e.g. "agent_output")
+	OutputSchema    map[string]interface{}   // Configuration: JSON Schema the final response must match
 }
 
 func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- workflow.StreamEvent) (map[string]interface{}, error) {
-	// 1. Fetch Agent Persona
-	_ = stream // usage in tool/tokens below
+	// 1. Queue LLM calls under the session when rate limited
+	ctx = llmContext(ctx, input, a.NodeID, stream)
 
 	// 2. Fetch Agent Persona
 	ag, err := a.AgentRepo.GetByID(ctx, parseUUID(a.AgentID))
@@ -37,12 +42,19 @@ func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface
 		return nil, fmt.Errorf("failed to fetch agent %s: %w", a.AgentID, err)
 	}
 
-	// 3. Construct Context from Input
-	history := constructHistory(ag.PersonaPrompt, input, a.PromptSections)
+	// 3. Construct Context from Input, fitted into the model's context window
+	model := ag.ModelConfig.Model
+	if model == "" {
+		model = a.Registry.GetDefaultModel()
+	}
+	budget := promptBudget(model, a.Registry.ContextWindow(model), ag.ModelConfig.MaxTokens)
+	history, truncations := constructHistory(ag.PersonaPrompt, input, a.PromptSections, budget)
+	reportTruncations(stream, a.NodeID, budget, truncations)
 
 	// Prepare Tools
+	agentTools := a.resolveTools(ag)
 	var llmTools []llm.Tool
-	for _, t := range a.Tools {
+	for _, t := range agentTools {
 		llmTools = append(llmTools, llm.Tool{
 			Type: "function",
 			Function: llm.ToolFunction{
@@ -59,25 +71,37 @@ func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface
 	if err != nil {
 		return nil, fmt.Errorf("failed to get LLM provider '%s': %w", providerName, err)
 	}
+	var fallbacks []llm.FallbackTarget
+	for _, fb := range ag.ModelConfig.Fallbacks {
+		fallbacks = append(fallbacks, llm.FallbackTarget{Provider: fb.Provider, Model: fb.Model})
+	}
+	provider = a.Registry.WithFallbacks(provider, providerName, fallbacks)
 
 	// 5. Re-Act Loop
 	var finalResponse string
 	maxIterations := 5
 
+	// With an output schema the final response is validated, and sent back
+	// for repair when it does not match
+	var format *llm.ResponseFormat
+	if a.OutputSchema != nil {
+		format = &llm.ResponseFormat{Name: "agent_output", Schema: a.OutputSchema}
+	}
+	var structured interface{}
+	structuredErr := errors.New("no final response")
+	repairs := 0
+
 	for i := 0; i < maxIterations; i++ {
 		req := &llm.CompletionRequest{
-			Model:       ag.ModelConfig.Model,
-			Messages:    history,
-			Temperature: float32(ag.ModelConfig.Temperature),
-			MaxTokens:   ag.ModelConfig.MaxTokens,
-			TopP:        float32(ag.ModelConfig.TopP),
-			Stream:      true,
-			Tools:       llmTools,
-		}
-		if req.Model == "" {
-			req.Model = a.Registry.GetDefaultModel()
+			Model:          model,
+			Messages:       history,
+			Temperature:    float32(ag.ModelConfig.Temperature),
+			MaxTokens:      ag.ModelConfig.MaxTokens,
+			TopP:           float32(ag.ModelConfig.TopP),
+			Stream:         true,
+			Tools:          llmTools,
+			ResponseFormat: format,
 		}
-
 		// Notify "Thinking" (Force frontend to render message bubble)
 		stream <- workflow.StreamEvent{
 			Type:      "token_stream",
@@ -85,11 +109,28 @@ func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface
 			Data:      map[string]interface{}{"node_id": a.NodeID, "agent_id": a.AgentID, "chunk": " "},
 		}
 
+		started := time.Now()
 		resp, err := a.streamResponse(ctx, provider, req, stream)
 		if err != nil {
 			return nil, err
 		}
 
+		// Notify Token Usage of every round, tool rounds included
+		stream <- workflow.StreamEvent{
+			Type:      "token_usage",
+			Timestamp: time.Now(),
+			Data: map[string]interface{}{
+				"node_id":            a.NodeID,
+				"agent_id":           a.AgentID,
+				"provider":           a.Registry.ProviderName(providerName),
+				"model":              req.Model,
+				"input_tokens":       resp.Usage.PromptTokens,
+				"output_tokens":      resp.Usage.CompletionTokens,
+				"estimated_cost_usd": workflow.PriceUsage(req.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens),
+				"latency_ms":         time.Since(started).Milliseconds(),
+			},
+		}
+
 		// Append Assistant Message
 		msg := llm.Message{
 			Role:      "assistant",
@@ -109,7 +150,7 @@ func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface
 
 				// Find tool
 				var selectedTool tools.Tool
-				for _, t := range a.Tools {
+				for _, t := range agentTools {
 					if t.Name() == toolName {
 						selectedTool = t
 						break
@@ -126,11 +167,7 @@ func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface
 						result = fmt.Sprintf("Error: Invalid JSON arguments: %v", err)
 					} else {
 						// Execute
-						if sat, ok := selectedTool.(tools.SessionAwareTool); ok {
-							result, err = sat.ExecuteWithSession(ctx, a.Session, argsMap)
-						} else {
-							result, err = selectedTool.Execute(ctx, argsMap)
-						}
+						result, err = tools.Invoke(ctx, selectedTool, a.Session, argsMap)
 						if err != nil {
 							result = fmt.Sprintf("Error: %v", err)
 						}
@@ -161,24 +198,28 @@ func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface
 		} else {
 			// No tool calls, we are done
 			finalResponse = resp.Content
+			if format == nil {
+				// Notify Content Stream (Already done by streamResponse)
+				break
+			}
 
-			// Notify Content Stream (Already done by streamResponse)
-
-			// Notify Token Usage
+			structuredErr = llm.DecodeStructured(resp.Content, format, &structured)
+			if structuredErr == nil || repairs == llm.StructuredRepairs {
+				break
+			}
+			repairs++
+			history = append(history, llm.RepairMessage(structuredErr))
+			// The repaired response replaces the rejected one
 			stream <- workflow.StreamEvent{
-				Type:      "token_usage",
+				Type:      "token_stream",
 				Timestamp: time.Now(),
-				Data: map[string]interface{}{
-					"node_id":            a.NodeID,
-					"agent_id":           a.AgentID,
-					"input_tokens":       resp.Usage.PromptTokens,
-					"output_tokens":      resp.Usage.CompletionTokens,
-					"estimated_cost_usd": estimateCost(resp.Usage.TotalTokens),
-				},
+				Data:      map[string]interface{}{"node_id": a.NodeID, "agent_id": a.AgentID, "chunk": "", "restart": true},
 			}
-			break
 		}
 	}
+	if format != nil && structuredErr != nil {
+		return nil, fmt.Errorf("agent %s output does not match output_schema: %w", a.AgentID, structuredErr)
+	}
 
 	// 6. Output with context passthrough (Config Driven)
 	outputKey := a.OutputKey
@@ -195,6 +236,19 @@ func (a *AgentProcessor) Process(ctx context.Context, input map[string]interface
 		Keys: a.PassthroughKeys,
 	})
 
+	// Typed fields of a structured response, e.g. a score for loops or a
+	// vote for vote nodes; the response content and identity are kept
+	if format != nil {
+		output["structured"] = structured
+		if fields, ok := structured.(map[string]interface{}); ok {
+			for key, val := range fields {
+				if key != outputKey && key != "agent_id" && key != "timestamp" {
+					output[key] = val
+				}
+			}
+		}
+	}
+
 	finalOutputKeys := a.OutputKey
 	_ = finalOutputKeys // end logic complete
 
@@ -214,6 +268,16 @@ func (a *AgentProcessor) streamResponse(ctx context.Context, provider llm.LLMPro
 			if !ok {
 				chunkChan = nil
 			} else {
+				if chunk.Restart {
+					// The provider failed midway and a fallback starts over
+					fullContent = ""
+					toolCallsMap = make(map[int]*llm.ToolCall)
+					stream <- workflow.StreamEvent{
+						Type:      "token_stream",
+						Timestamp: time.Now(),
+						Data:      map[string]interface{}{"node_id": a.NodeID, "agent_id": a.AgentID, "chunk": "", "restart": true},
+					}
+				}
 				if chunk.Content != "" {
 					stream <- workflow.StreamEvent{
 						Type:      "token_stream",
@@ -279,33 +343,26 @@ func (a *AgentProcessor) streamResponse(ctx context.Context, provider llm.LLMPro
 	}, nil
 }
 
-func constructHistory(systemPrompt string, input map[string]interface{}, sections []workflow.PromptSection) []llm.Message {
-	var contextBuilder strings.Builder
-
-	// Build structured context with configured sections
-	for _, sec := range sections {
-		if val, ok := input[sec.Key].(string); ok && val != "" {
-			contextBuilder.WriteString(fmt.Sprintf("<%s>\n%s\n</%s>\n\n", sec.Label, val, sec.Label))
-		}
-	}
+// constructHistory builds the system and user messages from the input,
+// fitting the sections into the budget (nil keeps them whole).
+func constructHistory(systemPrompt string, input map[string]interface{}, sections []workflow.PromptSection, budget *workflow.PromptBudget) ([]llm.Message, []workflow.Truncation) {
+	texts, truncations, structured := fitSections(budget, systemPrompt, input, sections)
 
-	userContent := contextBuilder.String()
-	if userContent == "" {
-		// Fallback: dump all string values if no structured content found
-		keys := make([]string, 0, len(input))
-		for k := range input {
-			keys = append(keys, k)
+	var contextBuilder strings.Builder
+	for _, sec := range texts {
+		if sec.Text == "" {
+			continue // Dropped to fit the context window
 		}
-		sort.Strings(keys)
-
-		for _, k := range keys {
-			if str, ok := input[k].(string); ok {
-				contextBuilder.WriteString(fmt.Sprintf("%s: %s\n", k, str))
-			}
+		if structured {
+			// Build structured context with configured sections
+			contextBuilder.WriteString(fmt.Sprintf("<%s>\n%s\n</%s>\n\n", sec.Label, sec.Text, sec.Label))
+		} else {
+			// Fallback: dump all string values if no structured content found
+			contextBuilder.WriteString(fmt.Sprintf("%s: %s\n", sec.Key, sec.Text))
 		}
-		userContent = contextBuilder.String()
 	}
 
+	userContent := contextBuilder.String()
 	if userContent == "" {
 		userContent = "Begin task."
 	}
@@ -313,7 +370,7 @@ func constructHistory(systemPrompt string, input map[string]interface{}, section
 	return []llm.Message{
 		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: userContent},
-	}
+	}, truncations
 }
 
 func parseUUID(id string) uuid.UUID {
@@ -321,8 +378,61 @@ func parseUUID(id string) uuid.UUID {
 	return u
 }
 
-// estimateCost provides a rough cost estimate based on token count
-// Using approximate pricing of $0.002 per 1K tokens (typical for most models)
-func estimateCost(tokens int) float64 {
-	return float64(tokens) * 0.002 / 1000
+// resolveTools provisions the tools granted by the agent's capabilities and
+// merges the node-level tools on top (a node tool replaces a capability tool
+// of the same name).
+func (a *AgentProcessor) resolveTools(ag *agent.Agent) []tools.Tool {
+	byName := make(map[string]tools.Tool)
+
+	if ag.Capabilities.WebSearch {
+		if t := a.webSearchTool(ag.Capabilities.SearchProvider); t != nil {
+			byName[t.Name()] = t
+		} else {
+			log.Printf("[Agent] %s has web_search capability but no search provider is configured", ag.Name)
+		}
+	}
+	if ag.Capabilities.CodeExecution {
+		if t, ok := a.registryTool(tools.RunCodeToolName); ok {
+			byName[t.Name()] = t
+		} else {
+			log.Printf("[Agent] %s has code_execution capability but no sandbox is configured", ag.Name)
+		}
+	}
+
+	for _, t := range a.Tools {
+		byName[t.Name()] = t
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resolved := make([]tools.Tool, 0, len(names))
+	for _, name := range names {
+		resolved = append(resolved, byName[name])
+	}
+	return resolved
+}
+
+// webSearchTool returns a search tool for the agent's preferred provider, or
+// the one backed by the configured SearchClient.
+func (a *AgentProcessor) webSearchTool(provider string) tools.Tool {
+	if provider != "" {
+		if client, err := search.NewSearchClient(provider); err == nil {
+			return &tools.WebSearchTool{Client: client}
+		}
+	}
+	if t, ok := a.registryTool(tools.WebSearchToolName); ok {
+		return t
+	}
+	return nil
+}
+
+func (a *AgentProcessor) registryTool(name string) (tools.Tool, bool) {
+	if a.ToolRegistry == nil {
+		return nil, false
+	}
+	return a.ToolRegistry.GetTool(name)
 }
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -57,3 +58,112 @@ func TestAgentProcessor_Process(t *testing.T) {
 		t.Errorf("Expected 'Agent Says Hi', got '%v'", out)
 	}
 }
+
+func TestAgentProcessor_OutputSchema(t *testing.T) {
+	mockRepo := mocks.NewAgentMockRepository()
+	mockLLM := llm.NewMockProvider()
+	mockLLM.GenerateResponseQueue = []*llm.CompletionResponse{
+		{Content: `{"score": "high"}`},
+		{Content: "```json\n{\"score\": 92, \"summary\": \"Ready to ship\"}\n```"},
+	}
+
+	agentID := uuid.New()
+	if err := mockRepo.Create(context.Background(), &agent.Agent{
+		ID:          agentID,
+		Name:        "Reviewer",
+		ModelConfig: agent.ModelConfig{Model: "gpt-4", Provider: "default"},
+	}); err != nil {
+		t.Fatalf("Failed to create mock agent: %v", err)
+	}
+	registry := llm.NewRegistry(&config.Config{})
+	registry.RegisterProvider("default", mockLLM)
+
+	processor := &AgentProcessor{
+		AgentID:   agentID.String(),
+		AgentRepo: mockRepo,
+		Registry:  registry,
+		OutputSchema: map[string]interface{}{
+			"type":       "object",
+			"required":   []interface{}{"score", "summary"},
+			"properties": map[string]interface{}{"score": map[string]interface{}{"type": "number"}},
+		},
+	}
+
+	stream := make(chan workflow.StreamEvent, 100)
+	output, err := processor.Process(context.Background(), map[string]interface{}{"proposal": "p"}, stream)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if mockLLM.StreamCalls != 2 || output["score"] != float64(92) || output["summary"] != "Ready to ship" {
+		t.Errorf("Expected the repaired response's fields in the output, got %v after %d calls", output, mockLLM.StreamCalls)
+	}
+	if structured, _ := output["structured"].(map[string]interface{}); structured["score"] != float64(92) {
+		t.Errorf("Expected the structured response, got %v", output["structured"])
+	}
+
+	restarted := false
+	for len(stream) > 0 {
+		if ev := <-stream; ev.Type == "token_stream" && ev.Data["restart"] == true {
+			restarted = true
+		}
+	}
+	if !restarted {
+		t.Error("Expected the rejected response to be discarded by the client")
+	}
+
+	mockLLM.GenerateResponseQueue = []*llm.CompletionResponse{{Content: "Looks good"}, {Content: "Score: 92"}}
+	if _, err := processor.Process(context.Background(), map[string]interface{}{"proposal": "p"}, stream); err == nil {
+		t.Error("Expected a response that cannot be repaired to fail the node")
+	}
+}
+
+func TestAgentProcessor_FitsContextWindow(t *testing.T) {
+	mockRepo := mocks.NewAgentMockRepository()
+	agentID := uuid.New()
+	if err := mockRepo.Create(context.Background(), &agent.Agent{
+		ID:            agentID,
+		Name:          "Reviewer",
+		PersonaPrompt: "You are a reviewer.",
+		ModelConfig:   agent.ModelConfig{Model: "gpt-4", Provider: "default", MaxTokens: 1000},
+	}); err != nil {
+		t.Fatalf("Failed to create mock agent: %v", err)
+	}
+	registry := llm.NewRegistry(&config.Config{})
+	registry.RegisterProvider("default", llm.NewMockProvider())
+	registry.SetContextWindows(map[string]int{"gpt-4": 2000})
+
+	processor := &AgentProcessor{
+		NodeID:    "review",
+		AgentID:   agentID.String(),
+		AgentRepo: mockRepo,
+		Registry:  registry,
+		PromptSections: []workflow.PromptSection{
+			{Key: "proposal", Label: "proposal", Priority: 1},
+			{Key: "document_content", Label: "document"},
+		},
+	}
+
+	stream := make(chan workflow.StreamEvent, 100)
+	input := map[string]interface{}{
+		"proposal":         "Adopt Go 1.24.",
+		"document_content": strings.Repeat("The release notes go on and on. ", 2000),
+	}
+	if _, err := processor.Process(context.Background(), input, stream); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	var truncated *workflow.StreamEvent
+	for len(stream) > 0 {
+		if ev := <-stream; ev.Type == "context:truncated" {
+			truncated = &ev
+		}
+	}
+	if truncated == nil {
+		t.Fatal("Expected a context:truncated event")
+	}
+	sections, _ := truncated.Data["sections"].([]workflow.Truncation)
+	if truncated.Data["context_window"] != 2000 || len(sections) != 1 || sections[0].Key != "document_content" ||
+		sections[0].KeptTokens > 1000-8*2 || sections[0].KeptTokens < 800 {
+		t.Errorf("Expected the document cut to the 1000 tokens left beside the reply, got %v", truncated.Data)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
 	"github.com/hrygo/council/internal/infrastructure/llm"
 	"github.com/hrygo/council/internal/infrastructure/mocks"
+	"github.com/hrygo/council/internal/infrastructure/search"
 	"github.com/hrygo/council/internal/pkg/config"
 	"github.com/stretchr/testify/assert"
 )
@@ -85,3 +86,111 @@ func TestAgent_ToolCall_HappyPath(t *testing.T) {
 	assert.Equal(t, "package main", f.Content)
 	assert.Equal(t, 1, f.Version)
 }
+
+func TestAgent_ToolCall_WebSearchCapability(t *testing.T) {
+	mockRepo := mocks.NewAgentMockRepository()
+	mockLLM := llm.NewMockProvider()
+	mockLLM.GenerateResponseQueue = []*llm.CompletionResponse{
+		{
+			ToolCalls: []llm.ToolCall{
+				{
+					ID:   "call_1",
+					Type: "function",
+					Function: llm.FunctionCall{
+						Name:      "web_search",
+						Arguments: `{"query": "council quorum rules"}`,
+					},
+				},
+			},
+		},
+		{Content: "Quorum is a majority."},
+	}
+
+	agentID := uuid.New()
+	err := mockRepo.Create(context.Background(), &agent.Agent{
+		ID:            agentID,
+		Name:          "Researcher",
+		PersonaPrompt: "You are Researcher.",
+		ModelConfig:   agent.ModelConfig{Model: "gpt-4", Provider: "default"},
+		Capabilities:  agent.Capabilities{WebSearch: true},
+	})
+	assert.NoError(t, err)
+
+	registry := llm.NewRegistry(&config.Config{})
+	registry.RegisterProvider("default", mockLLM)
+
+	toolRegistry := tools.NewDefaultRegistry()
+	toolRegistry.Register(&tools.WebSearchTool{Client: &mocks.SearchMockClient{
+		Result: &search.SearchResult{Results: []search.SearchItem{
+			{Title: "Rules", URL: "https://example.org/rules", Content: "A majority forms a quorum."},
+		}},
+	}})
+
+	processor := &nodes.AgentProcessor{
+		NodeID:       "researcher",
+		AgentID:      agentID.String(),
+		AgentRepo:    mockRepo,
+		Registry:     registry,
+		ToolRegistry: toolRegistry,
+		Session:      workflow.NewSession(nil, nil),
+	}
+
+	stream := make(chan workflow.StreamEvent, 100)
+	output, err := processor.Process(context.Background(), map[string]interface{}{"task": "Research quorum"}, stream)
+	assert.NoError(t, err)
+	assert.Equal(t, "Quorum is a majority.", output["agent_output"])
+
+	close(stream)
+	var toolOutput string
+	for e := range stream {
+		if e.Type == "tool_execution" {
+			toolOutput, _ = e.Data["output"].(string)
+		}
+	}
+	assert.Contains(t, toolOutput, "https://example.org/rules")
+}
+
+func TestAgent_NoCapability_NoTools(t *testing.T) {
+	mockRepo := mocks.NewAgentMockRepository()
+	mockLLM := llm.NewMockProvider()
+	mockLLM.GenerateResponseQueue = []*llm.CompletionResponse{
+		{
+			ToolCalls: []llm.ToolCall{
+				{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "web_search", Arguments: `{"query": "x"}`}},
+			},
+		},
+		{Content: "Done."},
+	}
+
+	agentID := uuid.New()
+	assert.NoError(t, mockRepo.Create(context.Background(), &agent.Agent{
+		ID:          agentID,
+		Name:        "Plain",
+		ModelConfig: agent.ModelConfig{Model: "gpt-4", Provider: "default"},
+	}))
+
+	registry := llm.NewRegistry(&config.Config{})
+	registry.RegisterProvider("default", mockLLM)
+
+	toolRegistry := tools.NewDefaultRegistry()
+	toolRegistry.Register(&tools.WebSearchTool{Client: &mocks.SearchMockClient{}})
+
+	processor := &nodes.AgentProcessor{
+		NodeID:       "plain",
+		AgentID:      agentID.String(),
+		AgentRepo:    mockRepo,
+		Registry:     registry,
+		ToolRegistry: toolRegistry,
+	}
+
+	stream := make(chan workflow.StreamEvent, 100)
+	_, err := processor.Process(context.Background(), map[string]interface{}{"task": "x"}, stream)
+	assert.NoError(t, err)
+
+	close(stream)
+	for e := range stream {
+		if e.Type == "tool_execution" {
+			assert.Equal(t, "Error: Tool web_search not found", e.Data["output"])
+		}
+	}
+}
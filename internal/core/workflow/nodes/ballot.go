@@ -0,0 +1,76 @@
+package nodes
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Ballot choices.
+const (
+	VoteApprove = "approve"
+	VoteReject  = "reject"
+	VoteAbstain = "abstain"
+)
+
+// Ballot is a single voter's decision, emitted by an agent as a JSON block:
+//
+//	```json
+//	{"vote": "approve", "rationale": "...", "ranking": ["plan_b", "plan_a"]}
+//	```
+type Ballot struct {
+	Voter     string   `json:"voter"`
+	Vote      string   `json:"vote"` // approve | reject | abstain
+	Rationale string   `json:"rationale,omitempty"`
+	Ranking   []string `json:"ranking,omitempty"` // Preference order for ranked_choice
+	Weight    float64  `json:"weight"`
+}
+
+var ballotBlockRe = regexp.MustCompile("(?s)```json\\s*(\\{.*?\\})\\s*```")
+
+// ParseBallot extracts a ballot from an agent's output. The JSON block is
+// expected to be wrapped in ```json ... ``` code fences; a bare vote keyword
+// such as "YES" or "Rejected" is also accepted.
+func ParseBallot(content string) (*Ballot, error) {
+	for _, match := range ballotBlockRe.FindAllStringSubmatch(content, -1) {
+		var raw struct {
+			Vote      string   `json:"vote"`
+			Rationale string   `json:"rationale"`
+			Ranking   []string `json:"ranking"`
+		}
+		if err := json.Unmarshal([]byte(match[1]), &raw); err != nil {
+			continue
+		}
+		if raw.Vote == "" && len(raw.Ranking) == 0 {
+			continue // Another JSON block, e.g. a structured score
+		}
+		vote := normalizeVote(raw.Vote)
+		if vote == "" {
+			if len(raw.Ranking) == 0 {
+				return nil, fmt.Errorf("unknown vote %q in ballot", raw.Vote)
+			}
+			vote = VoteApprove // A ranking alone is a participating ballot
+		}
+		return &Ballot{Vote: vote, Rationale: raw.Rationale, Ranking: raw.Ranking}, nil
+	}
+
+	if vote := normalizeVote(content); vote != "" {
+		return &Ballot{Vote: vote}, nil
+	}
+	return nil, fmt.Errorf("no ballot found in agent output")
+}
+
+// normalizeVote maps vote keywords to a ballot choice, or "" if unknown.
+func normalizeVote(s string) string {
+	switch strings.ToUpper(strings.Trim(strings.TrimSpace(s), ".!")) {
+	case "APPROVE", "APPROVED", "YES", "Y", "FOR", "ACCEPT":
+		return VoteApprove
+	case "REJECT", "REJECTED", "NO", "N", "AGAINST", "DENY", "FAIL":
+		return VoteReject
+	case "ABSTAIN", "ABSTAINED", "ABSTENTION":
+		return VoteAbstain
+	default:
+		return ""
+	}
+}
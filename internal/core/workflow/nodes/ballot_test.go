@@ -0,0 +1,30 @@
+package nodes
+
+import "testing"
+
+func TestParseBallot_JSONBlock(t *testing.T) {
+	content := "Scores first:\n```json\n{\"score\": {\"weighted_total\": 80}}\n```\nThen my vote:\n```json\n{\"vote\": \"Reject\", \"rationale\": \"Too costly\"}\n```"
+
+	ballot, err := ParseBallot(content)
+	if err != nil {
+		t.Fatalf("ParseBallot failed: %v", err)
+	}
+	if ballot.Vote != VoteReject || ballot.Rationale != "Too costly" {
+		t.Errorf("unexpected ballot: %+v", ballot)
+	}
+}
+
+func TestParseBallot_Keyword(t *testing.T) {
+	for input, want := range map[string]string{"yes": VoteApprove, " APPROVED. ": VoteApprove, "No": VoteReject, "abstain": VoteAbstain} {
+		ballot, err := ParseBallot(input)
+		if err != nil || ballot.Vote != want {
+			t.Errorf("ParseBallot(%q) = %+v, %v; want %s", input, ballot, err, want)
+		}
+	}
+}
+
+func TestParseBallot_NoBallot(t *testing.T) {
+	if _, err := ParseBallot("A long analysis without any decision."); err == nil {
+		t.Error("expected error for output without a ballot")
+	}
+}
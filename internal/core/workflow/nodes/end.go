@@ -13,41 +13,60 @@ import (
 type EndProcessor struct {
 	NodeID         string // Dynamic Node ID
 	LLM            llm.LLMProvider
+	Provider       string // Name of the LLM provider, for the cost ledger
 	Model          string
 	Prompt         string
 	PromptSections []workflow.PromptSection // Configuration
+	ContextWindow  int                      // Tokens the model accepts; the sections are fitted into it when > 0
 	OutputKey      string                   // Configuration: Key for summary (e.g. "final_report")
 }
 
 func (e *EndProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- workflow.StreamEvent) (map[string]interface{}, error) {
 	// 1. Logic start
-	_ = stream // for now use stream for tokens below
+	ctx = llmContext(ctx, input, e.NodeID, stream)
 
-	// 2. Aggregate Content - Build structured context for report generation
-	var contentBuilder strings.Builder
+	// 2. Aggregate Content - Build structured context for report generation,
+	// fitted into the model's context window
+	prompt := e.Prompt
+	if prompt == "" {
+		prompt = "Please summarize the above content."
+	}
 
+	var texts []workflow.SectionText
 	for _, section := range e.PromptSections {
 		if val, ok := input[section.Key]; ok {
 			strVal := fmt.Sprintf("%v", val)
 			if strVal != "" {
-				contentBuilder.WriteString("## " + section.Label + "\n")
-				contentBuilder.WriteString(strVal)
-				contentBuilder.WriteString("\n\n")
+				texts = append(texts, workflow.SectionText{PromptSection: section, Text: strVal})
 			}
 		}
 	}
+	raw := len(texts) == 0
+	if raw {
+		texts = []workflow.SectionText{{PromptSection: workflow.PromptSection{Key: "input", Label: "input"}, Text: fmt.Sprintf("%v", input)}}
+	}
+	budget := promptBudget(e.Model, e.ContextWindow, 0)
+	if budget != nil {
+		var truncations []workflow.Truncation
+		texts, truncations = budget.Fit(budget.Count(prompt), texts)
+		reportTruncations(stream, e.NodeID, budget, truncations)
+	}
 
-	fullContent := contentBuilder.String()
-	if fullContent == "" {
-		fullContent = fmt.Sprintf("Raw Input: %v", input)
+	var contentBuilder strings.Builder
+	for _, section := range texts {
+		if section.Text != "" {
+			contentBuilder.WriteString("## " + section.Label + "\n")
+			contentBuilder.WriteString(section.Text)
+			contentBuilder.WriteString("\n\n")
+		}
 	}
 
-	// 3. Call LLM
-	prompt := e.Prompt
-	if prompt == "" {
-		prompt = "Please summarize the above content."
+	fullContent := contentBuilder.String()
+	if raw {
+		fullContent = "Raw Input: " + texts[0].Text
 	}
 
+	// 3. Call LLM
 	req := &llm.CompletionRequest{
 		Model: e.Model,
 		Messages: []llm.Message{
@@ -58,9 +77,11 @@ func (e *EndProcessor) Process(ctx context.Context, input map[string]interface{}
 		Stream:      true, // We want streaming
 	}
 
+	started := time.Now()
 	tokenStream, errChan := e.LLM.Stream(ctx, req)
 
 	var finalSummary strings.Builder
+	var usage llm.Usage
 
 	// 4. Stream Tokens
 	// We need to read from both channels.
@@ -72,6 +93,15 @@ Loop:
 			if !ok {
 				tokenStream = nil // Channel closed
 			} else {
+				if chunk.Restart {
+					// The provider failed midway and a fallback starts over
+					finalSummary.Reset()
+					stream <- workflow.StreamEvent{
+						Type:      "token_stream",
+						Timestamp: time.Now(),
+						Data:      map[string]interface{}{"node_id": e.NodeID, "chunk": "", "restart": true},
+					}
+				}
 				if chunk.Content != "" {
 					finalSummary.WriteString(chunk.Content)
 					stream <- workflow.StreamEvent{
@@ -80,6 +110,9 @@ Loop:
 						Data:      map[string]interface{}{"node_id": e.NodeID, "chunk": chunk.Content},
 					}
 				}
+				if chunk.Usage != nil {
+					usage = *chunk.Usage
+				}
 			}
 		case err, ok := <-errChan:
 			if ok && err != nil {
@@ -96,6 +129,20 @@ Loop:
 		}
 	}
 
+	stream <- workflow.StreamEvent{
+		Type:      "token_usage",
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"node_id":            e.NodeID,
+			"provider":           e.Provider,
+			"model":              e.Model,
+			"input_tokens":       usage.PromptTokens,
+			"output_tokens":      usage.CompletionTokens,
+			"estimated_cost_usd": workflow.PriceUsage(e.Model, usage.PromptTokens, usage.CompletionTokens),
+			"latency_ms":         time.Since(started).Milliseconds(),
+		},
+	}
+
 	// 5. Output
 	outputKey := e.OutputKey
 	if outputKey == "" {
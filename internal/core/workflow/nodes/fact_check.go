@@ -3,7 +3,9 @@ package nodes
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hrygo/council/internal/core/workflow"
@@ -11,103 +13,392 @@ import (
 	"github.com/hrygo/council/internal/infrastructure/search"
 )
 
+// Claim verdicts.
+const (
+	VerdictSupported    = "supported"
+	VerdictRefuted      = "refuted"
+	VerdictUnverifiable = "unverifiable"
+)
+
+// DefaultFactCheckKeys are the input keys checked when InputKeys is not configured.
+var DefaultFactCheckKeys = []string{"agent_output", "response", "aggregated_outputs"}
+
+// Reply formats of the claim extraction and verification prompts.
+var (
+	claimsFormat = &llm.ResponseFormat{Name: "claims", Schema: map[string]interface{}{
+		"type":     "object",
+		"required": []string{"claims"},
+		"properties": map[string]interface{}{
+			"claims": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}}
+	verdictFormat = &llm.ResponseFormat{Name: "verdict", Schema: map[string]interface{}{
+		"type":     "object",
+		"required": []string{"verdict", "confidence", "explanation", "citations"},
+		"properties": map[string]interface{}{
+			"verdict":     map[string]interface{}{"type": "string", "enum": []string{VerdictSupported, VerdictRefuted, VerdictUnverifiable}},
+			"confidence":  map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+			"explanation": map[string]interface{}{"type": "string"},
+			"citations":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}, Normalize: normalizeVerdict}
+)
+
+// normalizeVerdict accepts the verdict in any case and with stray spaces.
+func normalizeVerdict(value interface{}) interface{} {
+	if obj, ok := value.(map[string]interface{}); ok {
+		if v, ok := obj["verdict"].(string); ok {
+			obj["verdict"] = strings.ToLower(strings.TrimSpace(v))
+		}
+	}
+	return value
+}
+
+// ClaimVerdict is the verification result of a single factual claim.
+type ClaimVerdict struct {
+	Claim       string   `json:"claim"`
+	Verdict     string   `json:"verdict"`    // supported | refuted | unverifiable
+	Confidence  float64  `json:"confidence"` // Confidence in the verdict, 0.0-1.0
+	Explanation string   `json:"explanation"`
+	Citations   []string `json:"citations"` // URLs of the search results backing the verdict
+	Error       string   `json:"error,omitempty"`
+}
+
+// FactCheckProcessor extracts factual claims from upstream output, searches
+// evidence for each claim and asks the LLM for a per-claim verdict.
+// It routes to NextIDs[0] when verified and NextIDs[1] otherwise.
 type FactCheckProcessor struct {
+	NodeID          string
 	LLM             llm.LLMProvider
+	Provider        string // Name of the LLM provider, for the cost ledger
+	Model           string
 	SearchClient    search.SearchClient
-	VerifyThreshold float64
+	VerifyThreshold float64  // Minimum aggregate confidence (default 0.7)
+	MaxClaims       int      // Claims extracted per run (default 5)
+	Concurrency     int      // Parallel claim checks (default 3)
+	InputKeys       []string // Keys holding the text to check (default DefaultFactCheckKeys)
+	PassthroughKeys []string // Configuration: Keys to pass to output
+}
+
+// GetNextNodes implements workflow.ConditionalRouter.
+func (f *FactCheckProcessor) GetNextNodes(ctx context.Context, output map[string]interface{}, defaultNextIDs []string) ([]string, error) {
+	if len(defaultNextIDs) < 2 {
+		return defaultNextIDs, nil
+	}
+	if verified, _ := output["verified"].(bool); verified {
+		return []string{defaultNextIDs[0]}, nil
+	}
+	return []string{defaultNextIDs[1]}, nil
 }
 
 func (f *FactCheckProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- workflow.StreamEvent) (map[string]interface{}, error) {
 	stream <- workflow.StreamEvent{
 		Type:      "node_state_change",
 		Timestamp: time.Now(),
+		NodeID:    f.NodeID,
 		Data:      map[string]interface{}{"status": "running"},
 	}
 
-	// 1. Gather text to check from input
-	var textToCheck string
-	for _, v := range input {
-		if s, ok := v.(string); ok {
-			textToCheck += s + "\n"
-		}
-	}
-
-	// 2. Perform Web Search
-	var searchResults string
-	if f.SearchClient != nil {
-		result, err := f.SearchClient.Search(ctx, textToCheck, search.SearchOptions{
-			MaxResults: 3,
-			SearchType: "answer",
-		})
-		if err != nil {
-			stream <- workflow.StreamEvent{
-				Type:      "error",
-				Timestamp: time.Now(),
-				Data:      map[string]interface{}{"error": "search failed: " + err.Error()},
-			}
-			// Proceed with empty results instead of failing
-			searchResults = "[Search unavailable]"
-		} else {
-			// Build search summary
-			var sb strings.Builder
-			if result.Answer != "" {
-				sb.WriteString("Direct Answer: " + result.Answer + "\n")
-			}
-			for _, item := range result.Results {
-				sb.WriteString(fmt.Sprintf("- %s: %s\n", item.Title, item.Content))
-			}
-			searchResults = sb.String()
-		}
-	} else {
-		searchResults = "[No search client configured]"
+	if f.LLM == nil {
+		return nil, fmt.Errorf("fact check node %s has no llm provider", f.NodeID)
 	}
+	ctx = llmContext(ctx, input, f.NodeID, stream)
 
-	// 3. Verify with LLM
-	prompt := fmt.Sprintf(`Analyze the following text against web search results.
-Text to verify: 
-%s
+	usage := &usageCounter{}
 
-Web Search Results:
-%s
+	// 1. Extract claims
+	claims, err := f.extractClaims(ctx, f.textToCheck(input), usage)
+	if err != nil {
+		return nil, err
+	}
 
-Determine if the claims in the text are accurate.
-Output JSON: {"verified": true/false, "confidence": 0.0-1.0, "issues": ["list of issues if any"]}`, textToCheck, searchResults)
+	// 2. Verify each claim against its own evidence
+	groupID, _ := input["group_uuid"].(string)
+	sessionID, _ := input["session_id"].(string)
+	scope := search.SearchOptions{MaxResults: 3, SearchType: "search", GroupID: groupID, SessionID: sessionID}
+	verdicts := f.verifyClaims(ctx, claims, scope, usage)
 
-	verified := true
-	confidence := 0.9
+	// 3. Aggregate
+	threshold := f.VerifyThreshold
+	if threshold <= 0 {
+		threshold = 0.7
+	}
+	confidence := aggregateConfidence(verdicts)
+	refuted := false
 	issues := []string{}
+	claimsOut := make([]map[string]interface{}, 0, len(verdicts))
+	for _, v := range verdicts {
+		if v.Verdict == VerdictRefuted {
+			refuted = true
+		}
+		if v.Verdict != VerdictSupported {
+			issues = append(issues, fmt.Sprintf("%s (%s): %s", v.Claim, v.Verdict, v.Explanation))
+		}
+		claimsOut = append(claimsOut, v.toMap())
+	}
+	verified := !refuted && confidence >= threshold
 
-	if f.LLM != nil {
-		resp, err := f.LLM.Generate(ctx, &llm.CompletionRequest{
-			Messages: []llm.Message{
-				{Role: "user", Content: prompt},
+	for _, call := range usage.calls {
+		stream <- workflow.StreamEvent{
+			Type:      "token_usage",
+			Timestamp: time.Now(),
+			Data: map[string]interface{}{
+				"node_id":            f.NodeID,
+				"provider":           f.Provider,
+				"model":              f.Model,
+				"input_tokens":       call.usage.PromptTokens,
+				"output_tokens":      call.usage.CompletionTokens,
+				"estimated_cost_usd": workflow.PriceUsage(f.Model, call.usage.PromptTokens, call.usage.CompletionTokens),
+				"latency_ms":         call.latency.Milliseconds(),
 			},
-			Temperature: 0.1,
-		})
-		if err == nil && resp.Content != "" {
-			// Parse LLM response (simplified - assume success means verified)
-			if strings.Contains(strings.ToLower(resp.Content), `"verified": false`) ||
-				strings.Contains(strings.ToLower(resp.Content), `"verified":false`) {
-				verified = false
-				confidence = 0.7
-				issues = append(issues, "LLM flagged potential inaccuracies")
-			}
 		}
 	}
 
 	output := map[string]interface{}{
-		"verified":       verified,
-		"confidence":     confidence,
-		"issues":         issues,
-		"search_summary": searchResults,
-		"timestamp":      time.Now(),
+		"verified":   verified,
+		"confidence": confidence,
+		"threshold":  threshold,
+		"claims":     claimsOut,
+		"issues":     issues,
+		"timestamp":  time.Now(),
 	}
 
+	workflow.ApplyPassthrough(input, output, workflow.PassthroughConfig{
+		Keys: f.PassthroughKeys,
+	})
+
 	stream <- workflow.StreamEvent{
 		Type:      "node_state_change",
 		Timestamp: time.Now(),
+		NodeID:    f.NodeID,
 		Data:      map[string]interface{}{"status": "completed", "verified": verified},
 	}
 
 	return output, nil
 }
+
+// textToCheck gathers the configured input keys. Without any of them, all
+// string values except identifiers are used.
+func (f *FactCheckProcessor) textToCheck(input map[string]interface{}) string {
+	keys := f.InputKeys
+	if len(keys) == 0 {
+		keys = DefaultFactCheckKeys
+	}
+
+	var parts []string
+	for _, key := range keys {
+		if s, ok := input[key].(string); ok && strings.TrimSpace(s) != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, "\n\n")
+	}
+
+	var fallback []string
+	for key, v := range input {
+		if s, ok := v.(string); ok && key != "session_id" && key != "agent_id" {
+			fallback = append(fallback, s)
+		}
+	}
+	sort.Strings(fallback)
+	return strings.Join(fallback, "\n\n")
+}
+
+func (f *FactCheckProcessor) extractClaims(ctx context.Context, text string, usage *usageCounter) ([]string, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	maxClaims := f.MaxClaims
+	if maxClaims <= 0 {
+		maxClaims = 5
+	}
+
+	prompt := fmt.Sprintf(`Extract the checkable factual claims from the text below.
+Only include objective statements (numbers, dates, names, events, scientific facts), not opinions or recommendations.
+Return at most %d claims, most important first, each as a self-contained sentence.
+
+Text:
+%s`, maxClaims, text)
+
+	var parsed struct {
+		Claims []string `json:"claims"`
+	}
+	started := time.Now()
+	resp, err := llm.GenerateStructured(ctx, f.LLM, &llm.CompletionRequest{
+		Model:          f.Model,
+		Messages:       []llm.Message{{Role: "user", Content: prompt}},
+		Temperature:    0.1,
+		ResponseFormat: claimsFormat,
+	}, &parsed)
+	if resp != nil {
+		usage.add(resp.Usage, started)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim extraction failed: %w", err)
+	}
+
+	var claims []string
+	for _, c := range parsed.Claims {
+		if c = strings.TrimSpace(c); c != "" {
+			claims = append(claims, c)
+		}
+	}
+	if len(claims) > maxClaims {
+		claims = claims[:maxClaims]
+	}
+	return claims, nil
+}
+
+// verifyClaims checks claims with bounded concurrency, preserving claim order.
+func (f *FactCheckProcessor) verifyClaims(ctx context.Context, claims []string, opts search.SearchOptions, usage *usageCounter) []*ClaimVerdict {
+	concurrency := f.Concurrency
+	if concurrency <= 0 {
+		concurrency = 3
+	}
+
+	verdicts := make([]*ClaimVerdict, len(claims))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, claim := range claims {
+		wg.Add(1)
+		go func(i int, claim string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			verdicts[i] = f.verifyClaim(ctx, claim, opts, usage)
+		}(i, claim)
+	}
+	wg.Wait()
+	return verdicts
+}
+
+func (f *FactCheckProcessor) verifyClaim(ctx context.Context, claim string, opts search.SearchOptions, usage *usageCounter) *ClaimVerdict {
+	verdict := &ClaimVerdict{Claim: claim, Verdict: VerdictUnverifiable, Citations: []string{}}
+
+	if f.SearchClient == nil {
+		verdict.Error = "no search client configured"
+		return verdict
+	}
+	result, err := f.SearchClient.Search(ctx, claim, opts)
+	if err != nil {
+		verdict.Error = "search failed: " + err.Error()
+		return verdict
+	}
+	if result == nil || len(result.Results) == 0 {
+		verdict.Explanation = "No evidence found"
+		return verdict
+	}
+
+	var evidence strings.Builder
+	sources := make(map[string]bool, len(result.Results))
+	for i, item := range result.Results {
+		evidence.WriteString(fmt.Sprintf("[%d] %s\nURL: %s\n%s\n\n", i+1, item.Title, item.URL, item.Content))
+		sources[item.URL] = true
+	}
+
+	prompt := fmt.Sprintf(`Judge the claim using ONLY the evidence below.
+
+Claim: %s
+
+Evidence:
+%s
+Verdict must be "supported", "refuted" or "unverifiable" (evidence is insufficient).
+Cite the URLs of the evidence you relied on.`, claim, evidence.String())
+
+	var parsed struct {
+		Verdict     string   `json:"verdict"`
+		Confidence  float64  `json:"confidence"`
+		Explanation string   `json:"explanation"`
+		Citations   []string `json:"citations"`
+	}
+	started := time.Now()
+	resp, err := llm.GenerateStructured(ctx, f.LLM, &llm.CompletionRequest{
+		Model:          f.Model,
+		Messages:       []llm.Message{{Role: "user", Content: prompt}},
+		Temperature:    0.1,
+		ResponseFormat: verdictFormat,
+	}, &parsed)
+	if resp != nil {
+		usage.add(resp.Usage, started)
+	}
+	if err != nil {
+		verdict.Error = "verification failed: " + err.Error()
+		return verdict
+	}
+
+	verdict.Verdict = parsed.Verdict
+	verdict.Confidence = clamp01(parsed.Confidence)
+	verdict.Explanation = parsed.Explanation
+
+	// Only keep citations that point at the evidence actually provided
+	for _, url := range parsed.Citations {
+		if sources[url] {
+			verdict.Citations = append(verdict.Citations, url)
+		}
+	}
+	return verdict
+}
+
+// aggregateConfidence averages the likelihood that each claim is true:
+// a supported claim counts its confidence, a refuted one the complement,
+// and an unverifiable one 0.5. Without claims there is nothing to dispute.
+func aggregateConfidence(verdicts []*ClaimVerdict) float64 {
+	if len(verdicts) == 0 {
+		return 1
+	}
+	total := 0.0
+	for _, v := range verdicts {
+		switch v.Verdict {
+		case VerdictSupported:
+			total += v.Confidence
+		case VerdictRefuted:
+			total += 1 - v.Confidence
+		default:
+			total += 0.5
+		}
+	}
+	return total / float64(len(verdicts))
+}
+
+func (v *ClaimVerdict) toMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"claim":       v.Claim,
+		"verdict":     v.Verdict,
+		"confidence":  v.Confidence,
+		"explanation": v.Explanation,
+		"citations":   v.Citations,
+	}
+	if v.Error != "" {
+		m["error"] = v.Error
+	}
+	return m
+}
+
+func clamp01(f float64) float64 {
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+// llmCall is the usage and latency of a single LLM call.
+type llmCall struct {
+	usage   llm.Usage
+	latency time.Duration
+}
+
+// usageCounter collects the LLM calls of concurrent claim checks.
+type usageCounter struct {
+	mu    sync.Mutex
+	calls []llmCall
+}
+
+func (u *usageCounter) add(usage llm.Usage, started time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.calls = append(u.calls, llmCall{usage: usage, latency: time.Since(started)})
+}
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/hrygo/council/internal/core/workflow"
@@ -10,31 +11,59 @@ import (
 	"github.com/hrygo/council/internal/infrastructure/search"
 )
 
-func TestFactCheckProcessor_Process(t *testing.T) {
-	// Setup Mocks
-	mockLLM := llm.NewMockProvider()
-	mockSearch := &mocks.SearchMockClient{
+// factCheckLLM answers the claim-extraction prompt with claims and each
+// verification prompt with the verdict registered for the claim it contains.
+type factCheckLLM struct {
+	claims   string
+	verdicts map[string]string
+}
+
+func (m *factCheckLLM) Generate(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	prompt := req.Messages[len(req.Messages)-1].Content
+	if strings.HasPrefix(prompt, "Extract the checkable factual claims") {
+		return &llm.CompletionResponse{Content: m.claims, Usage: llm.Usage{PromptTokens: 10, CompletionTokens: 5}}, nil
+	}
+	for claim, verdict := range m.verdicts {
+		if strings.Contains(prompt, "Claim: "+claim) {
+			return &llm.CompletionResponse{Content: verdict, Usage: llm.Usage{PromptTokens: 20, CompletionTokens: 10}}, nil
+		}
+	}
+	return &llm.CompletionResponse{Content: "{}"}, nil
+}
+
+func (m *factCheckLLM) Stream(ctx context.Context, req *llm.CompletionRequest) (<-chan llm.CompletionChunk, <-chan error) {
+	return nil, nil
+}
+
+func newFactCheckSearch() *mocks.SearchMockClient {
+	return &mocks.SearchMockClient{
 		Result: &search.SearchResult{
-			Answer: "Mocked fact answer",
 			Results: []search.SearchItem{
-				{Title: "Source 1", Content: "Fact content"},
+				{Title: "Source 1", URL: "https://example.org/1", Content: "Fact content"},
 			},
 		},
 	}
+}
+
+func TestFactCheckProcessor_Process(t *testing.T) {
+	mockLLM := &factCheckLLM{
+		claims: "```json\n{\"claims\": [\"The sky is blue.\", \"Water boils at 100C at sea level.\"]}\n```",
+		verdicts: map[string]string{
+			"The sky is blue.":                  `{"verdict": "supported", "confidence": 0.9, "explanation": "Rayleigh scattering", "citations": ["https://example.org/1", "https://invented.example"]}`,
+			"Water boils at 100C at sea level.": `{"verdict": "supported", "confidence": 0.95, "explanation": "Standard pressure", "citations": ["https://example.org/1"]}`,
+		},
+	}
 
 	processor := &FactCheckProcessor{
+		NodeID:          "fc",
 		LLM:             mockLLM,
-		SearchClient:    mockSearch,
+		SearchClient:    newFactCheckSearch(),
 		VerifyThreshold: 0.8,
-	}
-
-	// Case 1: All good (Verified = true)
-	mockLLM.GenerateResponse = &llm.CompletionResponse{
-		Content: `{"verified": true, "confidence": 0.95}`,
+		PassthroughKeys: []string{"proposal"},
 	}
 
 	stream := make(chan workflow.StreamEvent, 10)
-	input := map[string]interface{}{"text": "The sky is blue."}
+	input := map[string]interface{}{"agent_output": "The sky is blue. Water boils at 100C.", "proposal": "p"}
 
 	output, err := processor.Process(context.Background(), input, stream)
 	if err != nil {
@@ -42,20 +71,82 @@ func TestFactCheckProcessor_Process(t *testing.T) {
 	}
 
 	if output["verified"] != true {
-		t.Errorf("Expected verified=true, got %v", output["verified"])
+		t.Errorf("Expected verified=true, got %v", output)
+	}
+	if c := output["confidence"].(float64); c < 0.92 || c > 0.93 {
+		t.Errorf("Expected aggregate confidence 0.925, got %v", c)
+	}
+	if output["proposal"] != "p" {
+		t.Errorf("Expected proposal to pass through, got %v", output["proposal"])
+	}
+
+	claims := output["claims"].([]map[string]interface{})
+	if len(claims) != 2 || claims[0]["claim"] != "The sky is blue." {
+		t.Fatalf("Expected claims in extraction order, got %v", claims)
+	}
+	citations := claims[0]["citations"].([]string)
+	if len(citations) != 1 || citations[0] != "https://example.org/1" {
+		t.Errorf("Expected only evidence URLs to be cited, got %v", citations)
 	}
 
-	// Case 2: Flagged (Verified = false)
-	mockLLM.GenerateResponse = &llm.CompletionResponse{
-		Content: `{"verified": false, "confidence": 0.5}`,
+	close(stream)
+	var inputTokens int
+	for e := range stream {
+		if e.Type == "token_usage" {
+			inputTokens += e.Data["input_tokens"].(int)
+		}
 	}
+	if inputTokens != 50 {
+		t.Errorf("Expected usage of all LLM calls (50 prompt tokens), got %d", inputTokens)
+	}
+}
+
+func TestFactCheckProcessor_RefutedClaim(t *testing.T) {
+	mockLLM := &factCheckLLM{
+		claims: `{"claims": ["The moon is made of cheese."]}`,
+		verdicts: map[string]string{
+			"The moon is made of cheese.": `{"verdict": " Refuted", "confidence": 0.99, "explanation": "Rock", "citations": ["https://example.org/1"]}`,
+		},
+	}
+	processor := &FactCheckProcessor{LLM: mockLLM, SearchClient: newFactCheckSearch()}
 
-	output, err = processor.Process(context.Background(), input, stream)
+	output, err := processor.Process(context.Background(), map[string]interface{}{"agent_output": "x"}, make(chan workflow.StreamEvent, 10))
 	if err != nil {
 		t.Fatalf("Process failed: %v", err)
 	}
-
 	if output["verified"] != false {
 		t.Errorf("Expected verified=false, got %v", output["verified"])
 	}
+	if len(output["issues"].([]string)) != 1 {
+		t.Errorf("Expected one issue, got %v", output["issues"])
+	}
+	// The verdict's case and spacing are normalised rather than repaired
+	if claim := output["claims"].([]map[string]interface{})[0]; claim["verdict"] != VerdictRefuted || claim["error"] != nil {
+		t.Errorf("Expected the refuted verdict, got %v", claim)
+	}
+
+	next, _ := processor.GetNextNodes(context.Background(), output, []string{"pass", "revise"})
+	if len(next) != 1 || next[0] != "revise" {
+		t.Errorf("Expected routing to the unverified path, got %v", next)
+	}
+}
+
+func TestFactCheckProcessor_SearchFailure(t *testing.T) {
+	mockLLM := &factCheckLLM{claims: `{"claims": ["GDP grew 3% in 2023."]}`}
+	processor := &FactCheckProcessor{
+		LLM:          mockLLM,
+		SearchClient: &mocks.SearchMockClient{Err: context.DeadlineExceeded},
+	}
+
+	output, err := processor.Process(context.Background(), map[string]interface{}{"agent_output": "x"}, make(chan workflow.StreamEvent, 10))
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	claims := output["claims"].([]map[string]interface{})
+	if claims[0]["verdict"] != VerdictUnverifiable || claims[0]["error"] == nil {
+		t.Errorf("Expected unverifiable claim with error, got %v", claims[0])
+	}
+	if output["verified"] != false {
+		t.Errorf("Expected unverifiable evidence not to pass the default threshold")
+	}
 }
@@ -1,6 +1,7 @@
 package nodes
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/hrygo/council/internal/core/agent"
@@ -8,6 +9,7 @@ import (
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
 	"github.com/hrygo/council/internal/infrastructure/llm"
+	"github.com/hrygo/council/internal/infrastructure/search"
 )
 
 // Dependencies for creating nodes
@@ -16,6 +18,8 @@ type GenericNodeFactory struct {
 	Registry      *llm.Registry
 	AgentRepo     agent.Repository
 	MemoryManager memory.MemoryManager
+	Tools         *tools.Registry
+	SearchClient  search.SearchClient // Evidence source for fact checks; optional
 }
 
 // NewGenericNodeFactory creates a new factory with dependencies.
@@ -24,6 +28,7 @@ func NewGenericNodeFactory(registry *llm.Registry, agentRepo agent.Repository, m
 		Registry:      registry,
 		AgentRepo:     agentRepo,
 		MemoryManager: memManager,
+		Tools:         tools.NewDefaultRegistry(),
 	}
 }
 
@@ -48,14 +53,17 @@ func (f *GenericNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 			if err != nil {
 				return nil, fmt.Errorf("failed to get default LLM provider: %w", err)
 			}
+			provider = f.Registry.WithFallbacks(provider, "default", nil)
 		}
 
 		return &EndProcessor{
-			NodeID:    node.ID,
-			LLM:       provider,
-			Model:     model,
-			Prompt:    prompt,
-			OutputKey: "summary",
+			NodeID:        node.ID,
+			LLM:           provider,
+			Provider:      f.providerName("default"),
+			Model:         model,
+			Prompt:        prompt,
+			ContextWindow: f.contextWindow(model),
+			OutputKey:     "summary",
 		}, nil
 
 	case workflow.NodeTypeAgent:
@@ -64,37 +72,43 @@ func (f *GenericNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 			return nil, fmt.Errorf("agent_uuid property missing for node %s", node.ID)
 		}
 
-		var processorTools []tools.Tool
-		if toolNames, ok := node.Properties["tools"].([]interface{}); ok {
-			for _, t := range toolNames {
-				if name, ok := t.(string); ok {
-					switch name {
-					case "write_file":
-						processorTools = append(processorTools, &tools.WriteFileTool{})
-					case "read_file":
-						processorTools = append(processorTools, &tools.ReadFileTool{})
-					}
-				}
-			}
+		outputSchema, err := f.ResolveOutputSchema(node)
+		if err != nil {
+			return nil, err
 		}
 
 		return &AgentProcessor{
-			NodeID:    node.ID,
-			AgentID:   agentID,
-			AgentRepo: f.AgentRepo,
-			Registry:  f.Registry,
-			Tools:     processorTools,
-			Session:   deps.Session,
-			OutputKey: "response",
+			NodeID:       node.ID,
+			AgentID:      agentID,
+			AgentRepo:    f.AgentRepo,
+			Registry:     f.Registry,
+			Tools:        f.ResolveNodeTools(node),
+			ToolRegistry: f.Tools,
+			Session:      deps.Session,
+			OutputKey:    "response",
+			OutputSchema: outputSchema,
 		}, nil
 
+	case workflow.NodeTypeLLM:
+		processor, err := f.CreateLLMNode(node, "response")
+		if err != nil {
+			return nil, err
+		}
+		return processor, nil
+
+	case workflow.NodeTypeTool:
+		processor, err := f.CreateToolNode(node, deps, "tool_output")
+		if err != nil {
+			return nil, err
+		}
+		return processor, nil
+
+	case workflow.NodeTypeSequence:
+		// Structural node: its steps are executed by the Engine
+		return nil, nil
+
 	case workflow.NodeTypeVote:
-		threshold, _ := node.Properties["threshold"].(float64)
-		voteType, _ := node.Properties["vote_type"].(string)
-		return &VoteProcessor{
-			Threshold: threshold,
-			VoteType:  voteType,
-		}, nil
+		return f.CreateVoteNode(node), nil
 
 	case workflow.NodeTypeLoop:
 		maxRounds, _ := node.Properties["max_rounds"].(float64)
@@ -106,19 +120,11 @@ func (f *GenericNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 		}, nil
 
 	case workflow.NodeTypeFactCheck:
-		threshold, _ := node.Properties["verify_threshold"].(float64)
-		var provider llm.LLMProvider
-		var err error
-		if f.Registry != nil {
-			provider, err = f.Registry.GetLLMProvider("default")
-			if err != nil {
-				return nil, fmt.Errorf("failed to get default LLM for fact check: %w", err)
-			}
+		processor, err := f.CreateFactCheckNode(node)
+		if err != nil {
+			return nil, err
 		}
-		return &FactCheckProcessor{
-			LLM:             provider,
-			VerifyThreshold: threshold,
-		}, nil
+		return processor, nil
 
 	case workflow.NodeTypeHumanReview:
 		timeout, _ := node.Properties["timeout_minutes"].(float64)
@@ -144,3 +150,201 @@ func (f *GenericNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 		return nil, fmt.Errorf("unsupported node type: %s", node.Type)
 	}
 }
+
+// CreateLLMNode builds an LLMProcessor from node properties. defaultOutputKey is
+// used when the node does not set "output_key".
+func (f *GenericNodeFactory) CreateLLMNode(node *workflow.Node, defaultOutputKey string) (*LLMProcessor, error) {
+	providerName, _ := node.Properties["provider"].(string)
+	model, _ := node.Properties["model"].(string)
+	systemPrompt, _ := node.Properties["system_prompt"].(string)
+	promptTemplate, _ := node.Properties["prompt_template"].(string)
+	temperature, ok := node.Properties["temperature"].(float64)
+	if !ok {
+		temperature = 0.7
+	}
+	maxTokens, _ := node.Properties["max_tokens"].(float64)
+	outputKey, _ := node.Properties["output_key"].(string)
+	if outputKey == "" {
+		outputKey = defaultOutputKey
+	}
+
+	// Resolve LLM: explicit provider, then provider owning the model, then default
+	var provider llm.LLMProvider
+	if f.Registry != nil {
+		var err error
+		switch {
+		case providerName != "":
+			provider, err = f.Registry.GetLLMProvider(providerName)
+		case model != "":
+			provider, err = f.Registry.GetProviderByModel(model)
+			providerName = f.Registry.ProviderNameForModel(model)
+		default:
+			provider, err = f.Registry.GetLLMProvider("default")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve llm provider for node %s: %w", node.ID, err)
+		}
+		provider = f.Registry.WithFallbacks(provider, providerName, nil)
+		if model == "" {
+			model = f.Registry.GetDefaultModel()
+		}
+	}
+
+	return &LLMProcessor{
+		NodeID:         node.ID,
+		LLM:            provider,
+		Provider:       f.providerName(providerName),
+		Model:          model,
+		SystemPrompt:   systemPrompt,
+		PromptTemplate: promptTemplate,
+		Temperature:    temperature,
+		MaxTokens:      int(maxTokens),
+		ContextWindow:  f.contextWindow(model),
+		OutputKey:      outputKey,
+	}, nil
+}
+
+// CreateToolNode builds a ToolProcessor for the tool named by the "tool" property.
+// defaultOutputKey is used when the node does not set "output_key".
+func (f *GenericNodeFactory) CreateToolNode(node *workflow.Node, deps workflow.FactoryDeps, defaultOutputKey string) (*ToolProcessor, error) {
+	toolName, _ := node.Properties["tool"].(string)
+	if toolName == "" {
+		return nil, fmt.Errorf("tool property missing for node %s", node.ID)
+	}
+	tool, ok := f.Tools.GetTool(toolName)
+	if !ok {
+		return nil, fmt.Errorf("unknown tool %q for node %s", toolName, node.ID)
+	}
+
+	args, _ := node.Properties["args"].(map[string]interface{})
+	mapping := make(map[string]string)
+	if raw, ok := node.Properties["args_mapping"].(map[string]interface{}); ok {
+		for arg, key := range raw {
+			inputKey, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("args_mapping.%s must be an input key for node %s", arg, node.ID)
+			}
+			mapping[arg] = inputKey
+		}
+	}
+
+	outputKey, _ := node.Properties["output_key"].(string)
+	if outputKey == "" {
+		outputKey = defaultOutputKey
+	}
+
+	return &ToolProcessor{
+		NodeID:      node.ID,
+		Tool:        tool,
+		Session:     deps.Session,
+		Args:        args,
+		ArgsMapping: mapping,
+		OutputKey:   outputKey,
+	}, nil
+}
+
+// CreateVoteNode builds a VoteProcessor from node properties.
+func (f *GenericNodeFactory) CreateVoteNode(node *workflow.Node) *VoteProcessor {
+	threshold, _ := node.Properties["threshold"].(float64)
+	voteType, _ := node.Properties["vote_type"].(string)
+	quorum, _ := node.Properties["quorum"].(float64)
+
+	weights := make(map[string]float64)
+	if raw, ok := node.Properties["weights"].(map[string]interface{}); ok {
+		for voter, w := range raw {
+			if weight, ok := w.(float64); ok {
+				weights[voter] = weight
+			}
+		}
+	}
+
+	return &VoteProcessor{
+		NodeID:    node.ID,
+		Threshold: threshold,
+		VoteType:  voteType,
+		Quorum:    quorum,
+		Weights:   weights,
+	}
+}
+
+// CreateFactCheckNode builds a FactCheckProcessor from node properties.
+func (f *GenericNodeFactory) CreateFactCheckNode(node *workflow.Node) (*FactCheckProcessor, error) {
+	threshold, _ := node.Properties["verify_threshold"].(float64)
+	maxClaims, _ := node.Properties["max_claims"].(float64)
+	concurrency, _ := node.Properties["concurrency"].(float64)
+	model, _ := node.Properties["model"].(string)
+
+	var provider llm.LLMProvider
+	providerName := "default"
+	if f.Registry != nil {
+		var err error
+		if model != "" {
+			provider, err = f.Registry.GetProviderByModel(model)
+			providerName = f.Registry.ProviderNameForModel(model)
+		} else {
+			provider, err = f.Registry.GetLLMProvider("default")
+			model = f.Registry.GetDefaultModel()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get LLM for fact check: %w", err)
+		}
+		provider = f.Registry.WithFallbacks(provider, providerName, nil)
+	}
+
+	return &FactCheckProcessor{
+		NodeID:          node.ID,
+		LLM:             provider,
+		Provider:        f.providerName(providerName),
+		Model:           model,
+		SearchClient:    f.SearchClient,
+		VerifyThreshold: threshold,
+		MaxClaims:       int(maxClaims),
+		Concurrency:     int(concurrency),
+		InputKeys:       toStringSlice(node.Properties["input_keys"]),
+	}, nil
+}
+
+// providerName resolves a provider name for the cost ledger.
+func (f *GenericNodeFactory) providerName(name string) string {
+	if f.Registry == nil {
+		return name
+	}
+	return f.Registry.ProviderName(name)
+}
+
+// contextWindow returns the model's context window, or 0 without a registry.
+func (f *GenericNodeFactory) contextWindow(model string) int {
+	if f.Registry == nil {
+		return 0
+	}
+	return f.Registry.ContextWindow(model)
+}
+
+// ResolveNodeTools returns the registered tools named by the node's "tools" property.
+func (f *GenericNodeFactory) ResolveNodeTools(node *workflow.Node) []tools.Tool {
+	var resolved []tools.Tool
+	for _, name := range toStringSlice(node.Properties["tools"]) {
+		if tool, ok := f.Tools.GetTool(name); ok {
+			resolved = append(resolved, tool)
+		}
+	}
+	return resolved
+}
+
+// ResolveOutputSchema returns the JSON Schema of the node's "output_schema"
+// property, given as an object or as a JSON string, or nil without one.
+func (f *GenericNodeFactory) ResolveOutputSchema(node *workflow.Node) (map[string]interface{}, error) {
+	switch schema := node.Properties["output_schema"].(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return schema, nil
+	case string:
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid output_schema for node %s: %w", node.ID, err)
+		}
+		return parsed, nil
+	}
+	return nil, fmt.Errorf("invalid output_schema for node %s: expected a JSON Schema object", node.ID)
+}
@@ -33,6 +33,9 @@ func TestGenericNodeFactory(t *testing.T) {
 		{workflow.NodeTypeEnd, "end", nil, false},
 		{workflow.NodeTypeAgent, "agent", map[string]interface{}{"agent_uuid": "a1"}, false},
 		{workflow.NodeTypeAgent, "agent-fail", nil, true}, // Missing agent_id
+		{workflow.NodeTypeLLM, "llm", map[string]interface{}{"prompt_template": "Summarize: {{ .doc }}"}, false},
+		{workflow.NodeTypeTool, "tool", map[string]interface{}{"tool": "read_file", "args_mapping": map[string]interface{}{"path": "target_file"}}, false},
+		{workflow.NodeTypeTool, "tool-unknown", map[string]interface{}{"tool": "nope"}, true},
 		{workflow.NodeTypeVote, "vote", nil, false},
 		{workflow.NodeTypeLoop, "loop", nil, false},
 		{workflow.NodeTypeFactCheck, "factcheck", nil, false},
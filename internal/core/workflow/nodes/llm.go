@@ -0,0 +1,149 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/llm"
+)
+
+// LLMProcessor performs a single prompt-template LLM call without an agent persona.
+// The user prompt is rendered from PromptTemplate using the node input as data,
+// e.g. "Summarize: {{ .document_content }}".
+type LLMProcessor struct {
+	NodeID          string
+	LLM             llm.LLMProvider
+	Provider        string // Name of the LLM provider, for the cost ledger
+	Model           string
+	SystemPrompt    string
+	PromptTemplate  string
+	Temperature     float64
+	MaxTokens       int
+	ContextWindow   int      // Tokens the model accepts; the input is fitted into it when > 0
+	OutputKey       string   // Configuration: Key for response content
+	PassthroughKeys []string // Configuration: Keys to pass to output
+}
+
+func (p *LLMProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- workflow.StreamEvent) (map[string]interface{}, error) {
+	if p.LLM == nil {
+		return nil, fmt.Errorf("llm node %s has no provider", p.NodeID)
+	}
+	ctx = llmContext(ctx, input, p.NodeID, stream)
+
+	messages, truncations, err := p.buildMessages(input)
+	if err != nil {
+		return nil, err
+	}
+	reportTruncations(stream, p.NodeID, promptBudget(p.Model, p.ContextWindow, p.MaxTokens), truncations)
+
+	req := &llm.CompletionRequest{
+		Model:       p.Model,
+		Messages:    messages,
+		Temperature: float32(p.Temperature),
+		MaxTokens:   p.MaxTokens,
+		Stream:      true,
+	}
+
+	started := time.Now()
+	chunkChan, errChan := p.LLM.Stream(ctx, req)
+
+	var content strings.Builder
+	var usage llm.Usage
+	for chunkChan != nil || errChan != nil {
+		select {
+		case chunk, ok := <-chunkChan:
+			if !ok {
+				chunkChan = nil
+				continue
+			}
+			if chunk.Restart {
+				// The provider failed midway and a fallback starts over
+				content.Reset()
+				stream <- workflow.StreamEvent{
+					Type:      "token_stream",
+					Timestamp: time.Now(),
+					Data:      map[string]interface{}{"node_id": p.NodeID, "chunk": "", "restart": true},
+				}
+			}
+			if chunk.Content != "" {
+				content.WriteString(chunk.Content)
+				stream <- workflow.StreamEvent{
+					Type:      "token_stream",
+					Timestamp: time.Now(),
+					Data:      map[string]interface{}{"node_id": p.NodeID, "chunk": chunk.Content},
+				}
+			}
+			if chunk.Usage != nil {
+				usage = *chunk.Usage
+			}
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("llm stream error: %w", err)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	stream <- workflow.StreamEvent{
+		Type:      "token_usage",
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"node_id":            p.NodeID,
+			"provider":           p.Provider,
+			"model":              p.Model,
+			"input_tokens":       usage.PromptTokens,
+			"output_tokens":      usage.CompletionTokens,
+			"estimated_cost_usd": workflow.PriceUsage(p.Model, usage.PromptTokens, usage.CompletionTokens),
+			"latency_ms":         time.Since(started).Milliseconds(),
+		},
+	}
+
+	outputKey := p.OutputKey
+	if outputKey == "" {
+		outputKey = "response"
+	}
+	output := map[string]interface{}{
+		outputKey:   content.String(),
+		"timestamp": time.Now(),
+	}
+
+	workflow.ApplyPassthrough(input, output, workflow.PassthroughConfig{
+		Keys: p.PassthroughKeys,
+	})
+
+	return output, nil
+}
+
+// buildMessages renders the prompt template. Without a template, the string
+// values of the input, fitted into the context window, are the user message.
+func (p *LLMProcessor) buildMessages(input map[string]interface{}) ([]llm.Message, []workflow.Truncation, error) {
+	if strings.TrimSpace(p.PromptTemplate) == "" {
+		messages, truncations := constructHistory(p.SystemPrompt, input, nil, promptBudget(p.Model, p.ContextWindow, p.MaxTokens))
+		return messages, truncations, nil
+	}
+
+	tmpl, err := template.New(p.NodeID).Option("missingkey=error").Parse(p.PromptTemplate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid prompt_template for node %s: %w", p.NodeID, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, input); err != nil {
+		return nil, nil, fmt.Errorf("failed to render prompt_template for node %s: %w", p.NodeID, err)
+	}
+
+	var messages []llm.Message
+	if p.SystemPrompt != "" {
+		messages = append(messages, llm.Message{Role: "system", Content: p.SystemPrompt})
+	}
+	return append(messages, llm.Message{Role: "user", Content: sb.String()}), nil, nil
+}
@@ -0,0 +1,103 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/llm"
+)
+
+func TestLLMProcessor_Process(t *testing.T) {
+	mockLLM := llm.NewMockProvider()
+	mockLLM.StreamContent = []string{"Short", " ", "summary"}
+
+	processor := &LLMProcessor{
+		NodeID:          "summarize",
+		LLM:             mockLLM,
+		Model:           "gpt-4",
+		PromptTemplate:  "Summarize: {{ .document_content }}",
+		OutputKey:       "summary",
+		PassthroughKeys: []string{"document_content"},
+	}
+
+	input := map[string]interface{}{"document_content": "A long document"}
+	stream := make(chan workflow.StreamEvent, 100)
+
+	output, err := processor.Process(context.Background(), input, stream)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	if summary, ok := output["summary"].(string); !ok || summary != "Short summary" {
+		t.Errorf("Expected 'Short summary', got '%v'", output["summary"])
+	}
+	if output["document_content"] != "A long document" {
+		t.Errorf("Expected document_content to pass through, got %v", output["document_content"])
+	}
+
+	close(stream)
+	var tokens, usage int
+	for e := range stream {
+		switch e.Type {
+		case "token_stream":
+			if e.Data["node_id"] != "summarize" {
+				t.Errorf("Expected token_stream from node summarize, got %v", e.Data["node_id"])
+			}
+			tokens++
+		case "token_usage":
+			usage++
+		}
+	}
+	if tokens != 3 {
+		t.Errorf("Expected 3 token events, got %d", tokens)
+	}
+	if usage != 1 {
+		t.Errorf("Expected 1 token_usage event, got %d", usage)
+	}
+}
+
+func TestLLMProcessor_Throttled(t *testing.T) {
+	limiter := llm.NewRateLimiter(map[string]llm.RateLimit{"deepseek": {MaxConcurrency: 2}})
+	limiter.Pause("deepseek", 20*time.Millisecond)
+	processor := &LLMProcessor{
+		NodeID:         "summarize",
+		LLM:            &llm.RateLimitedProvider{LLMProvider: llm.NewMockProvider(), Name: "deepseek", Limiter: limiter},
+		Model:          "deepseek-chat",
+		PromptTemplate: "Summarize",
+	}
+
+	stream := make(chan workflow.StreamEvent, 100)
+	if _, err := processor.Process(context.Background(), map[string]interface{}{"session_id": "s1"}, stream); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	close(stream)
+	event := <-stream
+	if event.Type != "llm:throttled" || event.NodeID != "summarize" || event.Data["reason"] != llm.ThrottleRetryAfter || event.Data["provider"] != "deepseek" {
+		t.Errorf("Expected the wait to be reported first, got %+v", event)
+	}
+}
+
+func TestLLMProcessor_BuildMessages(t *testing.T) {
+	processor := &LLMProcessor{
+		NodeID:         "translate",
+		SystemPrompt:   "You are a translator.",
+		PromptTemplate: "Translate to {{ .lang }}: {{ .text }}",
+	}
+
+	messages, _, err := processor.buildMessages(map[string]interface{}{"lang": "French", "text": "hello"})
+	if err != nil {
+		t.Fatalf("buildMessages failed: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Role != "system" {
+		t.Fatalf("Expected system and user messages, got %v", messages)
+	}
+	if messages[1].Content != "Translate to French: hello" {
+		t.Errorf("Unexpected rendered prompt: %q", messages[1].Content)
+	}
+
+	if _, _, err := processor.buildMessages(map[string]interface{}{"lang": "French"}); err == nil {
+		t.Error("Expected error for missing template key")
+	}
+}
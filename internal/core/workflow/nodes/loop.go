@@ -68,7 +68,7 @@ func (l *LoopProcessor) Process(ctx context.Context, input map[string]interface{
 
 	// Persist History
 	if l.Session != nil {
-		history, _ := l.Session.GetContext("score_history").([]float64)
+		history := toFloatSlice(l.Session.GetContext("score_history"))
 		history = append(history, currentScore)
 		l.Session.SetContext("score_history", history)
 
@@ -105,3 +105,22 @@ func (l *LoopProcessor) Process(ctx context.Context, input map[string]interface{
 
 	return output, nil
 }
+
+// toFloatSlice accepts both the in-memory []float64 and the []interface{}
+// produced when session context is restored from JSON.
+func toFloatSlice(v interface{}) []float64 {
+	switch vals := v.(type) {
+	case []float64:
+		return vals
+	case []interface{}:
+		out := make([]float64, 0, len(vals))
+		for _, item := range vals {
+			if f, ok := item.(float64); ok {
+				out = append(out, f)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
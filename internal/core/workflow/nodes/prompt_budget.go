@@ -0,0 +1,73 @@
+package nodes
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/llm"
+)
+
+// promptBudget returns the budget of prompts to model, whose context window
+// is window tokens, keeping maxTokens free for the reply. A zero window
+// disables it.
+func promptBudget(model string, window, maxTokens int) *workflow.PromptBudget {
+	if window <= 0 {
+		return nil
+	}
+	return &workflow.PromptBudget{
+		Model:          model,
+		ContextWindow:  window,
+		ReservedOutput: maxTokens,
+		CountTokens:    llm.CountTokens,
+	}
+}
+
+// fitSections returns the non-empty string values of the sections, fitted
+// into the budget next to the fixed text. Without configured sections, or
+// when none has a value, every string value of the input is a section.
+func fitSections(budget *workflow.PromptBudget, fixed string, input map[string]interface{}, sections []workflow.PromptSection) ([]workflow.SectionText, []workflow.Truncation, bool) {
+	var texts []workflow.SectionText
+	for _, sec := range sections {
+		if val, ok := input[sec.Key].(string); ok && val != "" {
+			texts = append(texts, workflow.SectionText{PromptSection: sec, Text: val})
+		}
+	}
+	structured := len(texts) > 0
+	if !structured {
+		keys := make([]string, 0, len(input))
+		for k := range input {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if str, ok := input[k].(string); ok {
+				texts = append(texts, workflow.SectionText{PromptSection: workflow.PromptSection{Key: k, Label: k}, Text: str})
+			}
+		}
+	}
+
+	if budget == nil {
+		return texts, nil, structured
+	}
+	fitted, truncations := budget.Fit(budget.Count(fixed), texts)
+	return fitted, truncations, structured
+}
+
+// reportTruncations tells the client which prompt sections were cut to fit
+// the model's context window.
+func reportTruncations(stream chan<- workflow.StreamEvent, nodeID string, budget *workflow.PromptBudget, truncations []workflow.Truncation) {
+	if len(truncations) == 0 {
+		return
+	}
+	stream <- workflow.StreamEvent{
+		Type:      "context:truncated",
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"node_id":        nodeID,
+			"model":          budget.Model,
+			"context_window": budget.ContextWindow,
+			"sections":       truncations,
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package nodes
+
+import (
+	"context"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/llm"
+)
+
+// llmContext prepares ctx for the LLM calls of a node: rate-limited providers
+// queue them fairly across sessions, and report waits as llm:throttled events
+// so the UI can explain the delay.
+func llmContext(ctx context.Context, input map[string]interface{}, nodeID string, stream chan<- workflow.StreamEvent) context.Context {
+	sessionID, _ := input["session_id"].(string)
+	ctx = llm.WithSession(ctx, sessionID)
+	return llm.WithThrottleListener(ctx, func(t llm.Throttle) {
+		stream <- workflow.StreamEvent{
+			Type:      "llm:throttled",
+			Timestamp: time.Now(),
+			NodeID:    nodeID,
+			Data: map[string]interface{}{
+				"node_id":  nodeID,
+				"provider": t.Provider,
+				"model":    t.Model,
+				"reason":   t.Reason,
+				"wait_ms":  t.Wait.Milliseconds(),
+			},
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
+)
+
+// ToolProcessor executes a single tool as an explicit graph step.
+// Arguments are built from static Args, then ArgsMapping copies input values
+// into tool arguments (tool argument name -> input key).
+type ToolProcessor struct {
+	NodeID          string
+	Tool            tools.Tool
+	Session         *workflow.Session
+	Args            map[string]interface{}
+	ArgsMapping     map[string]string
+	OutputKey       string   // Configuration: Key for tool result
+	PassthroughKeys []string // Configuration: Keys to pass to output
+}
+
+func (p *ToolProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- workflow.StreamEvent) (map[string]interface{}, error) {
+	if p.Tool == nil {
+		return nil, fmt.Errorf("tool node %s has no tool", p.NodeID)
+	}
+
+	args := make(map[string]interface{}, len(p.Args)+len(p.ArgsMapping))
+	for k, v := range p.Args {
+		args[k] = v
+	}
+	for arg, key := range p.ArgsMapping {
+		val, ok := input[key]
+		if !ok {
+			return nil, fmt.Errorf("tool node %s: input key %q for argument %q not found", p.NodeID, key, arg)
+		}
+		args[arg] = val
+	}
+
+	result, err := tools.Invoke(ctx, p.Tool, p.Session, args)
+
+	argsJSON, _ := json.Marshal(args)
+	event := map[string]interface{}{
+		"node_id": p.NodeID,
+		"tool":    p.Tool.Name(),
+		"input":   string(argsJSON),
+		"output":  result,
+	}
+	if err != nil {
+		event["output"] = fmt.Sprintf("Error: %v", err)
+	}
+	stream <- workflow.StreamEvent{
+		Type:      "tool_execution",
+		Timestamp: time.Now(),
+		Data:      event,
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("tool %s failed: %w", p.Tool.Name(), err)
+	}
+
+	outputKey := p.OutputKey
+	if outputKey == "" {
+		outputKey = "tool_output"
+	}
+	output := map[string]interface{}{
+		outputKey:   result,
+		"timestamp": time.Now(),
+	}
+
+	workflow.ApplyPassthrough(input, output, workflow.PassthroughConfig{
+		Keys: p.PassthroughKeys,
+	})
+
+	return output, nil
+}
@@ -0,0 +1,79 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
+)
+
+type echoTool struct{}
+
+func (t *echoTool) Name() string                       { return "echo" }
+func (t *echoTool) Description() string                { return "Echo arguments" }
+func (t *echoTool) Parameters() map[string]interface{} { return nil }
+func (t *echoTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return fmt.Sprintf("%v:%v", args["prefix"], args["text"]), nil
+}
+
+func TestToolProcessor_Process(t *testing.T) {
+	processor := &ToolProcessor{
+		NodeID:          "echo-node",
+		Tool:            &echoTool{},
+		Args:            map[string]interface{}{"prefix": "echo"},
+		ArgsMapping:     map[string]string{"text": "proposal"},
+		OutputKey:       "echoed",
+		PassthroughKeys: []string{"proposal"},
+	}
+
+	stream := make(chan workflow.StreamEvent, 10)
+	output, err := processor.Process(context.Background(), map[string]interface{}{"proposal": "hello"}, stream)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if output["echoed"] != "echo:hello" {
+		t.Errorf("Expected 'echo:hello', got %v", output["echoed"])
+	}
+	if output["proposal"] != "hello" {
+		t.Errorf("Expected proposal to pass through, got %v", output["proposal"])
+	}
+
+	close(stream)
+	var events int
+	for e := range stream {
+		if e.Type == "tool_execution" && e.Data["tool"] == "echo" {
+			events++
+		}
+	}
+	if events != 1 {
+		t.Errorf("Expected 1 tool_execution event, got %d", events)
+	}
+}
+
+func TestToolProcessor_MissingInputKey(t *testing.T) {
+	processor := &ToolProcessor{
+		NodeID:      "echo-node",
+		Tool:        &echoTool{},
+		ArgsMapping: map[string]string{"text": "missing"},
+	}
+
+	stream := make(chan workflow.StreamEvent, 10)
+	if _, err := processor.Process(context.Background(), map[string]interface{}{}, stream); err == nil {
+		t.Error("Expected error for unmapped input key")
+	}
+}
+
+func TestToolProcessor_SessionAwareToolRequiresSession(t *testing.T) {
+	processor := &ToolProcessor{
+		NodeID: "read",
+		Tool:   &tools.ReadFileTool{},
+		Args:   map[string]interface{}{"path": "main.go"},
+	}
+
+	stream := make(chan workflow.StreamEvent, 10)
+	if _, err := processor.Process(context.Background(), map[string]interface{}{}, stream); err == nil {
+		t.Error("Expected error when session-aware tool runs without a session")
+	}
+}
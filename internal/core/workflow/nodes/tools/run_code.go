@@ -0,0 +1,434 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+)
+
+// Sandbox defaults for RunCodeTool.
+const (
+	DefaultRunCodeTimeout   = 30 * time.Second
+	DefaultRunCodeCPU       = 10   // seconds
+	DefaultRunCodeMemoryMB  = 1024 // address space; the Go toolchain needs about 1 GB
+	DefaultRunCodeMaxOutput = 16 * 1024
+)
+
+// DefaultRunCodeReadOnlyPaths are the host directories visible in the sandbox,
+// besides the installation of each runtime.
+var DefaultRunCodeReadOnlyPaths = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64"}
+
+// runtimes maps a language to its entry file and command.
+var runtimes = map[string]struct {
+	file string
+	cmd  []string
+}{
+	"python": {file: "main.py", cmd: []string{"python3", "main.py"}},
+	"shell":  {file: "main.sh", cmd: []string{"sh", "main.sh"}},
+	"go":     {file: "main.go", cmd: []string{"go", "run", "main.go"}},
+}
+
+// RunCodeTool runs a Python, shell or Go snippet in a constrained local sandbox.
+// The snippet runs in fresh user, network, mount and PID namespaces (via
+// unshare) whose root is an empty tmpfs: it sees the temporary work directory
+// seeded with the session's VFS files at /work, the runtimes and system
+// directories read-only, and nothing else of the host. CPU time, address space
+// and wall-clock time are limited, and there is no network. Files created or
+// changed by the snippet can be written back to the VFS.
+//
+// A non-zero exit status is reported in the result rather than as an error, so
+// that the agent can react to failing builds or tests.
+type RunCodeTool struct {
+	Timeout        time.Duration // Wall-clock limit (default DefaultRunCodeTimeout)
+	CPUSeconds     int           // CPU time limit per process (default DefaultRunCodeCPU)
+	MemoryMB       int           // Address space limit per process (default DefaultRunCodeMemoryMB)
+	MaxOutputBytes int           // Per stream truncation (default DefaultRunCodeMaxOutput)
+	CacheDir       string        // Go build cache shared across runs (default: under os.TempDir)
+	Isolation      []string      // Command prefix creating the namespaces (default: unshare)
+	ReadOnlyPaths  []string      // Host directories visible read-only (default DefaultRunCodeReadOnlyPaths)
+}
+
+func (t *RunCodeTool) Name() string {
+	return RunCodeToolName
+}
+
+func (t *RunCodeTool) Description() string {
+	return "Run a Python, shell or Go snippet in a sandbox containing the session files (no network, limited CPU, memory and time). Returns the exit code, stdout and stderr."
+}
+
+func (t *RunCodeTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"language": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"python", "shell", "go"},
+				"description": "Language of the snippet",
+			},
+			"code": map[string]interface{}{
+				"type":        "string",
+				"description": "Source code to run (e.g. a script that builds or tests the session files)",
+			},
+			"write_back": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Write files created or changed by the snippet back to the virtual file system",
+			},
+		},
+		"required": []string{"language", "code"},
+	}
+}
+
+// Execute runs the snippet in an empty sandbox.
+func (t *RunCodeTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return t.run(ctx, nil, args)
+}
+
+// ExecuteWithSession runs the snippet in a sandbox seeded with the session files.
+func (t *RunCodeTool) ExecuteWithSession(ctx context.Context, session *workflow.Session, args map[string]interface{}) (string, error) {
+	return t.run(ctx, session, args)
+}
+
+func (t *RunCodeTool) run(ctx context.Context, session *workflow.Session, args map[string]interface{}) (string, error) {
+	language, _ := args["language"].(string)
+	rt, ok := runtimes[language]
+	if !ok {
+		return "", fmt.Errorf("unsupported language %q (use python, shell or go)", language)
+	}
+	code, ok := args["code"].(string)
+	if !ok || strings.TrimSpace(code) == "" {
+		return "", fmt.Errorf("code is required")
+	}
+	writeBack, _ := args["write_back"].(bool)
+	if writeBack && session == nil {
+		return "", fmt.Errorf("write_back requires a session")
+	}
+
+	install, err := resolveRuntime(language)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "council-run-code-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	root, err := os.MkdirTemp("", "council-run-root-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	defer os.RemoveAll(root)
+	cacheDir := t.cacheDir()
+	for _, d := range []string{cacheDir, filepath.Join(dir, ".tmp")} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return "", fmt.Errorf("failed to create sandbox: %w", err)
+		}
+	}
+
+	seeded := make(map[string]string)
+	if session != nil {
+		files, err := session.ListFiles()
+		if err != nil {
+			return "", fmt.Errorf("failed to list session files: %w", err)
+		}
+		for _, f := range files {
+			if err := writeSandboxFile(dir, f.Path, f.Content); err != nil {
+				return "", err
+			}
+			seeded[filepath.Clean(f.Path)] = f.Content
+		}
+	}
+	if err := writeSandboxFile(dir, rt.file, code); err != nil {
+		return "", err
+	}
+
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRunCodeTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	argv := t.command(root, dir, cacheDir, install, rt.cmd)
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = t.env(install)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+	killProcessGroup(cmd)
+
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case runCtx.Err() == context.DeadlineExceeded:
+			exitCode = -1
+			fmt.Fprintf(&stderr, "\n[sandbox] killed after %s", timeout)
+		case errors.As(err, &exitErr):
+			exitCode = exitErr.ExitCode()
+		default:
+			return "", fmt.Errorf("failed to start sandbox: %w", err)
+		}
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "exit_code: %d\n", exitCode)
+	fmt.Fprintf(&result, "stdout:\n%s\n", t.truncate(stdout.String()))
+	fmt.Fprintf(&result, "stderr:\n%s", t.truncate(stderr.String()))
+
+	if writeBack {
+		written, err := writeBackFiles(session, dir, rt.file, seeded)
+		if err != nil {
+			return "", err
+		}
+		if len(written) > 0 {
+			fmt.Fprintf(&result, "\nfiles written: %s", strings.Join(written, ", "))
+		}
+	}
+
+	return result.String(), nil
+}
+
+// sandboxScript runs inside the new namespaces. It mounts a tmpfs on the root
+// directory, bind-mounts the read-only paths, the work directory and the Go
+// cache into it, pivots into it and detaches the host's file system, then
+// applies the resource limits (ulimit -t: CPU seconds, ulimit -v: address
+// space KB, which also bounds mmap-backed heaps) and runs the command.
+// Arguments: root, work, cache, cpu, memory, read-only paths joined by ":",
+// then the command.
+const sandboxScript = `set -e
+root=$1 work=$2 cache=$3 cpu=$4 mem=$5 paths=$6
+shift 6
+mount -t tmpfs -o size=64m,mode=755 tmpfs "$root"
+IFS=:
+for p in $paths; do
+	[ -e "$p" ] || continue
+	if [ -L "$p" ]; then
+		mkdir -p "$root${p%/*}"
+		ln -s "$(readlink "$p")" "$root$p"
+		continue
+	fi
+	mkdir -p "$root$p"
+	mount --rbind "$p" "$root$p"
+	mount -o remount,bind,ro "$root$p"
+done
+unset IFS
+mkdir -p "$root/work" "$root/cache" "$root/proc" "$root/dev" "$root/tmp"
+mount --bind "$work" "$root/work"
+mount --bind "$cache" "$root/cache"
+for d in null zero random urandom; do
+	touch "$root/dev/$d"
+	mount --bind "/dev/$d" "$root/dev/$d"
+done
+mount -t proc proc "$root/proc"
+cd "$root"
+mkdir .old
+pivot_root . .old
+umount -l /.old
+rmdir /.old
+cd /work
+ulimit -t "$cpu"
+ulimit -v "$mem"
+exec "$@"`
+
+// command wraps the runtime command with the isolation prefix and the
+// sandbox setup script.
+func (t *RunCodeTool) command(root, dir, cacheDir string, install runtimeInstall, runtime []string) []string {
+	cpu := t.CPUSeconds
+	if cpu <= 0 {
+		cpu = DefaultRunCodeCPU
+	}
+	mem := t.MemoryMB
+	if mem <= 0 {
+		mem = DefaultRunCodeMemoryMB
+	}
+	isolation := t.Isolation
+	if isolation == nil {
+		isolation = []string{"unshare", "--user", "--map-root-user", "--net", "--mount", "--pid", "--fork", "--kill-child", "--"}
+	}
+	paths := t.ReadOnlyPaths
+	if paths == nil {
+		paths = DefaultRunCodeReadOnlyPaths
+	}
+	if install.prefix != "" {
+		paths = append(append([]string{}, paths...), install.prefix)
+	}
+
+	cmd := append([]string{}, isolation...)
+	cmd = append(cmd, "sh", "-c", sandboxScript, "sh",
+		root, dir, cacheDir, strconv.Itoa(cpu), strconv.Itoa(mem*1024), strings.Join(mountPaths(paths), ":"))
+	return append(cmd, runtime...)
+}
+
+// mountPaths drops the paths already visible through another one, which
+// could not be mounted on the read-only parent.
+func mountPaths(paths []string) []string {
+	var kept []string
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		covered := false
+		for _, q := range paths {
+			q = filepath.Clean(q)
+			if q != p && strings.HasPrefix(p, q+string(filepath.Separator)) {
+				covered = true
+				break
+			}
+		}
+		if !covered && !slices.Contains(kept, p) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func (t *RunCodeTool) cacheDir() string {
+	if t.CacheDir != "" {
+		return t.CacheDir
+	}
+	return filepath.Join(os.TempDir(), "council-run-code-cache")
+}
+
+// env is deliberately minimal: no credentials from the server environment leak
+// into the sandbox. Paths are those seen inside the sandbox.
+func (t *RunCodeTool) env(install runtimeInstall) []string {
+	path := "/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin"
+	if install.bin != "" {
+		path = install.bin + ":" + path
+	}
+	return []string{
+		"PATH=" + path,
+		"HOME=/work",
+		"TMPDIR=/work/.tmp",
+		"GOCACHE=/cache",
+		"GOPATH=/work/.gopath",
+		"GOTOOLCHAIN=local",
+		"GOPROXY=off",
+	}
+}
+
+// runtimeInstall locates a runtime installed outside the system directories
+// (e.g. a pyenv Python), which the sandbox mounts read-only.
+type runtimeInstall struct {
+	bin    string // Directory of the runtime's executables
+	prefix string // Installation directory
+}
+
+var runtimeInstalls sync.Map // language -> runtimeInstall
+
+// resolveRuntime asks the host runtime where it is installed. Shell scripts
+// only need the system directories.
+func resolveRuntime(language string) (runtimeInstall, error) {
+	if cached, ok := runtimeInstalls.Load(language); ok {
+		return cached.(runtimeInstall), nil
+	}
+
+	var query []string
+	switch language {
+	case "python":
+		query = []string{"python3", "-c", "import os, sys; print(os.path.dirname(os.path.realpath(sys.executable))); print(sys.base_prefix)"}
+	case "go":
+		query = []string{"go", "env", "GOROOT"}
+	default:
+		return runtimeInstall{}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, query[0], query[1:]...).Output()
+	if err != nil {
+		return runtimeInstall{}, fmt.Errorf("%s runtime unavailable: %w", language, err)
+	}
+
+	var install runtimeInstall
+	switch lines := strings.Fields(string(out)); {
+	case language == "go" && len(lines) == 1:
+		install = runtimeInstall{bin: filepath.Join(lines[0], "bin"), prefix: lines[0]}
+	case language == "python" && len(lines) == 2:
+		install = runtimeInstall{bin: lines[0], prefix: lines[1]}
+	default:
+		return runtimeInstall{}, fmt.Errorf("%s runtime unavailable: unexpected location %q", language, out)
+	}
+	runtimeInstalls.Store(language, install)
+	return install, nil
+}
+
+func (t *RunCodeTool) truncate(s string) string {
+	limit := t.MaxOutputBytes
+	if limit <= 0 {
+		limit = DefaultRunCodeMaxOutput
+	}
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + fmt.Sprintf("\n... (%d bytes truncated)", len(s)-limit)
+}
+
+// writeSandboxFile writes a VFS file into the sandbox, rejecting paths that
+// would escape it.
+func writeSandboxFile(dir, path, content string) error {
+	clean := filepath.Clean(path)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid file path %q", path)
+	}
+	target := filepath.Join(dir, clean)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to seed %s: %w", path, err)
+	}
+	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to seed %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeBackFiles saves new or changed regular files to the VFS, skipping the
+// snippet itself and dot-directories (e.g. the Go module cache).
+func writeBackFiles(session *workflow.Session, dir, entry string, seeded map[string]string) ([]string, error) {
+	var written []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel == entry || !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if prev, ok := seeded[rel]; ok && prev == string(content) {
+			return nil
+		}
+		vfsPath := filepath.ToSlash(rel)
+		if _, err := session.WriteFile(vfsPath, string(content), "agent", "produced by run_code"); err != nil {
+			return err
+		}
+		written = append(written, vfsPath)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write back files: %w", err)
+	}
+	return written, nil
+}
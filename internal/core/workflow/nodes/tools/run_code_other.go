@@ -0,0 +1,9 @@
+//go:build !unix
+
+package tools
+
+import "os/exec"
+
+// killProcessGroup is a no-op where process groups are unavailable; only the
+// sandbox process itself is killed on timeout.
+func killProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/mocks"
+)
+
+func newSandbox(t *testing.T) *RunCodeTool {
+	t.Helper()
+	probe := exec.Command("unshare", "--user", "--map-root-user", "--net", "--mount", "--pid", "--fork", "--",
+		"sh", "-c", `mount -t tmpfs tmpfs "$1"`, "sh", t.TempDir())
+	if err := probe.Run(); err != nil {
+		t.Skipf("namespaces unavailable: %v", err)
+	}
+	return &RunCodeTool{Timeout: 5 * time.Second}
+}
+
+func TestRunCodeTool_SeededSessionFiles(t *testing.T) {
+	tool := newSandbox(t)
+
+	session := workflow.NewSession(nil, nil)
+	session.SetFileRepository(mocks.NewMockSessionFileRepository())
+	if _, err := session.WriteFile("data/input.txt", "hello", "agent", "seed"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Invoke(context.Background(), tool, session, map[string]interface{}{
+		"language": "shell",
+		"code":     "cat data/input.txt; echo oops >&2; echo world > data/output.txt; exit 3",
+	})
+	if err != nil {
+		t.Fatalf("run_code failed: %v", err)
+	}
+	if !strings.Contains(out, "exit_code: 3") || !strings.Contains(out, "stdout:\nhello") || !strings.Contains(out, "stderr:\noops") {
+		t.Errorf("Unexpected result: %q", out)
+	}
+	if f, _ := session.GetLatestFile("data/output.txt"); f != nil {
+		t.Error("Expected no write back without write_back")
+	}
+}
+
+func TestRunCodeTool_WriteBack(t *testing.T) {
+	tool := newSandbox(t)
+
+	session := workflow.NewSession(nil, nil)
+	session.SetFileRepository(mocks.NewMockSessionFileRepository())
+	if _, err := session.WriteFile("keep.txt", "same", "agent", "seed"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Invoke(context.Background(), tool, session, map[string]interface{}{
+		"language":   "python",
+		"code":       "open('result.txt', 'w').write('42')",
+		"write_back": true,
+	})
+	if err != nil {
+		t.Fatalf("run_code failed: %v", err)
+	}
+	if !strings.Contains(out, "files written: result.txt") {
+		t.Errorf("Expected only the new file to be written back, got %q", out)
+	}
+	f, err := session.GetLatestFile("result.txt")
+	if err != nil || f.Content != "42" {
+		t.Errorf("Expected result.txt in VFS, got %v, %v", f, err)
+	}
+}
+
+func TestRunCodeTool_NoNetwork(t *testing.T) {
+	tool := newSandbox(t)
+
+	out, err := tool.Execute(context.Background(), map[string]interface{}{
+		"language": "shell",
+		"code":     "cat /proc/net/dev | tail -n +3 | cut -d: -f1 | tr -d ' '",
+	})
+	if err != nil {
+		t.Fatalf("run_code failed: %v", err)
+	}
+	if !strings.Contains(out, "stdout:\nlo\n") {
+		t.Errorf("Expected only the loopback interface, got %q", out)
+	}
+}
+
+func TestRunCodeTool_NoHostFiles(t *testing.T) {
+	tool := newSandbox(t)
+	secret := filepath.Join(t.TempDir(), "secret.env")
+	if err := os.WriteFile(secret, []byte("API_KEY=hunter2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := tool.Execute(context.Background(), map[string]interface{}{
+		"language": "shell",
+		"code":     "cat " + secret + "; ls /etc; touch /usr/pwned && echo written",
+	})
+	if err != nil {
+		t.Fatalf("run_code failed: %v", err)
+	}
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "passwd") || strings.Contains(out, "written") {
+		t.Errorf("Expected host files to be out of reach, got %q", out)
+	}
+}
+
+func TestRunCodeTool_MemoryLimit(t *testing.T) {
+	tool := newSandbox(t)
+	tool.MemoryMB = 256
+
+	out, err := tool.Execute(context.Background(), map[string]interface{}{
+		"language": "python",
+		"code":     "import mmap\nmmap.mmap(-1, 512 * 1024 * 1024)\nprint('mapped')",
+	})
+	if err != nil {
+		t.Fatalf("run_code failed: %v", err)
+	}
+	if strings.Contains(out, "stdout:\nmapped") || !strings.Contains(out, "exit_code: 1") {
+		t.Errorf("Expected the mapping to exceed the limit, got %q", out)
+	}
+}
+
+func TestRunCodeTool_Timeout(t *testing.T) {
+	tool := newSandbox(t)
+	tool.Timeout = 200 * time.Millisecond
+
+	out, err := tool.Execute(context.Background(), map[string]interface{}{
+		"language": "shell",
+		"code":     "sleep 10",
+	})
+	if err != nil {
+		t.Fatalf("run_code failed: %v", err)
+	}
+	if !strings.Contains(out, "exit_code: -1") || !strings.Contains(out, "killed after") {
+		t.Errorf("Expected timeout, got %q", out)
+	}
+}
+
+func TestRunCodeTool_InvalidArgs(t *testing.T) {
+	tool := &RunCodeTool{}
+	if _, err := tool.Execute(context.Background(), map[string]interface{}{"language": "ruby", "code": "puts 1"}); err == nil {
+		t.Error("Expected unsupported language error")
+	}
+	if _, err := tool.Execute(context.Background(), map[string]interface{}{"language": "python", "code": "print(1)", "write_back": true}); err == nil {
+		t.Error("Expected write_back to require a session")
+	}
+	if err := writeSandboxFile(t.TempDir(), "../escape.txt", "x"); err == nil {
+		t.Error("Expected paths outside the sandbox to be rejected")
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/search"
+)
+
+// WebSearchToolName is the name under which WebSearchTool is registered.
+const WebSearchToolName = "web_search"
+
+// WebSearchTool queries a search provider and returns the results as text.
+// Within a session, the search is scoped to the session's group and files, so
+// that a corpus provider never returns another group's memories.
+type WebSearchTool struct {
+	Client search.SearchClient
+}
+
+func (t *WebSearchTool) Name() string {
+	return WebSearchToolName
+}
+
+func (t *WebSearchTool) Description() string {
+	return "Search the web and return the most relevant results with their sources."
+}
+
+func (t *WebSearchTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "Search query",
+			},
+			"max_results": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of results (default 5)",
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+func (t *WebSearchTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return t.search(ctx, search.SearchOptions{}, args)
+}
+
+// ExecuteWithSession searches within the session's group and VFS files.
+func (t *WebSearchTool) ExecuteWithSession(ctx context.Context, session *workflow.Session, args map[string]interface{}) (string, error) {
+	groupID, _ := session.Inputs["group_uuid"].(string)
+	return t.search(ctx, search.SearchOptions{GroupID: groupID, SessionID: session.ID}, args)
+}
+
+func (t *WebSearchTool) search(ctx context.Context, scope search.SearchOptions, args map[string]interface{}) (string, error) {
+	if t.Client == nil {
+		return "", fmt.Errorf("no search provider configured")
+	}
+	query, ok := args["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	maxResults, _ := args["max_results"].(float64)
+
+	scope.MaxResults = int(maxResults)
+	scope.SearchType = "search"
+	result, err := t.Client.Search(ctx, query, scope)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if result.Answer != "" {
+		sb.WriteString(fmt.Sprintf("Answer: %s\n\n", result.Answer))
+	}
+	for i, item := range result.Results {
+		sb.WriteString(fmt.Sprintf("[%d] %s (%s)\n%s\n\n", i+1, item.Title, item.URL, item.Content))
+	}
+	if sb.Len() == 0 {
+		return "No results found.", nil
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
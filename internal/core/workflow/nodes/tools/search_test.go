@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/search"
+)
+
+// groupedSearch returns the memories of the requested group, or of every
+// group without a scope, as CorpusClient does.
+type groupedSearch struct {
+	memories map[string]search.SearchItem // group -> memory
+	opts     search.SearchOptions
+}
+
+func (s *groupedSearch) Search(ctx context.Context, query string, opts search.SearchOptions) (*search.SearchResult, error) {
+	s.opts = opts
+	result := &search.SearchResult{Query: query}
+	for group, item := range s.memories {
+		if opts.GroupID == "" || opts.GroupID == group {
+			result.Results = append(result.Results, item)
+		}
+	}
+	return result, nil
+}
+
+func TestWebSearchTool_ScopedToSession(t *testing.T) {
+	client := &groupedSearch{memories: map[string]search.SearchItem{
+		"group-a": {Title: "Memory", URL: "memory://a", Content: "Group A budget"},
+		"group-b": {Title: "Memory", URL: "memory://b", Content: "Group B budget"},
+	}}
+	tool := &WebSearchTool{Client: client}
+
+	session := workflow.NewSession(nil, map[string]interface{}{"group_uuid": "group-a"})
+	session.ID = "session-a"
+	out, err := Invoke(context.Background(), tool, session, map[string]interface{}{"query": "budget"})
+	if err != nil {
+		t.Fatalf("web_search failed: %v", err)
+	}
+	if !strings.Contains(out, "memory://a") || strings.Contains(out, "memory://b") {
+		t.Errorf("Expected only group A's memory, got %q", out)
+	}
+	if client.opts.GroupID != "group-a" || client.opts.SessionID != "session-a" {
+		t.Errorf("Expected the session's scope, got %+v", client.opts)
+	}
+}
@@ -2,6 +2,9 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/hrygo/council/internal/core/workflow"
 )
@@ -20,8 +23,24 @@ type SessionAwareTool interface {
 	ExecuteWithSession(ctx context.Context, session *workflow.Session, args map[string]interface{}) (string, error)
 }
 
-// Registry manages available tools
+// Invoke executes a tool, passing the session to tools that need it.
+func Invoke(ctx context.Context, tool Tool, session *workflow.Session, args map[string]interface{}) (string, error) {
+	if sat, ok := tool.(SessionAwareTool); ok {
+		if session == nil {
+			return "", fmt.Errorf("tool %s requires a session", tool.Name())
+		}
+		return sat.ExecuteWithSession(ctx, session, args)
+	}
+	return tool.Execute(ctx, args)
+}
+
+// RunCodeToolName is the name of the sandboxed code execution tool granted by
+// the code_execution capability.
+const RunCodeToolName = "run_code"
+
+// Registry manages available tools. It is safe for concurrent use.
 type Registry struct {
+	mu    sync.RWMutex
 	tools map[string]Tool
 }
 
@@ -31,19 +50,35 @@ func NewRegistry() *Registry {
 	}
 }
 
+// NewDefaultRegistry creates a registry with the built-in tools that need no configuration.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(&ReadFileTool{})
+	r.Register(&WriteFileTool{})
+	return r
+}
+
 func (r *Registry) Register(tool Tool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.tools[tool.Name()] = tool
 }
 
 func (r *Registry) GetTool(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	t, ok := r.tools[name]
 	return t, ok
 }
 
+// ListTools returns the registered tools ordered by name.
 func (r *Registry) ListTools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var list []Tool
 	for _, t := range r.tools {
 		list = append(list, t)
 	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
 	return list
 }
@@ -2,68 +2,302 @@ package nodes
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/hrygo/council/internal/core/workflow"
 )
 
+// Supported vote types.
+const (
+	VoteTypeMajority      = "majority"
+	VoteTypeSupermajority = "supermajority"
+	VoteTypeUnanimous     = "unanimous"
+	VoteTypeWeighted      = "weighted"
+	VoteTypeRankedChoice  = "ranked_choice"
+)
+
+// VoteProcessor tallies the ballots of upstream agents (see ParseBallot).
+// Abstentions count towards participation but not towards the approval ratio.
+// It routes to NextIDs[0] when approved and NextIDs[1] when rejected.
 type VoteProcessor struct {
-	Threshold float64
-	VoteType  string
+	NodeID          string
+	Threshold       float64            // Approval ratio; defaults depend on VoteType
+	VoteType        string             // majority (default) | supermajority | unanimous | weighted | ranked_choice
+	Quorum          float64            // >= 1: minimum cast votes; < 1: minimum share of non-abstaining ballots
+	Weights         map[string]float64 // weighted: voter -> weight (default 1)
+	PassthroughKeys []string           // Configuration: Keys to pass to output
+}
+
+// GetNextNodes implements workflow.ConditionalRouter.
+func (v *VoteProcessor) GetNextNodes(ctx context.Context, output map[string]interface{}, defaultNextIDs []string) ([]string, error) {
+	if len(defaultNextIDs) < 2 {
+		return defaultNextIDs, nil
+	}
+	if approved, _ := output["approved"].(bool); approved {
+		return []string{defaultNextIDs[0]}, nil
+	}
+	return []string{defaultNextIDs[1]}, nil
 }
 
 func (v *VoteProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- workflow.StreamEvent) (map[string]interface{}, error) {
 	stream <- workflow.StreamEvent{
 		Type:      "node_state_change",
 		Timestamp: time.Now(),
+		NodeID:    v.NodeID,
 		Data:      map[string]interface{}{"status": "running"},
 	}
 
-	// This is a placeholder logic.
-	// Real implementation would inspect `input` (results from previous nodes, e.g. parallel agents)
-	// and extract votes.
-
-	// Assuming input contains a list of "agent_outputs" or similar which are strings "YES" or "NO" or scores.
-	// For simulation, we assume inputs might have keys like "agent_1_result": "YES"
-
-	yesVotes := 0
-	totalVotes := 0
+	voteType := v.VoteType
+	if voteType == "" {
+		voteType = VoteTypeMajority
+	}
 
-	// Mock: Scan input values for simplistic "YES"/"NO" detection
-	for _, val := range input {
-		if str, ok := val.(string); ok {
-			upper := str // In real code: strings.ToUpper
-			if upper == "YES" || upper == "APPROVED" {
-				yesVotes++
+	ballots := collectBallots(input)
+	var yes, no, abstain int
+	var yesWeight, noWeight float64
+	for _, b := range ballots {
+		b.Weight = 1
+		if voteType == VoteTypeWeighted {
+			if w, ok := v.Weights[b.Voter]; ok {
+				b.Weight = w
 			}
-			totalVotes++
+		}
+		switch b.Vote {
+		case VoteApprove:
+			yes++
+			yesWeight += b.Weight
+		case VoteReject:
+			no++
+			noWeight += b.Weight
+		default:
+			abstain++
 		}
 	}
 
-	// If no inputs suitable, mock success for development flow
-	if totalVotes == 0 {
-		// Mock behavior: If this node runs, assume it passes for now unless configured otherwise
-		yesVotes = 1
-		totalVotes = 1
+	ratio := 0.0
+	if yesWeight+noWeight > 0 {
+		ratio = yesWeight / (yesWeight + noWeight)
 	}
 
-	ratio := float64(yesVotes) / float64(totalVotes)
-	approved := ratio >= v.Threshold
-
+	quorumMet := v.quorumMet(yes+no, len(ballots))
 	output := map[string]interface{}{
-		"approved":    approved,
-		"yes_votes":   yesVotes,
-		"total_votes": totalVotes,
+		"vote_type":   voteType,
+		"yes_votes":   yes,
+		"no_votes":    no,
+		"abstentions": abstain,
+		"total_votes": len(ballots),
 		"ratio":       ratio,
+		"quorum_met":  quorumMet,
+		"ballots":     ballotsOutput(ballots),
 		"timestamp":   time.Now(),
 	}
 
-	status := "completed"
+	var approved bool
+	switch {
+	case !quorumMet:
+		output["reason"] = "quorum_not_met"
+	case voteType == VoteTypeRankedChoice:
+		winner, rounds := rankedChoice(ballots)
+		approved = winner != ""
+		output["winner"] = winner
+		output["rounds"] = rounds
+		if !approved {
+			output["reason"] = "no_majority_winner"
+		}
+	case voteType == VoteTypeUnanimous:
+		approved = yes > 0 && no == 0
+	case voteType == VoteTypeMajority, voteType == VoteTypeSupermajority, voteType == VoteTypeWeighted:
+		approved = v.passes(voteType, ratio)
+	default:
+		return nil, fmt.Errorf("unsupported vote_type %q", voteType)
+	}
+	output["approved"] = approved
+
+	workflow.ApplyPassthrough(input, output, workflow.PassthroughConfig{
+		Keys: v.PassthroughKeys,
+	})
+
 	stream <- workflow.StreamEvent{
 		Type:      "node_state_change",
 		Timestamp: time.Now(),
-		Data:      map[string]interface{}{"status": status, "result": approved},
+		NodeID:    v.NodeID,
+		Data:      map[string]interface{}{"status": "completed", "result": approved},
 	}
 
 	return output, nil
 }
+
+// passes applies the approval threshold. An explicit Threshold is inclusive;
+// the defaults are a strict majority and a two-thirds supermajority.
+func (v *VoteProcessor) passes(voteType string, ratio float64) bool {
+	if v.Threshold > 0 {
+		return ratio >= v.Threshold
+	}
+	if voteType == VoteTypeSupermajority {
+		return ratio >= 2.0/3.0
+	}
+	return ratio > 0.5
+}
+
+func (v *VoteProcessor) quorumMet(cast, total int) bool {
+	switch {
+	case v.Quorum >= 1:
+		return float64(cast) >= v.Quorum
+	case v.Quorum > 0:
+		return total > 0 && float64(cast)/float64(total) >= v.Quorum
+	default:
+		return cast > 0
+	}
+}
+
+// collectBallots reads one ballot per upstream branch ("branch_N" maps from a
+// merge). Without branches, string values of the input are read as ballots.
+// Inputs that contain no ballot are ignored.
+func collectBallots(input map[string]interface{}) []*Ballot {
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var ballots []*Ballot
+	for _, k := range keys {
+		branch, ok := input[k].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if b := ballotFromOutput(branch); b != nil {
+			b.Voter = k
+			if agentID, ok := branch["agent_id"].(string); ok && agentID != "" {
+				b.Voter = agentID
+			}
+			ballots = append(ballots, b)
+		}
+	}
+	if len(ballots) > 0 {
+		return ballots
+	}
+
+	for _, k := range keys {
+		content, ok := input[k].(string)
+		if !ok {
+			continue
+		}
+		if b, err := ParseBallot(content); err == nil {
+			b.Voter = k
+			if agentID, ok := input["agent_id"].(string); ok && agentID != "" && (k == "agent_output" || k == "response") {
+				b.Voter = agentID
+			}
+			ballots = append(ballots, b)
+		}
+	}
+	return ballots
+}
+
+// ballotFromOutput reads a ballot from a node output, either as fields of the
+// output itself or from its text content.
+func ballotFromOutput(output map[string]interface{}) *Ballot {
+	if vote, ok := output["vote"].(string); ok {
+		if choice := normalizeVote(vote); choice != "" {
+			rationale, _ := output["rationale"].(string)
+			return &Ballot{Vote: choice, Rationale: rationale, Ranking: toStringSlice(output["ranking"])}
+		}
+	}
+	for _, key := range []string{"agent_output", "response"} {
+		if content, ok := output[key].(string); ok {
+			if b, err := ParseBallot(content); err == nil {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+// rankedChoice runs an instant-runoff count over the ballot rankings. It
+// returns the option preferred by a majority of non-exhausted ballots, or ""
+// when no option reaches a majority, together with the tally of every round.
+func rankedChoice(ballots []*Ballot) (string, []map[string]int) {
+	eliminated := make(map[string]bool)
+	var rounds []map[string]int
+
+	for {
+		tally := make(map[string]int)
+		active := 0
+		for _, b := range ballots {
+			if b.Vote == VoteAbstain {
+				continue
+			}
+			for _, option := range b.Ranking {
+				if !eliminated[option] {
+					tally[option]++
+					active++
+					break
+				}
+			}
+		}
+		rounds = append(rounds, tally)
+		if len(tally) == 0 {
+			return "", rounds
+		}
+
+		options := make([]string, 0, len(tally))
+		for option := range tally {
+			options = append(options, option)
+		}
+		sort.Strings(options)
+
+		lowest, highest := options[0], options[0]
+		for _, option := range options {
+			if tally[option]*2 > active {
+				return option, rounds
+			}
+			if tally[option] < tally[lowest] {
+				lowest = option
+			}
+			if tally[option] > tally[highest] {
+				highest = option
+			}
+		}
+		if tally[lowest] == tally[highest] {
+			return "", rounds // Remaining options are tied
+		}
+		eliminated[lowest] = true
+	}
+}
+
+func ballotsOutput(ballots []*Ballot) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(ballots))
+	for _, b := range ballots {
+		entry := map[string]interface{}{
+			"voter":     b.Voter,
+			"vote":      b.Vote,
+			"rationale": b.Rationale,
+			"weight":    b.Weight,
+		}
+		if len(b.Ranking) > 0 {
+			entry["ranking"] = b.Ranking
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// toStringSlice accepts both []string and the []interface{} produced by JSON.
+func toStringSlice(v interface{}) []string {
+	switch vals := v.(type) {
+	case []string:
+		return vals
+	case []interface{}:
+		out := make([]string, 0, len(vals))
+		for _, item := range vals {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/hrygo/council/internal/core/workflow"
@@ -36,11 +37,14 @@ func TestVoteProcessor_Process(t *testing.T) {
 		t.Error("expected approved to be false for 1/3 ratio")
 	}
 
-	// Test case 3: empty input (fallthrough mock)
+	// Test case 3: empty input has no ballots and therefore no quorum
 	input3 := map[string]interface{}{}
 	output3, _ := p.Process(context.Background(), input3, stream)
-	if !output3["approved"].(bool) {
-		t.Error("expected approved to be true for empty input mock")
+	if output3["approved"].(bool) {
+		t.Error("expected approved to be false without ballots")
+	}
+	if output3["quorum_met"].(bool) {
+		t.Error("expected quorum not to be met without ballots")
 	}
 }
 
@@ -70,3 +74,121 @@ func TestVoteProcessor_AllApproved(t *testing.T) {
 		t.Errorf("expected 2 yes votes, got %v", output["yes_votes"])
 	}
 }
+
+// branches builds merged input where each agent emits a JSON ballot.
+func branches(votes map[string]string) map[string]interface{} {
+	input := map[string]interface{}{"session_id": "s1", "proposal": "Adopt plan A"}
+	i := 0
+	for agentID, vote := range votes {
+		input[fmt.Sprintf("branch_%d", i)] = map[string]interface{}{
+			"agent_id":     agentID,
+			"agent_output": fmt.Sprintf("My analysis.\n```json\n{\"vote\": %q, \"rationale\": \"because %s\"}\n```", vote, agentID),
+		}
+		i++
+	}
+	return input
+}
+
+func TestVoteProcessor_ParsedBallots(t *testing.T) {
+	p := &VoteProcessor{}
+	stream := make(chan workflow.StreamEvent, 10)
+
+	output, err := p.Process(context.Background(), branches(map[string]string{
+		"a1": "approve", "a2": "reject", "a3": "abstain", "a4": "approve",
+	}), stream)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if !output["approved"].(bool) {
+		t.Error("expected 2 approve vs 1 reject to pass a majority vote")
+	}
+	if output["abstentions"].(int) != 1 || output["no_votes"].(int) != 1 {
+		t.Errorf("unexpected tally: %v", output)
+	}
+
+	ballots := output["ballots"].([]map[string]interface{})
+	if len(ballots) != 4 {
+		t.Fatalf("expected 4 ballots, got %d", len(ballots))
+	}
+	for _, b := range ballots {
+		if b["rationale"] != "because "+b["voter"].(string) {
+			t.Errorf("expected per-voter rationale, got %v", b)
+		}
+	}
+}
+
+func TestVoteProcessor_VoteTypes(t *testing.T) {
+	votes := map[string]string{"a1": "approve", "a2": "approve", "a3": "reject"}
+
+	testCases := []struct {
+		name     string
+		p        *VoteProcessor
+		approved bool
+	}{
+		{"majority", &VoteProcessor{VoteType: VoteTypeMajority}, true},
+		{"supermajority", &VoteProcessor{VoteType: VoteTypeSupermajority}, true},
+		{"supermajority strict threshold", &VoteProcessor{VoteType: VoteTypeSupermajority, Threshold: 0.75}, false},
+		{"unanimous", &VoteProcessor{VoteType: VoteTypeUnanimous}, false},
+		{"weighted", &VoteProcessor{VoteType: VoteTypeWeighted, Weights: map[string]float64{"a3": 3}}, false},
+		{"quorum count", &VoteProcessor{Quorum: 4}, false},
+		{"quorum share", &VoteProcessor{Quorum: 0.5}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := make(chan workflow.StreamEvent, 10)
+			output, err := tc.p.Process(context.Background(), branches(votes), stream)
+			if err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+			if output["approved"].(bool) != tc.approved {
+				t.Errorf("expected approved=%v, got %v", tc.approved, output)
+			}
+		})
+	}
+}
+
+func TestVoteProcessor_RankedChoice(t *testing.T) {
+	rankings := map[string][]interface{}{
+		"a1": {"plan_a", "plan_b"},
+		"a2": {"plan_b", "plan_a"},
+		"a3": {"plan_c", "plan_b"},
+		"a4": {"plan_a", "plan_c"},
+		"a5": {"plan_b", "plan_c"},
+	}
+	input := map[string]interface{}{}
+	i := 0
+	for agentID, ranking := range rankings {
+		input[fmt.Sprintf("branch_%d", i)] = map[string]interface{}{"agent_id": agentID, "vote": "approve", "ranking": ranking}
+		i++
+	}
+
+	p := &VoteProcessor{VoteType: VoteTypeRankedChoice}
+	stream := make(chan workflow.StreamEvent, 10)
+	output, err := p.Process(context.Background(), input, stream)
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	// Round 1: a=2 b=2 c=1 -> c eliminated, a3 transfers to b -> b=3 of 5
+	if output["winner"] != "plan_b" {
+		t.Errorf("expected plan_b to win after runoff, got %v", output["winner"])
+	}
+	if rounds := output["rounds"].([]map[string]int); len(rounds) != 2 {
+		t.Errorf("expected 2 rounds, got %v", rounds)
+	}
+}
+
+func TestVoteProcessor_GetNextNodes(t *testing.T) {
+	p := &VoteProcessor{}
+	next := []string{"approved_path", "rejected_path"}
+
+	got, _ := p.GetNextNodes(context.Background(), map[string]interface{}{"approved": true}, next)
+	if len(got) != 1 || got[0] != "approved_path" {
+		t.Errorf("expected approved route, got %v", got)
+	}
+	got, _ = p.GetNextNodes(context.Background(), map[string]interface{}{"approved": false}, next)
+	if len(got) != 1 || got[0] != "rejected_path" {
+		t.Errorf("expected rejected route, got %v", got)
+	}
+}
@@ -25,4 +25,11 @@ func ApplyPassthrough(input, output map[string]interface{}, config PassthroughCo
 type PromptSection struct {
 	Key   string
 	Label string
+	// Priority decides which sections keep their content when the prompt
+	// does not fit the model's context window: higher first (see PromptBudget).
+	Priority int
+	// KeepRecent trims the section from its start, so that the oldest
+	// entries (split by Separator) are dropped first instead of the end.
+	KeepRecent bool
+	Separator  string
 }
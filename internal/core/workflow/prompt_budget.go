@@ -0,0 +1,175 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultReservedOutput is the room left for the reply when the budget
+	// configures none; it is capped at a quarter of the context window.
+	DefaultReservedOutput = 4096
+
+	sectionOverhead = 8  // Tokens of a section's label and tags
+	markerReserve   = 16 // Tokens kept for the truncation marker
+)
+
+// PromptBudget fits prompt sections into a model's context window.
+type PromptBudget struct {
+	Model          string
+	ContextWindow  int                          // Tokens the model accepts, prompt and reply; 0 disables the budget
+	ReservedOutput int                          // Tokens kept free for the reply; 0 reserves DefaultReservedOutput
+	CountTokens    func(model, text string) int // Tokenizer; nil assumes 4 characters per token
+}
+
+// SectionText is the content of a prompt section.
+type SectionText struct {
+	PromptSection
+	Text string
+}
+
+// Truncation records what a PromptBudget cut from a section.
+type Truncation struct {
+	Key            string `json:"key"`
+	Label          string `json:"label"`
+	OriginalTokens int    `json:"original_tokens"`
+	KeptTokens     int    `json:"kept_tokens"`
+	DroppedEntries int    `json:"dropped_entries,omitempty"` // Whole entries dropped from a KeepRecent section
+}
+
+// Fit returns the sections cut down to fit the context window next to fixed
+// tokens, such as the system prompt. Sections are served by descending
+// Priority; sections of equal priority share what is left, the unused share
+// of a short section going to the longer ones. A section that gets no room
+// is emptied. Nothing is cut when the sections fit.
+func (b *PromptBudget) Fit(fixed int, sections []SectionText) ([]SectionText, []Truncation) {
+	if b == nil || b.ContextWindow <= 0 || len(sections) == 0 {
+		return sections, nil
+	}
+
+	tokens := make([]int, len(sections))
+	total := 0
+	for i, s := range sections {
+		tokens[i] = b.Count(s.Text)
+		total += tokens[i]
+	}
+	available := max(b.ContextWindow-b.reserved()-fixed-sectionOverhead*len(sections), 0)
+	if total <= available {
+		return sections, nil
+	}
+
+	order := make([]int, len(sections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(x, y int) bool {
+		return sections[order[x]].Priority > sections[order[y]].Priority
+	})
+
+	allot := make([]int, len(sections))
+	remaining := available
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && sections[order[end]].Priority == sections[order[start]].Priority {
+			end++
+		}
+		group := append([]int(nil), order[start:end]...)
+		sort.SliceStable(group, func(x, y int) bool { return tokens[group[x]] < tokens[group[y]] })
+		for k, i := range group {
+			allot[i] = min(tokens[i], remaining/(len(group)-k))
+			remaining -= allot[i]
+		}
+		start = end
+	}
+
+	fitted := make([]SectionText, len(sections))
+	var truncations []Truncation
+	for i, s := range sections {
+		fitted[i] = s
+		if allot[i] >= tokens[i] {
+			continue
+		}
+		var dropped int
+		fitted[i].Text, dropped = b.cut(s, allot[i])
+		truncations = append(truncations, Truncation{
+			Key:            s.Key,
+			Label:          s.Label,
+			OriginalTokens: tokens[i],
+			KeptTokens:     b.Count(fitted[i].Text),
+			DroppedEntries: dropped,
+		})
+	}
+	return fitted, truncations
+}
+
+func (b *PromptBudget) reserved() int {
+	if b.ReservedOutput > 0 {
+		return b.ReservedOutput
+	}
+	return min(DefaultReservedOutput, b.ContextWindow/4)
+}
+
+// Count returns the tokens text takes in the model's prompt.
+func (b *PromptBudget) Count(text string) int {
+	if b.CountTokens == nil {
+		return len(text) / 4
+	}
+	return b.CountTokens(b.Model, text)
+}
+
+// cut shortens a section's text to about limit tokens, marking the cut. A
+// KeepRecent section loses whole entries from its start first and reports
+// how many it dropped.
+func (b *PromptBudget) cut(s SectionText, limit int) (string, int) {
+	limit -= markerReserve
+	if limit <= 0 {
+		if s.KeepRecent && s.Separator != "" {
+			return "", len(strings.Split(s.Text, s.Separator))
+		}
+		return "", 0
+	}
+
+	if !s.KeepRecent {
+		return b.prefix(s.Text, limit) + "\n[... truncated to fit the context window ...]", 0
+	}
+
+	text, dropped := s.Text, 0
+	if s.Separator != "" {
+		// Keep the most recent entries that fit whole, at least the last one
+		entries := strings.Split(s.Text, s.Separator)
+		first := len(entries) - 1
+		used := b.Count(entries[first])
+		for first > 0 {
+			next := used + b.Count(s.Separator) + b.Count(entries[first-1])
+			if next > limit {
+				break
+			}
+			used = next
+			first--
+		}
+		dropped = first
+		text = strings.Join(entries[first:], s.Separator)
+		if used <= limit {
+			if dropped == 0 {
+				return text, 0
+			}
+			return fmt.Sprintf("[... %d earlier entries dropped to fit the context window ...]\n%s", dropped, text), dropped
+		}
+	}
+	return "[... earlier content truncated to fit the context window ...]\n" + b.suffix(text, limit), dropped
+}
+
+// prefix returns the longest start of text within limit tokens.
+func (b *PromptBudget) prefix(text string, limit int) string {
+	runes := []rune(text)
+	n := sort.Search(len(runes)+1, func(n int) bool { return b.Count(string(runes[:n])) > limit })
+	return string(runes[:max(n-1, 0)])
+}
+
+// suffix returns the longest end of text within limit tokens.
+func (b *PromptBudget) suffix(text string, limit int) string {
+	runes := []rune(text)
+	n := sort.Search(len(runes)+1, func(n int) bool { return b.Count(string(runes[len(runes)-n:])) > limit })
+	return string(runes[len(runes)-max(n-1, 0):])
+}
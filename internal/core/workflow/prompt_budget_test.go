@@ -0,0 +1,74 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func words(n int, word string) string {
+	return strings.TrimSpace(strings.Repeat(word+" ", n))
+}
+
+func TestPromptBudget_Fit(t *testing.T) {
+	budget := &PromptBudget{Model: "m", ContextWindow: 1000, ReservedOutput: 100, CountTokens: countWords}
+	var rounds []string
+	for i := 0; i < 5; i++ {
+		rounds = append(rounds, words(100, "round"))
+	}
+	sections := []SectionText{
+		{PromptSection: PromptSection{Key: "objective", Priority: 2}, Text: words(20, "goal")},
+		{PromptSection: PromptSection{Key: "document"}, Text: words(2000, "doc")},
+		{PromptSection: PromptSection{Key: "outputs", KeepRecent: true, Separator: "\n---\n"}, Text: strings.Join(rounds, "\n---\n")},
+	}
+
+	// The prompt fits as long as nothing is long
+	small := []SectionText{sections[0], {PromptSection: sections[1].PromptSection, Text: "short"}}
+	if fitted, truncations := budget.Fit(50, small); truncations != nil || fitted[1].Text != "short" {
+		t.Errorf("Expected a prompt within the window to be kept, got %v", truncations)
+	}
+
+	// 1000 - 100 reserved - 50 fixed - 3*8 overhead = 826 tokens; the
+	// objective keeps its 20, the document and outputs share the other 806
+	fitted, truncations := budget.Fit(50, sections)
+	if fitted[0].Text != sections[0].Text {
+		t.Errorf("Expected the high priority section to be kept whole")
+	}
+	if len(truncations) != 2 {
+		t.Fatalf("Expected the document and outputs to be truncated, got %+v", truncations)
+	}
+
+	doc, outputs := truncations[0], truncations[1]
+	if doc.Key != "document" || doc.OriginalTokens != 2000 || doc.KeptTokens > 403 || doc.KeptTokens < 380 ||
+		!strings.HasPrefix(fitted[1].Text, "doc doc") || !strings.HasSuffix(fitted[1].Text, "...]") {
+		t.Errorf("Expected the document cut at its end to about 403 tokens, got %+v", doc)
+	}
+	if outputs.Key != "outputs" || outputs.DroppedEntries != 2 || strings.Count(fitted[2].Text, "---") != 2 ||
+		!strings.HasPrefix(fitted[2].Text, "[... 2 earlier entries dropped") {
+		t.Errorf("Expected the two oldest rounds to be dropped, got %+v: %q", outputs, fitted[2].Text[:60])
+	}
+	if total := 20 + doc.KeptTokens + outputs.KeptTokens; total > 826 {
+		t.Errorf("Expected the sections to fit 826 tokens, got %d", total)
+	}
+
+	// A lower priority gets nothing once the higher ones used the window
+	sections[1].Priority = 1
+	fitted, truncations = budget.Fit(50, sections)
+	if len(truncations) != 2 || truncations[1].KeptTokens != 0 || truncations[1].DroppedEntries != 5 || fitted[2].Text != "" {
+		t.Errorf("Expected the outputs to be dropped, got %+v", truncations)
+	}
+}
+
+func TestPromptBudget_KeepRecentWithoutEntries(t *testing.T) {
+	budget := &PromptBudget{ContextWindow: 200, ReservedOutput: 50, CountTokens: countWords}
+	text := words(100, "old") + " " + words(200, "new")
+
+	fitted, truncations := budget.Fit(0, []SectionText{{PromptSection: PromptSection{Key: "history", KeepRecent: true}, Text: text}})
+	if len(truncations) != 1 || !strings.HasPrefix(fitted[0].Text, "[... earlier content truncated") ||
+		!strings.HasSuffix(fitted[0].Text, "new new") || strings.Contains(fitted[0].Text, "old") {
+		t.Errorf("Expected the start of the history to be cut, got %+v: %q", truncations, fitted[0].Text)
+	}
+
+	if _, truncations := (*PromptBudget)(nil).Fit(0, []SectionText{{Text: text}}); truncations != nil {
+		t.Error("Expected a nil budget to keep everything")
+	}
+}
@@ -0,0 +1,281 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// recoveryStep is a unit of work needed to continue a restored session.
+type recoveryStep struct {
+	nodeID string
+	input  map[string]interface{}
+	reuse  map[string]map[string]interface{} // Parallel and sequence only: children that already completed
+}
+
+// Recover continues a restored session from the frontier of incomplete nodes
+// instead of restarting at StartNodeID. It relies on the node statuses and
+// outputs loaded by NewEngine from the session (see RestoreSession).
+//
+// The frontier consists of:
+//   - nodes that were running when the process stopped (re-executed),
+//   - nodes that were never started although all their upstream nodes completed.
+//
+// Suspended nodes are left untouched; they continue through ResumeNode.
+func (e *Engine) Recover(ctx context.Context) error {
+	if err := e.Graph.Validate(); err != nil {
+		e.emitError("validation_failed", err)
+		return err
+	}
+
+	startNodeID := e.Graph.StartNodeID
+	if startNodeID == "" {
+		return fmt.Errorf("no start node defined")
+	}
+
+	// Nothing ran yet: a normal run is the correct recovery
+	if status := e.GetStatus(startNodeID); status == "" || status == StatusPending {
+		return e.Run(ctx)
+	}
+
+	steps := e.recoveryPlan(ctx)
+
+	frontier := make([]string, 0, len(steps))
+	for _, step := range steps {
+		frontier = append(frontier, step.nodeID)
+	}
+	e.StreamChannel <- StreamEvent{
+		Type:      "execution:recovered",
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"frontier": frontier},
+	}
+	log.Printf("[Engine] Recovering session %s from frontier %v", e.Session.ID, frontier)
+
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	for _, step := range steps {
+		wg.Add(1)
+		go func(step recoveryStep) {
+			defer wg.Done()
+
+			e.Mu.RLock()
+			node := e.Graph.Nodes[step.nodeID]
+			e.Mu.RUnlock()
+
+			var err error
+			switch node.Type {
+			case NodeTypeParallel:
+				e.runParallel(ctx, node, step.input, step.reuse)
+			case NodeTypeSequence:
+				err = e.executeSequence(ctx, node, step.input, step.reuse)
+			default:
+				err = e.executeNode(ctx, step.nodeID, step.input)
+			}
+			if err != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errMu.Unlock()
+			}
+		}(step)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// recoveryPlan computes the frontier of a restored engine and seeds the
+// pending join inputs of nodes that are only partially satisfied.
+func (e *Engine) recoveryPlan(ctx context.Context) []recoveryStep {
+	e.Mu.RLock()
+	statuses := make(map[string]NodeStatus, len(e.Status))
+	for k, v := range e.Status {
+		statuses[k] = v
+	}
+	outputs := make(map[string]map[string]interface{}, len(e.outputs))
+	for k, v := range e.outputs {
+		outputs[k] = v
+	}
+	e.Mu.RUnlock()
+
+	preds := e.predecessors()
+
+	// Routes taken by completed nodes, evaluated against their stored output
+	routes := make(map[string][]string)
+	for id, status := range statuses {
+		node, ok := e.Graph.Nodes[id]
+		if !ok || status != StatusCompleted || len(node.NextIDs) == 0 {
+			continue
+		}
+		routes[id] = e.routeFromOutput(ctx, node, outputs[id])
+	}
+
+	delivered := func(from, to string) bool {
+		if statuses[from] != StatusCompleted {
+			return false
+		}
+		for _, id := range routes[from] {
+			if id == to {
+				return true
+			}
+		}
+		return false
+	}
+
+	// A running parallel node re-delivers all its branches itself
+	handledByParallel := func(id string) bool {
+		for _, p := range preds[id] {
+			if e.Graph.Nodes[p].Type == NodeTypeParallel && statuses[p] == StatusRunning {
+				return true
+			}
+		}
+		return false
+	}
+
+	// A sequence re-runs its own steps; steps have no edges of their own
+	stepOf := make(map[string]bool)
+	for _, node := range e.Graph.Nodes {
+		if node.Type == NodeTypeSequence {
+			for _, stepID := range SequenceSteps(node) {
+				stepOf[stepID] = true
+			}
+		}
+	}
+
+	inputFor := func(id string) map[string]interface{} {
+		if id == e.Graph.StartNodeID {
+			return cloneMap(e.inputs)
+		}
+		var upstream []map[string]interface{}
+		for _, p := range preds[id] {
+			if !delivered(p, id) {
+				continue
+			}
+			// Loop-back delivery wins, mirroring deliverToDownstream
+			if isBackEdge(e.Graph.Nodes[p], id) {
+				return cloneMap(outputs[p])
+			}
+			upstream = append(upstream, outputs[p])
+		}
+		if len(upstream) == 0 {
+			return make(map[string]interface{})
+		}
+		return e.MergeStrategy.Merge(upstream)
+	}
+
+	var steps []recoveryStep
+
+	// 1. Nodes interrupted while running
+	for id, status := range statuses {
+		node, ok := e.Graph.Nodes[id]
+		if !ok || status != StatusRunning || handledByParallel(id) || stepOf[id] {
+			continue
+		}
+		step := recoveryStep{nodeID: id, input: inputFor(id)}
+		if node.Type == NodeTypeParallel {
+			step.reuse = make(map[string]map[string]interface{})
+			for _, branchID := range node.NextIDs {
+				if statuses[branchID] == StatusCompleted && outputs[branchID] != nil {
+					step.reuse[branchID] = outputs[branchID]
+				}
+			}
+		}
+		if node.Type == NodeTypeSequence {
+			// Only the completed prefix is reused; later steps depend on it
+			step.reuse = make(map[string]map[string]interface{})
+			for _, stepID := range SequenceSteps(node) {
+				if statuses[stepID] != StatusCompleted || outputs[stepID] == nil {
+					break
+				}
+				step.reuse[stepID] = outputs[stepID]
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	// 2. Nodes that were never reached although upstream already delivered
+	for id := range e.Graph.Nodes {
+		if status := statuses[id]; status != "" && status != StatusPending {
+			continue
+		}
+		if handledByParallel(id) {
+			continue
+		}
+
+		var received []map[string]interface{}
+		for _, p := range preds[id] {
+			if isBackEdge(e.Graph.Nodes[p], id) || !delivered(p, id) || handledByParallel(p) {
+				continue
+			}
+			received = append(received, outputs[p])
+		}
+		if len(received) == 0 {
+			continue
+		}
+
+		if len(received) >= e.inDegree[id] {
+			steps = append(steps, recoveryStep{nodeID: id, input: e.MergeStrategy.Merge(received)})
+			continue
+		}
+
+		// Partially satisfied join: the remaining upstream nodes are in the frontier
+		e.joinMu.Lock()
+		e.pendingInputs[id] = received
+		e.joinMu.Unlock()
+	}
+
+	sort.Slice(steps, func(i, j int) bool { return steps[i].nodeID < steps[j].nodeID })
+	return steps
+}
+
+// routeFromOutput re-evaluates the routing decision of a node from its stored output.
+func (e *Engine) routeFromOutput(ctx context.Context, node *Node, output map[string]interface{}) []string {
+	processor, err := e.NodeFactory.CreateNode(node, FactoryDeps{Session: e.Session})
+	if err != nil || processor == nil {
+		return node.NextIDs
+	}
+	router, ok := processor.(ConditionalRouter)
+	if !ok {
+		return node.NextIDs
+	}
+	nextIDs, err := router.GetNextNodes(ctx, output, node.NextIDs)
+	if err != nil {
+		log.Printf("[Engine] Failed to re-evaluate routing of %s during recovery: %v", node.ID, err)
+		return nil
+	}
+	return nextIDs
+}
+
+// predecessors returns the upstream node IDs of every node, including loop back-edges.
+func (e *Engine) predecessors() map[string][]string {
+	preds := make(map[string][]string)
+	for _, node := range e.Graph.Nodes {
+		for _, nextID := range node.NextIDs {
+			preds[nextID] = append(preds[nextID], node.ID)
+		}
+	}
+	for id := range preds {
+		sort.Strings(preds[id])
+	}
+	return preds
+}
+
+// isBackEdge reports whether from -> to is a loop's continue edge (see computeInDegrees).
+func isBackEdge(from *Node, to string) bool {
+	return from != nil && from.Type == NodeTypeLoop && len(from.NextIDs) > 0 && from.NextIDs[0] == to
+}
+
+func cloneMap(in map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEngine_Recover_RunningNode(t *testing.T) {
+	graph := &GraphDefinition{
+		ID:          "recover-linear",
+		StartNodeID: "start",
+		Nodes: map[string]*Node{
+			"start": {ID: "start", Type: "test", NextIDs: []string{"a"}},
+			"a":     {ID: "a", Type: "test", NextIDs: []string{"b"}},
+			"b":     {ID: "b", Type: "test"},
+		},
+	}
+
+	session := RestoreSession(&SessionEntity{
+		ID:           "restored",
+		Graph:        graph,
+		NodeStatuses: map[string]NodeStatus{"start": StatusCompleted, "a": StatusRunning},
+		NodeOutputs:  map[string]map[string]interface{}{"start": {"doc": "from start"}},
+	})
+	engine := NewEngine(session)
+
+	mu := sync.Mutex{}
+	executed := make(map[string]int)
+	var inputOfA map[string]interface{}
+	engine.NodeFactory = SimpleFuncNodeFactory(func(n *Node) (NodeProcessor, error) {
+		return &countingProcessor{onProcess: func(input map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			executed[n.ID]++
+			if n.ID == "a" {
+				inputOfA = input
+			}
+		}, output: map[string]interface{}{"val": n.ID}}, nil
+	})
+
+	session.Start(context.Background())
+	if err := engine.Recover(context.Background()); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	if executed["start"] != 0 {
+		t.Errorf("expected completed start node not to re-run, ran %d times", executed["start"])
+	}
+	if executed["a"] != 1 || executed["b"] != 1 {
+		t.Errorf("expected a and b to run once, got a:%d b:%d", executed["a"], executed["b"])
+	}
+	if inputOfA["doc"] != "from start" {
+		t.Errorf("expected a to receive stored upstream output, got %v", inputOfA)
+	}
+}
+
+func TestEngine_Recover_PartialJoin(t *testing.T) {
+	graph := &GraphDefinition{
+		ID:          "recover-join",
+		StartNodeID: "start",
+		Nodes: map[string]*Node{
+			"start":    {ID: "start", Type: "test", NextIDs: []string{"parallel"}},
+			"parallel": {ID: "parallel", Type: NodeTypeParallel, NextIDs: []string{"b1", "b2"}},
+			"b1":       {ID: "b1", Type: "test", NextIDs: []string{"join"}},
+			"b2":       {ID: "b2", Type: "test", NextIDs: []string{"join"}},
+			"join":     {ID: "join", Type: "test"},
+		},
+	}
+
+	session := RestoreSession(&SessionEntity{
+		ID:    "restored-join",
+		Graph: graph,
+		NodeStatuses: map[string]NodeStatus{
+			"start":    StatusCompleted,
+			"parallel": StatusRunning,
+			"b1":       StatusCompleted,
+			"b2":       StatusRunning,
+		},
+		NodeOutputs: map[string]map[string]interface{}{
+			"start": {"doc": "x"},
+			"b1":    {"source": "b1"},
+		},
+	})
+	engine := NewEngine(session)
+
+	mu := sync.Mutex{}
+	executed := make(map[string]int)
+	var joinInputs []map[string]interface{}
+	engine.NodeFactory = SimpleFuncNodeFactory(func(n *Node) (NodeProcessor, error) {
+		return &countingProcessor{onProcess: func(input map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			executed[n.ID]++
+			if n.ID == "join" {
+				joinInputs = append(joinInputs, input)
+			}
+		}, output: map[string]interface{}{"source": n.ID}}, nil
+	})
+
+	session.Start(context.Background())
+	if err := engine.Recover(context.Background()); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	if executed["b1"] != 0 {
+		t.Errorf("expected completed branch b1 not to re-run, ran %d times", executed["b1"])
+	}
+	if executed["b2"] != 1 {
+		t.Errorf("expected interrupted branch b2 to run once, ran %d times", executed["b2"])
+	}
+	if len(joinInputs) != 1 {
+		t.Fatalf("expected join to run exactly once, ran %d times", len(joinInputs))
+	}
+	if _, ok := joinInputs[0]["branch_1"]; !ok {
+		t.Errorf("expected join to receive both branches, got %v", joinInputs[0])
+	}
+	if engine.GetStatus("parallel") != StatusCompleted {
+		t.Errorf("expected parallel node to be completed, got %s", engine.GetStatus("parallel"))
+	}
+}
+
+func TestEngine_Recover_SuspendedNodeWaitsForReview(t *testing.T) {
+	graph := &GraphDefinition{
+		ID:          "recover-review",
+		StartNodeID: "start",
+		Nodes: map[string]*Node{
+			"start":  {ID: "start", Type: "test", NextIDs: []string{"review"}},
+			"review": {ID: "review", Type: "suspending_node", NextIDs: []string{"end"}},
+			"end":    {ID: "end", Type: "test"},
+		},
+	}
+
+	session := RestoreSession(&SessionEntity{
+		ID:           "restored-review",
+		Graph:        graph,
+		NodeStatuses: map[string]NodeStatus{"start": StatusCompleted, "review": StatusSuspended},
+		NodeOutputs:  map[string]map[string]interface{}{"start": {"doc": "x"}},
+	})
+	engine := NewEngine(session)
+	engine.NodeFactory = SimpleFuncNodeFactory(func(n *Node) (NodeProcessor, error) {
+		if n.Type == "suspending_node" {
+			return &suspendingProcessor{}, nil
+		}
+		return &MockProcessor{Output: map[string]interface{}{"val": n.ID}}, nil
+	})
+
+	ctx := context.Background()
+	session.Start(ctx)
+	if err := engine.Recover(ctx); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	if engine.GetStatus("end") != "" {
+		t.Errorf("expected end not to run before review, got %s", engine.GetStatus("end"))
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- engine.Wait(ctx) }()
+
+	select {
+	case <-done:
+		t.Fatal("Wait returned while a node is still suspended")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := engine.ResumeNode(ctx, "review", map[string]interface{}{"review_action": "approve"}); err != nil {
+		t.Fatalf("ResumeNode failed: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Wait returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after resume")
+	}
+	if engine.GetStatus("end") != StatusCompleted {
+		t.Errorf("expected end to be completed, got %s", engine.GetStatus("end"))
+	}
+}
+
+// countingProcessor reports each Process call; the factory itself is also
+// invoked during recovery to re-evaluate routing, so creation is not execution.
+type countingProcessor struct {
+	onProcess func(input map[string]interface{})
+	output    map[string]interface{}
+}
+
+func (p *countingProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- StreamEvent) (map[string]interface{}, error) {
+	p.onProcess(input)
+	return p.output, nil
+}
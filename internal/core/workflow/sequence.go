@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SequenceSteps returns the ordered child node IDs of a sequence node,
+// configured through its "steps" property.
+func SequenceSteps(node *Node) []string {
+	switch raw := node.Properties["steps"].(type) {
+	case []string:
+		return raw
+	case []interface{}:
+		steps := make([]string, 0, len(raw))
+		for _, v := range raw {
+			if id, ok := v.(string); ok {
+				steps = append(steps, id)
+			}
+		}
+		return steps
+	default:
+		return nil
+	}
+}
+
+// executeSequence runs a sequence node as one composite step and then delivers
+// the output of its last step downstream. Steps present in reuse are not
+// executed again (used when recovering an interrupted sequence).
+func (e *Engine) executeSequence(ctx context.Context, node *Node, input map[string]interface{}, reuse map[string]map[string]interface{}) error {
+	output, err := e.runSequence(ctx, node, input, reuse)
+	if err != nil {
+		e.emitError(node.ID, err)
+		e.updateStatus(node.ID, StatusFailed)
+		return err
+	}
+
+	e.recordOutput(node.ID, output)
+	e.updateStatus(node.ID, StatusCompleted)
+
+	e.deliverToDownstream(ctx, node.ID, output, node.NextIDs)
+	return nil
+}
+
+// runSequence executes the steps of a sequence node in order. Each step
+// receives the output of the previous one; the first step receives input.
+// Child steps never trigger their own downstream nodes.
+func (e *Engine) runSequence(ctx context.Context, node *Node, input map[string]interface{}, reuse map[string]map[string]interface{}) (map[string]interface{}, error) {
+	steps := SequenceSteps(node)
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("sequence node %s has no steps", node.ID)
+	}
+
+	current := input
+	for i, stepID := range steps {
+		if out, ok := reuse[stepID]; ok {
+			current = out
+			continue
+		}
+
+		e.emitSequenceProgress(node.ID, steps, i)
+
+		output, err := e.executeNodeWithoutDownstream(ctx, stepID, cloneMap(current))
+		if err != nil {
+			return nil, fmt.Errorf("sequence step %s failed: %w", stepID, err)
+		}
+		if output == nil {
+			return nil, fmt.Errorf("sequence step %s suspended; suspending nodes are not supported inside a sequence", stepID)
+		}
+		current = output
+	}
+
+	return current, nil
+}
+
+// emitSequenceProgress reports the composite status of a sequence node
+// before its step at index current runs.
+func (e *Engine) emitSequenceProgress(nodeID string, steps []string, current int) {
+	e.StreamChannel <- StreamEvent{
+		Type:      "node_state_change",
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Data: map[string]interface{}{
+			"status":          StatusRunning,
+			"current_step":    steps[current],
+			"steps_completed": current,
+			"steps_total":     len(steps),
+		},
+	}
+}
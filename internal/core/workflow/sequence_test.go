@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func sequenceGraph() *GraphDefinition {
+	return &GraphDefinition{
+		ID:          "sequence-graph",
+		StartNodeID: "start",
+		Nodes: map[string]*Node{
+			"start": {ID: "start", Type: "test", NextIDs: []string{"seq"}},
+			"seq": {ID: "seq", Type: NodeTypeSequence, NextIDs: []string{"end"}, Properties: map[string]interface{}{
+				"steps": []interface{}{"critique", "revise", "score"},
+			}},
+			"critique": {ID: "critique", Type: "test"},
+			"revise":   {ID: "revise", Type: "test"},
+			"score":    {ID: "score", Type: "test"},
+			"end":      {ID: "end", Type: "test"},
+		},
+	}
+}
+
+// trailProcessor appends its node ID to the "trail" input value, so the final
+// trail shows the order in which outputs were threaded.
+type trailProcessor struct {
+	id string
+}
+
+func (p *trailProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- StreamEvent) (map[string]interface{}, error) {
+	trail, _ := input["trail"].(string)
+	return map[string]interface{}{"trail": trail + "/" + p.id}, nil
+}
+
+func TestEngine_Sequence(t *testing.T) {
+	session := NewSession(sequenceGraph(), map[string]interface{}{"trail": ""})
+	engine := NewEngine(session)
+	engine.StreamChannel = make(chan StreamEvent, 100)
+
+	var endInput map[string]interface{}
+	engine.NodeFactory = SimpleFuncNodeFactory(func(n *Node) (NodeProcessor, error) {
+		if n.ID == "end" {
+			return &InputCapturingProcessor{OnProcess: func(input map[string]interface{}) { endInput = input }}, nil
+		}
+		return &trailProcessor{id: n.ID}, nil
+	})
+
+	if err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if endInput["trail"] != "/start/critique/revise/score" {
+		t.Errorf("expected steps to run in order, got trail %v", endInput["trail"])
+	}
+	if out, _ := engine.GetOutput("seq"); out["trail"] != "/start/critique/revise/score" {
+		t.Errorf("expected sequence output to be its last step output, got %v", out)
+	}
+	if engine.GetStatus("seq") != StatusCompleted {
+		t.Errorf("expected sequence to be completed, got %s", engine.GetStatus("seq"))
+	}
+
+	close(engine.StreamChannel)
+	var progress []string
+	for event := range engine.StreamChannel {
+		if event.Type == "node_state_change" && event.NodeID == "seq" {
+			if step, ok := event.Data["current_step"].(string); ok {
+				progress = append(progress, step)
+			}
+		}
+	}
+	if len(progress) != 3 || progress[0] != "critique" || progress[2] != "score" {
+		t.Errorf("expected composite progress for each step, got %v", progress)
+	}
+}
+
+func TestEngine_Sequence_StepFailureFailsSequence(t *testing.T) {
+	session := NewSession(sequenceGraph(), nil)
+	engine := NewEngine(session)
+	repo := &journalRepo{}
+	engine.SessionRepo = repo
+
+	mu := sync.Mutex{}
+	calls := make(map[string]int)
+	engine.NodeFactory = SimpleFuncNodeFactory(func(n *Node) (NodeProcessor, error) {
+		if n.ID == "revise" {
+			return &failingProcessor{}, nil
+		}
+		return &countingProcessor{onProcess: func(input map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[n.ID]++
+		}, output: map[string]interface{}{"val": n.ID}}, nil
+	})
+
+	// Downstream failures are reported through node status, not Run's error
+	_ = engine.Run(context.Background())
+	if engine.GetStatus("seq") != StatusFailed {
+		t.Errorf("expected sequence to be failed, got %s", engine.GetStatus("seq"))
+	}
+	if repo.statuses["seq"] != StatusFailed {
+		t.Errorf("expected the failed status to be persisted, got %s", repo.statuses["seq"])
+	}
+	var last NodeStatus
+	for len(engine.StreamChannel) > 0 {
+		if ev := <-engine.StreamChannel; ev.Type == "node_state_change" && ev.NodeID == "seq" {
+			last, _ = ev.Data["status"].(NodeStatus)
+		}
+	}
+	if last != StatusFailed {
+		t.Errorf("expected the client to see the sequence fail, got %s", last)
+	}
+	if calls["score"] != 0 || calls["end"] != 0 {
+		t.Errorf("expected no steps after the failure to run, got %v", calls)
+	}
+}
+
+func TestEngine_Recover_Sequence(t *testing.T) {
+	session := RestoreSession(&SessionEntity{
+		ID:    "restored-seq",
+		Graph: sequenceGraph(),
+		NodeStatuses: map[string]NodeStatus{
+			"start":    StatusCompleted,
+			"seq":      StatusRunning,
+			"critique": StatusCompleted,
+			"revise":   StatusRunning,
+		},
+		NodeOutputs: map[string]map[string]interface{}{
+			"start":    {"trail": "/start"},
+			"critique": {"trail": "/start/critique"},
+		},
+	})
+	engine := NewEngine(session)
+
+	mu := sync.Mutex{}
+	calls := make(map[string]int)
+	var endInput map[string]interface{}
+	engine.NodeFactory = SimpleFuncNodeFactory(func(n *Node) (NodeProcessor, error) {
+		return &countingProcessor{onProcess: func(input map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[n.ID]++
+			if n.ID == "end" {
+				endInput = input
+			}
+		}, output: map[string]interface{}{"trail": n.ID}}, nil
+	})
+
+	session.Start(context.Background())
+	if err := engine.Recover(context.Background()); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	if calls["critique"] != 0 {
+		t.Errorf("expected completed step not to re-run, ran %d times", calls["critique"])
+	}
+	if calls["revise"] != 1 || calls["score"] != 1 {
+		t.Errorf("expected remaining steps to run once, got %v", calls)
+	}
+	if endInput["trail"] != "score" {
+		t.Errorf("expected end to receive the last step output, got %v", endInput)
+	}
+}
+
+type failingProcessor struct{}
+
+func (p *failingProcessor) Process(ctx context.Context, input map[string]interface{}, stream chan<- StreamEvent) (map[string]interface{}, error) {
+	return nil, errors.New("step failed")
+}
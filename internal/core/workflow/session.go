@@ -31,7 +31,8 @@ type Session struct {
 	Outputs   map[string]interface{}
 	Error     error
 
-	NodeStatuses map[string]NodeStatus `json:"node_statuses"`
+	NodeStatuses map[string]NodeStatus             `json:"node_statuses"`
+	NodeOutputs  map[string]map[string]interface{} `json:"node_outputs,omitempty"` // Last output per node (restored on recovery)
 
 	ctx      context.Context
 	cancel   context.CancelFunc
@@ -40,7 +41,12 @@ type Session struct {
 	SignalChannels map[string]chan interface{} `json:"signal_channels,omitempty"`
 	ContextData    map[string]interface{}      `json:"context_data"` // Runtime context for Loop variables, etc.
 	FileRepo       SessionFileRepository       `json:"-"`            // Injected persistence
-	mu             sync.RWMutex
+
+	budgets    Budgets // Spend limits set at execute time
+	usage      Usage   // Model usage of this session
+	groupSpend Usage   // Usage of the group's other sessions when this one started
+
+	mu sync.RWMutex
 }
 
 func (s *Session) SetFileRepository(repo SessionFileRepository) {
@@ -109,6 +115,17 @@ func (s *Session) GetContext(key string) interface{} {
 	return s.ContextData[key]
 }
 
+// ContextSnapshot returns a shallow copy of the runtime context for persistence.
+func (s *Session) ContextSnapshot() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot := make(map[string]interface{}, len(s.ContextData))
+	for k, v := range s.ContextData {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
 func NewSession(graph *GraphDefinition, inputs map[string]interface{}) *Session {
 	return &Session{
 		ID:     uuid.New().String(),
@@ -118,12 +135,38 @@ func NewSession(graph *GraphDefinition, inputs map[string]interface{}) *Session
 	}
 }
 
+// RestoreSession rebuilds a session from its persisted state so that an Engine
+// can continue it after a restart. The session is returned in pending state;
+// callers are expected to Start it (and Pause it again if it was paused).
+func RestoreSession(entity *SessionEntity) *Session {
+	s := &Session{
+		ID:           entity.ID,
+		Graph:        entity.Graph,
+		Inputs:       entity.Inputs,
+		Status:       SessionPending,
+		NodeStatuses: entity.NodeStatuses,
+		NodeOutputs:  entity.NodeOutputs,
+		ContextData:  entity.ContextData,
+		budgets:      entity.Budgets,
+		usage:        entity.Usage,
+	}
+	if s.Inputs == nil {
+		s.Inputs = make(map[string]interface{})
+	}
+	if entity.StartedAt != nil {
+		s.StartTime = *entity.StartedAt
+	}
+	return s
+}
+
 func (s *Session) Start(parentCtx context.Context) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.Status = SessionRunning
-	s.StartTime = time.Now()
+	if s.StartTime.IsZero() {
+		s.StartTime = time.Now()
+	}
 	s.resumeCh = make(chan struct{})
 	close(s.resumeCh) // Initially not paused
 
@@ -158,13 +201,15 @@ func (s *Session) Fail(err error) {
 	}
 }
 
-func (s *Session) Pause() {
+// Pause pauses a running session and reports whether the session is paused.
+func (s *Session) Pause() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.Status == SessionRunning {
 		s.Status = SessionPaused
 		s.resumeCh = make(chan struct{}) // Create a new blocking channel
 	}
+	return s.Status == SessionPaused
 }
 
 func (s *Session) Resume() {
@@ -7,14 +7,20 @@ import (
 
 // SessionEntity represents the persistent state of a session.
 type SessionEntity struct {
-	ID           string                 `json:"session_uuid" db:"session_uuid"`
-	GroupID      string                 `json:"group_uuid" db:"group_uuid"`
-	WorkflowID   string                 `json:"workflow_uuid" db:"workflow_uuid"`
-	Status       SessionStatus          `json:"status"`
-	Proposal     map[string]interface{} `json:"proposal"`
-	NodeStatuses map[string]NodeStatus  `json:"node_statuses,omitempty"`
-	StartedAt    *time.Time             `json:"started_at"`
-	EndedAt      *time.Time             `json:"ended_at"`
+	ID           string                            `json:"session_uuid" db:"session_uuid"`
+	GroupID      string                            `json:"group_uuid" db:"group_uuid"`
+	WorkflowID   string                            `json:"workflow_uuid" db:"workflow_uuid"`
+	Status       SessionStatus                     `json:"status"`
+	Proposal     map[string]interface{}            `json:"proposal"`
+	NodeStatuses map[string]NodeStatus             `json:"node_statuses,omitempty"`
+	NodeOutputs  map[string]map[string]interface{} `json:"node_outputs,omitempty"`
+	ContextData  map[string]interface{}            `json:"context_data,omitempty"`
+	Budgets      Budgets                           `json:"budgets"`
+	Usage        Usage                             `json:"usage"` // Summed from the execution journal
+	Inputs       map[string]interface{}            `json:"-"`     // Original execute input, used for recovery
+	Graph        *GraphDefinition                  `json:"-"`     // Graph snapshot taken at execute time
+	StartedAt    *time.Time                        `json:"started_at"`
+	EndedAt      *time.Time                        `json:"ended_at"`
 }
 
 // SessionRepository defines the interface for session persistence.
@@ -23,4 +29,18 @@ type SessionRepository interface {
 	Get(ctx context.Context, id string) (*SessionEntity, error)
 	UpdateStatus(ctx context.Context, id string, status SessionStatus) error
 	UpdateNodeStatus(ctx context.Context, sessionID string, nodeID string, status NodeStatus) error
+	// UpdateNodeOutput stores the latest output of a completed node.
+	UpdateNodeOutput(ctx context.Context, sessionID string, nodeID string, output map[string]interface{}) error
+	// UpdateContextData replaces the persisted runtime context (loop counters, score history, ...).
+	UpdateContextData(ctx context.Context, sessionID string, data map[string]interface{}) error
+	// ListRecoverable returns sessions that were still running or paused, e.g. before a restart.
+	ListRecoverable(ctx context.Context) ([]*SessionEntity, error)
+	// AppendExecution adds a record to the session's execution journal.
+	AppendExecution(ctx context.Context, exec *NodeExecution) error
+	// ListExecutions returns the execution journal of a session in execution order.
+	ListExecutions(ctx context.Context, sessionID string) ([]*NodeExecution, error)
+	// UpdateBudgets replaces the spend limits of a session.
+	UpdateBudgets(ctx context.Context, sessionID string, budgets Budgets) error
+	// GroupUsage sums the journaled usage of all sessions of a group.
+	GroupUsage(ctx context.Context, groupID string) (Usage, error)
 }
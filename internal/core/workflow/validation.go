@@ -9,7 +9,7 @@ import (
 // 1. Start node exists
 // 2. All next_ids point to existing nodes
 // 3. No cycles
-// 4. All nodes are reachable from Start
+// 4. All nodes are reachable from Start (sequence steps through their sequence)
 func (g *GraphDefinition) Validate() error {
 	if g == nil {
 		return errors.New("graph definition is nil")
@@ -35,6 +35,10 @@ func (g *GraphDefinition) Validate() error {
 		}
 	}
 
+	if err := g.validateSequences(); err != nil {
+		return err
+	}
+
 	// 3. Traversal for Reachability
 	// We allow cycles, so we just use a visited map.
 	visited := make(map[string]bool)
@@ -55,6 +59,18 @@ func (g *GraphDefinition) detectCycle(nodeID string, visited map[string]bool) er
 	visited[nodeID] = true
 
 	node := g.Nodes[nodeID]
+
+	// Sequence steps are reached through their sequence node
+	if node.Type == NodeTypeSequence {
+		for _, stepID := range SequenceSteps(node) {
+			if !visited[stepID] {
+				if err := g.detectCycle(stepID, visited); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	for _, nextID := range node.NextIDs {
 		// If not visited, recurse
 		if !visited[nextID] {
@@ -68,3 +84,57 @@ func (g *GraphDefinition) detectCycle(nodeID string, visited map[string]bool) er
 
 	return nil
 }
+
+// validateSequences checks that sequence steps exist and are owned exclusively
+// by one sequence. Steps are executed by the sequence, so they must not be the
+// start node, be linked by next_ids, link to other nodes, fork or suspend.
+func (g *GraphDefinition) validateSequences() error {
+	linked := make(map[string]bool)
+	for _, node := range g.Nodes {
+		for _, nextID := range node.NextIDs {
+			linked[nextID] = true
+		}
+	}
+
+	owner := make(map[string]string)
+	for id, node := range g.Nodes {
+		if node.Type != NodeTypeSequence {
+			continue
+		}
+		steps := SequenceSteps(node)
+		if len(steps) == 0 {
+			return fmt.Errorf("sequence node %s has no steps", id)
+		}
+		for _, stepID := range steps {
+			step, ok := g.Nodes[stepID]
+			if !ok {
+				return fmt.Errorf("sequence node %s has non-existent step %s", id, stepID)
+			}
+			if prev, ok := owner[stepID]; ok {
+				return fmt.Errorf("node %s is a step of both %s and %s", stepID, prev, id)
+			}
+			owner[stepID] = id
+
+			switch {
+			case stepID == g.StartNodeID || stepID == id:
+				return fmt.Errorf("sequence node %s cannot contain %s as a step", id, stepID)
+			case linked[stepID] || len(step.NextIDs) > 0:
+				return fmt.Errorf("sequence step %s must not be linked through next_ids", stepID)
+			case step.Type == NodeTypeParallel || step.Type == NodeTypeHumanReview:
+				return fmt.Errorf("sequence step %s has unsupported type %s", stepID, step.Type)
+			}
+		}
+	}
+
+	// Nested sequences must not contain each other
+	for stepID := range owner {
+		seen := map[string]bool{stepID: true}
+		for parent, ok := owner[stepID]; ok; parent, ok = owner[parent] {
+			if seen[parent] {
+				return fmt.Errorf("sequence node %s contains itself", parent)
+			}
+			seen[parent] = true
+		}
+	}
+	return nil
+}
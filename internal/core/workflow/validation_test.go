@@ -71,6 +71,60 @@ func TestGraphDefinition_Validate(t *testing.T) {
 			},
 			wantErr: true, // Assuming we want to catch unreachable nodes
 		},
+		{
+			name: "Sequence Steps Reachable Through Sequence",
+			graph: &GraphDefinition{
+				ID:          "sequence",
+				StartNodeID: "start",
+				Nodes: map[string]*Node{
+					"start":    {ID: "start", Type: NodeTypeStart, NextIDs: []string{"seq"}},
+					"seq":      {ID: "seq", Type: NodeTypeSequence, NextIDs: []string{"end"}, Properties: map[string]interface{}{"steps": []interface{}{"critique", "revise"}}},
+					"critique": {ID: "critique", Type: NodeTypeAgent},
+					"revise":   {ID: "revise", Type: NodeTypeAgent},
+					"end":      {ID: "end", Type: NodeTypeEnd},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Sequence Step Linked Through NextIDs",
+			graph: &GraphDefinition{
+				ID:          "sequence_linked",
+				StartNodeID: "start",
+				Nodes: map[string]*Node{
+					"start":    {ID: "start", Type: NodeTypeStart, NextIDs: []string{"seq", "critique"}},
+					"seq":      {ID: "seq", Type: NodeTypeSequence, Properties: map[string]interface{}{"steps": []interface{}{"critique"}}},
+					"critique": {ID: "critique", Type: NodeTypeAgent},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sequence With Suspending Step",
+			graph: &GraphDefinition{
+				ID:          "sequence_review",
+				StartNodeID: "start",
+				Nodes: map[string]*Node{
+					"start":  {ID: "start", Type: NodeTypeStart, NextIDs: []string{"seq"}},
+					"seq":    {ID: "seq", Type: NodeTypeSequence, Properties: map[string]interface{}{"steps": []interface{}{"review"}}},
+					"review": {ID: "review", Type: NodeTypeHumanReview},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sequences Containing Each Other",
+			graph: &GraphDefinition{
+				ID:          "sequence_cycle",
+				StartNodeID: "start",
+				Nodes: map[string]*Node{
+					"start": {ID: "start", Type: NodeTypeStart, NextIDs: []string{"a"}},
+					"a":     {ID: "a", Type: NodeTypeSequence, Properties: map[string]interface{}{"steps": []interface{}{"b"}}},
+					"b":     {ID: "b", Type: NodeTypeSequence, Properties: map[string]interface{}{"steps": []interface{}{"a"}}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
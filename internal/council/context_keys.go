@@ -2,6 +2,8 @@
 // 此包依赖 internal/core/workflow (骨架层)，但反之不可。
 package council
 
+import "github.com/hrygo/council/internal/core/workflow"
+
 // CouncilContextKeys 定义 Council 工作流使用的所有上下文字段。
 // 这些字段名是业务概念，不应出现在骨架层代码中。
 var CouncilContextKeys = []string{
@@ -53,3 +55,28 @@ var EndInputKeys = []string{
 	"aggregated_outputs",
 	"agent_output",
 }
+
+// contextPriorities 定义上下文超出模型窗口时各字段的保留优先级（高者优先保留）。
+// 未列出的字段优先级为 0。
+var contextPriorities = map[string]int{
+	"optimization_objective": 4,
+	"agent_output":           3,
+	"proposal":               3,
+	"document_content":       2,
+	"combined_context":       1,
+	"aggregated_outputs":     0,
+}
+
+// aggregatedOutputsSeparator 分隔 aggregated_outputs 中各 Agent 的输出（见 MergeStrategy）。
+const aggregatedOutputsSeparator = "\n\n---\n\n"
+
+// promptSection 返回带有保留优先级的 PromptSection。
+// aggregated_outputs 超出窗口时优先丢弃最早的输出。
+func promptSection(key, label string) workflow.PromptSection {
+	section := workflow.PromptSection{Key: key, Label: label, Priority: contextPriorities[key]}
+	if key == "aggregated_outputs" {
+		section.KeepRecent = true
+		section.Separator = aggregatedOutputsSeparator
+	}
+	return section
+}
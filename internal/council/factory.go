@@ -7,7 +7,9 @@ import (
 	"github.com/hrygo/council/internal/core/memory"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/core/workflow/nodes"
+	"github.com/hrygo/council/internal/core/workflow/nodes/tools"
 	"github.com/hrygo/council/internal/infrastructure/llm"
+	"github.com/hrygo/council/internal/infrastructure/search"
 )
 
 type CouncilNodeFactory struct {
@@ -26,6 +28,16 @@ func NewCouncilNodeFactory(repo agent.Repository, registry *llm.Registry, memMan
 	}
 }
 
+// SetToolRegistry replaces the registry used to resolve tool nodes and agent tools.
+func (f *CouncilNodeFactory) SetToolRegistry(registry *tools.Registry) {
+	f.baseFactory.Tools = registry
+}
+
+// SetSearchClient sets the evidence source used by fact check nodes.
+func (f *CouncilNodeFactory) SetSearchClient(client search.SearchClient) {
+	f.baseFactory.SearchClient = client
+}
+
 func (f *CouncilNodeFactory) CreateNode(node *workflow.Node, deps workflow.FactoryDeps) (workflow.NodeProcessor, error) {
 	switch node.Type {
 	case workflow.NodeTypeStart:
@@ -38,7 +50,7 @@ func (f *CouncilNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 		// Map EndInputKeys to PromptSections
 		sections := []workflow.PromptSection{}
 		for _, key := range EndInputKeys {
-			sections = append(sections, workflow.PromptSection{Key: key, Label: key})
+			sections = append(sections, promptSection(key, key))
 		}
 
 		// We need parameters from node properties
@@ -52,15 +64,17 @@ func (f *CouncilNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 
 		// Get LLM Provider
 		var llmProvider llm.LLMProvider
+		var providerName string
 		var err error
 		if model != "" {
 			// Try to get provider by model name
 			llmProvider, err = f.Registry.GetProviderByModel(model)
+			providerName = f.Registry.ProviderNameForModel(model)
 		}
 
 		// Fallback to provider property or default deepseek
 		if llmProvider == nil || err != nil {
-			providerName, _ := node.Properties["provider"].(string)
+			providerName, _ = node.Properties["provider"].(string)
 			if providerName == "" {
 				providerName = "default"
 			}
@@ -70,13 +84,16 @@ func (f *CouncilNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve llm provider for end node: %w", err)
 		}
+		llmProvider = f.Registry.WithFallbacks(llmProvider, providerName, nil)
 
 		return &nodes.EndProcessor{
 			NodeID:         node.ID,
 			LLM:            llmProvider,
+			Provider:       f.Registry.ProviderName(providerName),
 			Model:          model,
 			Prompt:         prompt,
 			PromptSections: sections,
+			ContextWindow:  f.Registry.ContextWindow(model),
 			OutputKey:      "final_report", // Council-specific key
 		}, nil
 
@@ -115,11 +132,16 @@ func (f *CouncilNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 		// Construct generic PromptSections for Agent from Council context keys
 		// We use a fixed set of sections for all Council agents for now
 		sections := []workflow.PromptSection{
-			{Key: "document_content", Label: "document_content"},
-			{Key: "proposal", Label: "proposal"},
-			{Key: "combined_context", Label: "combined_context"},
-			{Key: "aggregated_outputs", Label: "previous_analyses"},
-			{Key: "optimization_objective", Label: "optimization_objective"},
+			promptSection("document_content", "document_content"),
+			promptSection("proposal", "proposal"),
+			promptSection("combined_context", "combined_context"),
+			promptSection("aggregated_outputs", "previous_analyses"),
+			promptSection("optimization_objective", "optimization_objective"),
+		}
+
+		outputSchema, err := f.baseFactory.ResolveOutputSchema(node)
+		if err != nil {
+			return nil, err
 		}
 
 		return &nodes.AgentProcessor{
@@ -131,11 +153,42 @@ func (f *CouncilNodeFactory) CreateNode(node *workflow.Node, deps workflow.Facto
 			PassthroughKeys: AgentPassthroughKeys,
 			PromptSections:  sections,
 			OutputKey:       "agent_output", // Council-specific key
-			// Tools: f.resolveTools(node) // TODO: Implement tool resolution
+			Tools:           f.baseFactory.ResolveNodeTools(node),
+			ToolRegistry:    f.baseFactory.Tools,
+			OutputSchema:    outputSchema,
 		}, nil
 
-	case workflow.NodeTypeParallel:
-		// Parallel logic is handled by Engine (structural), but we return nil/error
+	case workflow.NodeTypeLLM:
+		processor, err := f.baseFactory.CreateLLMNode(node, "agent_output") // Council-specific key
+		if err != nil {
+			return nil, err
+		}
+		processor.PassthroughKeys = AgentPassthroughKeys
+		return processor, nil
+
+	case workflow.NodeTypeTool:
+		processor, err := f.baseFactory.CreateToolNode(node, deps, "tool_output")
+		if err != nil {
+			return nil, err
+		}
+		processor.PassthroughKeys = AgentPassthroughKeys
+		return processor, nil
+
+	case workflow.NodeTypeVote:
+		processor := f.baseFactory.CreateVoteNode(node)
+		processor.PassthroughKeys = AgentPassthroughKeys
+		return processor, nil
+
+	case workflow.NodeTypeFactCheck:
+		processor, err := f.baseFactory.CreateFactCheckNode(node)
+		if err != nil {
+			return nil, err
+		}
+		processor.PassthroughKeys = AgentPassthroughKeys
+		return processor, nil
+
+	case workflow.NodeTypeParallel, workflow.NodeTypeSequence:
+		// Parallel and sequence logic is handled by Engine (structural), but we return nil/error
 		// so engine knows to handle it or we can return a Dummy processor if Engine requires one.
 		// Our generic engine handles Parallel explicitly.
 		return nil, nil // Or generic error "Handled by Engine"
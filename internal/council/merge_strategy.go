@@ -42,7 +42,7 @@ func (s *CouncilMergeStrategy) Merge(inputs []map[string]interface{}) map[string
 
 	// 将多个 agent_output 聚合为 aggregated_outputs
 	if len(agentOutputs) > 0 {
-		merged["aggregated_outputs"] = strings.Join(agentOutputs, "\n\n---\n\n")
+		merged["aggregated_outputs"] = strings.Join(agentOutputs, aggregatedOutputsSeparator)
 	}
 
 	return merged
@@ -14,6 +14,8 @@ type MockCache struct {
 	LTrimFunc  func(ctx context.Context, key string, start, stop int64) *redis.StatusCmd
 	ExpireFunc func(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
 	DelFunc    func(ctx context.Context, keys ...string) *redis.IntCmd
+	MGetFunc   func(ctx context.Context, keys ...string) *redis.SliceCmd
+	SetFunc    func(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 }
 
 func (m *MockCache) LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
@@ -52,3 +54,19 @@ func (m *MockCache) Del(ctx context.Context, keys ...string) *redis.IntCmd {
 	}
 	return redis.NewIntCmd(ctx)
 }
+
+func (m *MockCache) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	if m.MGetFunc != nil {
+		return m.MGetFunc(ctx, keys...)
+	}
+	res := redis.NewSliceCmd(ctx)
+	res.SetVal(make([]interface{}, len(keys)))
+	return res
+}
+
+func (m *MockCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, key, value, expiration)
+	}
+	return redis.NewStatusCmd(ctx)
+}
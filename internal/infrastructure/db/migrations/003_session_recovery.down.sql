@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sessions_status;
+ALTER TABLE sessions DROP COLUMN graph_definition;
+ALTER TABLE sessions DROP COLUMN inputs;
+ALTER TABLE sessions DROP COLUMN context_data;
+ALTER TABLE sessions DROP COLUMN node_outputs;
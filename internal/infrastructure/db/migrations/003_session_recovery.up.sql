@@ -0,0 +1,6 @@
+-- Durable session state so running/paused sessions survive a backend restart
+ALTER TABLE sessions ADD COLUMN node_outputs JSONB DEFAULT '{}';
+ALTER TABLE sessions ADD COLUMN context_data JSONB DEFAULT '{}';
+ALTER TABLE sessions ADD COLUMN inputs JSONB DEFAULT '{}';
+ALTER TABLE sessions ADD COLUMN graph_definition JSONB;
+CREATE INDEX idx_sessions_status ON sessions(status);
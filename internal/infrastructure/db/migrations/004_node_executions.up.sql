@@ -0,0 +1,19 @@
+-- Append-only execution journal: one row per node run
+CREATE TABLE node_executions (
+    execution_id BIGSERIAL PRIMARY KEY,
+    session_uuid UUID REFERENCES sessions(session_uuid) ON DELETE CASCADE,
+    node_id VARCHAR(64) NOT NULL,
+    node_type VARCHAR(32) NOT NULL,
+    iteration INT NOT NULL DEFAULT 1,
+    status VARCHAR(32) NOT NULL,
+    input JSONB DEFAULT '{}',
+    output JSONB,
+    error TEXT,
+    prompt_tokens INT DEFAULT 0,
+    completion_tokens INT DEFAULT 0,
+    total_tokens INT DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ NOT NULL,
+    duration_ms BIGINT DEFAULT 0
+);
+CREATE INDEX idx_node_executions_session ON node_executions(session_uuid, execution_id);
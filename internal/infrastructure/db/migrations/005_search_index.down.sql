@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_session_files_content_fts;
+DROP INDEX IF EXISTS idx_memories_content_fts;
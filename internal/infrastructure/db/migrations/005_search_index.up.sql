@@ -0,0 +1,3 @@
+-- Full-text indexes for the local corpus search provider
+CREATE INDEX IF NOT EXISTS idx_memories_content_fts ON memories USING GIN (to_tsvector('simple', content));
+CREATE INDEX IF NOT EXISTS idx_session_files_content_fts ON session_files USING GIN (to_tsvector('simple', content));
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
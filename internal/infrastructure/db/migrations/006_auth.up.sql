@@ -0,0 +1,42 @@
+-- Users, API keys, per-group roles and the audit trail
+CREATE TABLE users (
+    user_uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    username VARCHAR(64) NOT NULL UNIQUE,
+    display_name VARCHAR(128),
+    password_hash VARCHAR(128),
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE api_keys (
+    key_uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid UUID NOT NULL REFERENCES users(user_uuid) ON DELETE CASCADE,
+    name VARCHAR(128) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+CREATE INDEX idx_api_keys_user ON api_keys(user_uuid);
+
+CREATE TABLE group_members (
+    group_uuid UUID REFERENCES groups(group_uuid) ON DELETE CASCADE,
+    user_uuid UUID REFERENCES users(user_uuid) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL, -- owner, editor, reviewer, viewer
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (group_uuid, user_uuid)
+);
+CREATE INDEX idx_group_members_user ON group_members(user_uuid);
+
+CREATE TABLE audit_logs (
+    audit_id BIGSERIAL PRIMARY KEY,
+    user_uuid UUID REFERENCES users(user_uuid) ON DELETE SET NULL,
+    username VARCHAR(64) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    group_uuid UUID,
+    session_uuid UUID,
+    details JSONB DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+CREATE INDEX idx_audit_logs_session ON audit_logs(session_uuid, audit_id);
@@ -0,0 +1,8 @@
+ALTER TABLE memories DROP COLUMN IF EXISTS tenant_uuid;
+ALTER TABLE sessions DROP COLUMN IF EXISTS tenant_uuid;
+ALTER TABLE workflow_templates DROP COLUMN IF EXISTS tenant_uuid;
+ALTER TABLE workflows DROP COLUMN IF EXISTS tenant_uuid;
+ALTER TABLE agents DROP COLUMN IF EXISTS tenant_uuid;
+ALTER TABLE groups DROP COLUMN IF EXISTS tenant_uuid;
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_uuid;
+DROP TABLE IF EXISTS tenants;
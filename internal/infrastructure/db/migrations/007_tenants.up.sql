@@ -0,0 +1,43 @@
+-- Tenants (workspaces): every team-owned table is scoped by tenant_uuid.
+-- Existing rows move to the default tenant.
+CREATE TABLE tenants (
+    tenant_uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(128) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+INSERT INTO tenants (tenant_uuid, name) VALUES ('00000000-0000-0000-0000-000000000001', 'Default');
+
+ALTER TABLE users ADD COLUMN tenant_uuid UUID REFERENCES tenants(tenant_uuid) ON DELETE CASCADE;
+UPDATE users SET tenant_uuid = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE users ALTER COLUMN tenant_uuid SET NOT NULL;
+CREATE INDEX idx_users_tenant ON users(tenant_uuid);
+
+ALTER TABLE groups ADD COLUMN tenant_uuid UUID REFERENCES tenants(tenant_uuid) ON DELETE CASCADE;
+UPDATE groups SET tenant_uuid = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE groups ALTER COLUMN tenant_uuid SET NOT NULL;
+CREATE INDEX idx_groups_tenant ON groups(tenant_uuid);
+
+ALTER TABLE agents ADD COLUMN tenant_uuid UUID REFERENCES tenants(tenant_uuid) ON DELETE CASCADE;
+UPDATE agents SET tenant_uuid = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE agents ALTER COLUMN tenant_uuid SET NOT NULL;
+CREATE INDEX idx_agents_tenant ON agents(tenant_uuid);
+
+ALTER TABLE workflows ADD COLUMN tenant_uuid UUID REFERENCES tenants(tenant_uuid) ON DELETE CASCADE;
+UPDATE workflows SET tenant_uuid = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE workflows ALTER COLUMN tenant_uuid SET NOT NULL;
+CREATE INDEX idx_workflows_tenant ON workflows(tenant_uuid);
+
+ALTER TABLE workflow_templates ADD COLUMN tenant_uuid UUID REFERENCES tenants(tenant_uuid) ON DELETE CASCADE;
+UPDATE workflow_templates SET tenant_uuid = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE workflow_templates ALTER COLUMN tenant_uuid SET NOT NULL;
+CREATE INDEX idx_workflow_templates_tenant ON workflow_templates(tenant_uuid);
+
+ALTER TABLE sessions ADD COLUMN tenant_uuid UUID REFERENCES tenants(tenant_uuid) ON DELETE CASCADE;
+UPDATE sessions SET tenant_uuid = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE sessions ALTER COLUMN tenant_uuid SET NOT NULL;
+CREATE INDEX idx_sessions_tenant ON sessions(tenant_uuid);
+
+ALTER TABLE memories ADD COLUMN tenant_uuid UUID REFERENCES tenants(tenant_uuid) ON DELETE CASCADE;
+UPDATE memories SET tenant_uuid = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE memories ALTER COLUMN tenant_uuid SET NOT NULL;
+CREATE INDEX idx_memories_tenant ON memories(tenant_uuid);
@@ -0,0 +1,2 @@
+ALTER TABLE node_executions DROP COLUMN IF EXISTS cost_usd;
+ALTER TABLE sessions DROP COLUMN IF EXISTS budgets;
@@ -0,0 +1,3 @@
+-- Spend limits set at execute time and the priced usage of each node run
+ALTER TABLE sessions ADD COLUMN budgets JSONB DEFAULT '{}';
+ALTER TABLE node_executions ADD COLUMN cost_usd NUMERIC(12, 6) DEFAULT 0;
@@ -0,0 +1,20 @@
+-- One row per LLM call. Group and workflow are copied from the session so
+-- that spend reports survive session deletion.
+CREATE TABLE cost_ledger (
+    entry_id BIGSERIAL PRIMARY KEY,
+    tenant_uuid UUID NOT NULL REFERENCES tenants(tenant_uuid) ON DELETE CASCADE,
+    session_uuid UUID REFERENCES sessions(session_uuid) ON DELETE SET NULL,
+    group_uuid UUID,
+    workflow_uuid UUID,
+    node_id VARCHAR(64) NOT NULL,
+    agent_uuid VARCHAR(64),
+    provider VARCHAR(32),
+    model VARCHAR(64),
+    prompt_tokens INT DEFAULT 0,
+    completion_tokens INT DEFAULT 0,
+    cost_usd NUMERIC(12, 6) DEFAULT 0,
+    latency_ms BIGINT DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_cost_ledger_tenant_created ON cost_ledger(tenant_uuid, created_at);
+CREATE INDEX idx_cost_ledger_group_created ON cost_ledger(group_uuid, created_at);
@@ -0,0 +1,3 @@
+-- Context window of each model, in tokens (prompt and reply together).
+-- NULL falls back to the default of the model's family.
+ALTER TABLE llm_models ADD COLUMN IF NOT EXISTS context_window INTEGER;
@@ -53,6 +53,94 @@ func TestMigrate(t *testing.T) {
 		WithArgs(migrationName2).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
+	// 8. Check, apply and record 003_session_recovery
+	migrationName3 := "003_session_recovery.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName3).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("ALTER", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName3).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// 9. Check, apply and record 004_node_executions
+	migrationName4 := "004_node_executions.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName4).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("CREATE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName4).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// 10. Check, apply and record 005_search_index
+	migrationName5 := "005_search_index.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName5).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("CREATE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName5).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// 11. Check, apply and record 006_auth
+	migrationName6 := "006_auth.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName6).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("CREATE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName6).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// 12. Check, apply and record 007_tenants
+	migrationName7 := "007_tenants.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName7).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("CREATE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName7).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// 13. Check, apply and record 008_budgets
+	migrationName8 := "008_budgets.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName8).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("ALTER", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName8).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// 14. Check, apply and record 009_cost_ledger
+	migrationName9 := "009_cost_ledger.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName9).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("CREATE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName9).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// 15. Check, apply and record 010_llm_model_context
+	migrationName10 := "010_llm_model_context.up.sql"
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM schema_migrations WHERE version=\\$1\\)").
+		WithArgs(migrationName10).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("(?s).*").
+		WillReturnResult(pgxmock.NewResult("ALTER", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migrationName10).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
 	err = Migrate(context.Background(), mock)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
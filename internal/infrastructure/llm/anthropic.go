@@ -0,0 +1,355 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	anthropicBaseURL   = "https://api.anthropic.com"
+	anthropicVersion   = "2023-06-01"
+	anthropicMaxTokens = 4096 // max_tokens is required by the Messages API
+)
+
+// AnthropicClient talks to the Anthropic Messages API.
+// https://docs.anthropic.com/en/api/messages
+type AnthropicClient struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewAnthropicClient returns a client of the Messages API at baseURL, or at
+// api.anthropic.com when it is empty.
+func NewAnthropicClient(apiKey, baseURL string) *AnthropicClient {
+	if baseURL == "" {
+		baseURL = anthropicBaseURL
+	}
+	return &AnthropicClient{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  http.DefaultClient,
+	}
+}
+
+// Ensure AnthropicClient implements LLMProvider interface
+var _ LLMProvider = (*AnthropicClient)(nil)
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float32            `json:"temperature,omitempty"`
+	TopP        float32            `json:"top_p,omitempty"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+// anthropicBlock is a content block: text, tool_use or tool_result.
+type anthropicBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	InputSchema interface{} `json:"input_schema"`
+}
+
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+func (u anthropicUsage) usage() Usage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return Usage{PromptTokens: prompt, CompletionTokens: u.OutputTokens, TotalTokens: prompt + u.OutputTokens}
+}
+
+type anthropicResponse struct {
+	Content []anthropicBlock `json:"content"`
+	Usage   anthropicUsage   `json:"usage"`
+}
+
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func (c *AnthropicClient) Generate(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	body, err := c.post(ctx, req, false)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic generate error: %w", err)
+	}
+	defer body.Close()
+
+	var resp anthropicResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("anthropic generate error: %w", err)
+	}
+
+	result := &CompletionResponse{Usage: resp.Usage.usage()}
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			result.Content += block.Text
+		case "tool_use":
+			result.ToolCalls = append(result.ToolCalls, ToolCall{
+				Index:    len(result.ToolCalls),
+				ID:       block.ID,
+				Type:     "function",
+				Function: FunctionCall{Name: block.Name, Arguments: string(block.Input)},
+			})
+		}
+	}
+	return result, nil
+}
+
+// anthropicEvent is a server-sent event of a streamed message.
+type anthropicEvent struct {
+	Type    string `json:"type"`
+	Index   int    `json:"index"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	ContentBlock anthropicBlock `json:"content_block"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Usage anthropicUsage  `json:"usage"`
+	Error *anthropicError `json:"error"`
+}
+
+func (c *AnthropicClient) Stream(ctx context.Context, req *CompletionRequest) (<-chan CompletionChunk, <-chan error) {
+	outputChan := make(chan CompletionChunk)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(outputChan)
+		defer close(errChan)
+
+		body, err := c.post(ctx, req, true)
+		if err != nil {
+			errChan <- fmt.Errorf("stream creation failed: %w", err)
+			return
+		}
+		defer body.Close()
+
+		send := func(chunk CompletionChunk) bool {
+			select {
+			case outputChan <- chunk:
+				return true
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+				return false
+			}
+		}
+
+		var usage anthropicUsage
+		toolIndex := make(map[int]int) // Content block index -> tool call index
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue // event names are repeated in the data's type
+			}
+			var event anthropicEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+				errChan <- fmt.Errorf("stream error: %w", err)
+				return
+			}
+
+			switch event.Type {
+			case "message_start":
+				usage = event.Message.Usage
+			case "content_block_start":
+				if event.ContentBlock.Type != "tool_use" {
+					continue
+				}
+				toolIndex[event.Index] = len(toolIndex)
+				if !send(CompletionChunk{ToolCalls: []ToolCall{{
+					Index:    toolIndex[event.Index],
+					ID:       event.ContentBlock.ID,
+					Type:     "function",
+					Function: FunctionCall{Name: event.ContentBlock.Name},
+				}}}) {
+					return
+				}
+			case "content_block_delta":
+				var chunk CompletionChunk
+				switch event.Delta.Type {
+				case "text_delta":
+					chunk.Content = event.Delta.Text
+				case "input_json_delta":
+					chunk.ToolCalls = []ToolCall{{
+						Index:    toolIndex[event.Index],
+						Function: FunctionCall{Arguments: event.Delta.PartialJSON},
+					}}
+				default:
+					continue
+				}
+				if !send(chunk) {
+					return
+				}
+			case "message_delta":
+				// Output tokens are cumulative
+				usage.OutputTokens = event.Usage.OutputTokens
+			case "message_stop":
+				total := usage.usage()
+				send(CompletionChunk{Usage: &total})
+				return
+			case "error":
+				errChan <- fmt.Errorf("stream error: %w", event.Error.statusError(0))
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errChan <- fmt.Errorf("stream error: %w", err)
+			return
+		}
+		errChan <- fmt.Errorf("stream error: %w", io.ErrUnexpectedEOF)
+	}()
+
+	return outputChan, errChan
+}
+
+// post sends req to the Messages API and returns the response body.
+func (c *AnthropicClient) post(ctx context.Context, req *CompletionRequest, stream bool) (io.ReadCloser, error) {
+	payload, err := json.Marshal(c.buildRequest(req, stream))
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		var body struct {
+			Error *anthropicError `json:"error"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(raw))}
+		if json.Unmarshal(raw, &body) == nil && body.Error != nil {
+			statusErr = body.Error.statusError(resp.StatusCode)
+		}
+		statusErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, statusErr
+	}
+	return resp.Body, nil
+}
+
+// buildRequest maps a CompletionRequest to the Messages API: system messages
+// become the top-level system prompt, tool calls become tool_use blocks and
+// tool messages tool_result blocks of a user turn. Consecutive messages of
+// the same role are merged, as the API expects alternating turns. A response
+// format is requested through the system prompt.
+func (c *AnthropicClient) buildRequest(req *CompletionRequest, stream bool) *anthropicRequest {
+	out := &anthropicRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: min(req.Temperature, 1), // The API accepts 0-1
+		TopP:        req.TopP,
+		Stream:      stream,
+	}
+	if out.MaxTokens <= 0 {
+		out.MaxTokens = anthropicMaxTokens
+	}
+
+	var system []string
+	for _, msg := range formatMessages(req) {
+		role := msg.Role
+		var blocks []anthropicBlock
+		switch msg.Role {
+		case "system":
+			system = append(system, msg.Content)
+			continue
+		case "tool":
+			role = "user"
+			blocks = append(blocks, anthropicBlock{Type: "tool_result", ToolUseID: msg.ToolCallID, Content: msg.Content})
+		default:
+			if msg.Content != "" {
+				blocks = append(blocks, anthropicBlock{Type: "text", Text: msg.Content})
+			}
+			for _, tc := range msg.ToolCalls {
+				input := json.RawMessage(tc.Function.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, anthropicBlock{Type: "tool_use", ID: tc.ID, Name: tc.Function.Name, Input: input})
+			}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		if n := len(out.Messages); n > 0 && out.Messages[n-1].Role == role {
+			out.Messages[n-1].Content = append(out.Messages[n-1].Content, blocks...)
+		} else {
+			out.Messages = append(out.Messages, anthropicMessage{Role: role, Content: blocks})
+		}
+	}
+	out.System = strings.Join(system, "\n\n")
+
+	for _, t := range req.Tools {
+		schema := t.Function.Parameters
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		out.Tools = append(out.Tools, anthropicTool{Name: t.Function.Name, Description: t.Function.Description, InputSchema: schema})
+	}
+	return out
+}
+
+// statusError converts an API error to a StatusError, so that fallback
+// chains can tell rate limits and overloads from invalid requests. Errors
+// sent inside a stream carry no HTTP status; it is derived from the type.
+func (e *anthropicError) statusError(status int) *StatusError {
+	if e == nil {
+		return &StatusError{StatusCode: status, Message: "unknown error"}
+	}
+	if status == 0 {
+		switch e.Type {
+		case "overloaded_error":
+			status = 529
+		case "rate_limit_error":
+			status = http.StatusTooManyRequests
+		case "invalid_request_error":
+			status = http.StatusBadRequest
+		default:
+			status = http.StatusInternalServerError
+		}
+	}
+	return &StatusError{StatusCode: status, Message: fmt.Sprintf("%s: %s", e.Type, e.Message)}
+}
@@ -0,0 +1,180 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnthropicClient_Generate(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "sk-ant" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("Unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = fmt.Fprint(w, `{"content": [
+			{"type": "text", "text": "Let me check."},
+			{"type": "tool_use", "id": "toolu_2", "name": "web_search", "input": {"query": "Go 1.24"}}
+		], "usage": {"input_tokens": 120, "output_tokens": 30, "cache_read_input_tokens": 80}}`)
+	}))
+	defer server.Close()
+
+	resp, err := NewAnthropicClient("sk-ant", server.URL).Generate(context.Background(), &CompletionRequest{
+		Model:       "claude-sonnet-4-5",
+		Temperature: 1.5,
+		Messages: []Message{
+			{Role: "system", Content: "You are the adjudicator."},
+			{Role: "user", Content: "When was Go 1.24 released?"},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "toolu_1", Type: "function", Function: FunctionCall{Name: "web_search", Arguments: `{"query":"Go"}`}}}},
+			{Role: "tool", ToolCallID: "toolu_1", Content: "Go 1.24 was released in February 2025."},
+			{Role: "user", Content: "Be precise."},
+		},
+		Tools: []Tool{{Type: "function", Function: ToolFunction{Name: "web_search", Parameters: map[string]interface{}{"type": "object"}}}},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if got["system"] != "You are the adjudicator." || got["max_tokens"] != float64(anthropicMaxTokens) || got["temperature"] != float64(1) {
+		t.Errorf("Expected a top-level system prompt, default max_tokens and a clamped temperature, got %v", got)
+	}
+	messages, _ := got["messages"].([]interface{})
+	if len(messages) != 3 {
+		t.Fatalf("Expected user, assistant and a merged user turn, got %v", messages)
+	}
+	toolUse := messages[1].(map[string]interface{})["content"].([]interface{})[0].(map[string]interface{})
+	if toolUse["type"] != "tool_use" || toolUse["id"] != "toolu_1" || toolUse["input"].(map[string]interface{})["query"] != "Go" {
+		t.Errorf("Expected the assistant's tool call as a tool_use block, got %v", toolUse)
+	}
+	results := messages[2].(map[string]interface{})["content"].([]interface{})
+	if len(results) != 2 || results[0].(map[string]interface{})["tool_use_id"] != "toolu_1" {
+		t.Errorf("Expected the tool result and the next question in one user turn, got %v", results)
+	}
+	tools, _ := got["tools"].([]interface{})
+	if len(tools) != 1 || tools[0].(map[string]interface{})["input_schema"] == nil {
+		t.Errorf("Expected tools with an input_schema, got %v", got["tools"])
+	}
+
+	if resp.Content != "Let me check." || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "toolu_2" ||
+		resp.ToolCalls[0].Function.Arguments != `{"query": "Go 1.24"}` {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if resp.Usage.PromptTokens != 200 || resp.Usage.CompletionTokens != 30 || resp.Usage.TotalTokens != 230 {
+		t.Errorf("Expected cached input to count as prompt tokens, got %+v", resp.Usage)
+	}
+}
+
+func TestAnthropicClient_Stream(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"usage":{"input_tokens":50,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"ping"}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" world"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"web_search","input":{}}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"query\":"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":" \"Go\"}"}}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":25}}`,
+		`{"type":"message_stop"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["stream"] != true {
+			t.Errorf("Expected a streamed request, got %v", req)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			var e struct{ Type string }
+			_ = json.Unmarshal([]byte(event), &e)
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, event)
+		}
+	}))
+	defer server.Close()
+
+	chunks, errs := NewAnthropicClient("sk-ant", server.URL).Stream(context.Background(), &CompletionRequest{
+		Model:    "claude-sonnet-4-5",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+	var content strings.Builder
+	var call ToolCall
+	var usage *Usage
+	for chunk := range chunks {
+		content.WriteString(chunk.Content)
+		for _, tc := range chunk.ToolCalls {
+			if tc.Index != 0 {
+				t.Errorf("Expected the first tool call at index 0, got %d", tc.Index)
+			}
+			if tc.ID != "" {
+				call.ID, call.Function.Name = tc.ID, tc.Function.Name
+			}
+			call.Function.Arguments += tc.Function.Arguments
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	if content.String() != "Hello world" {
+		t.Errorf("Expected the text deltas, got %q", content.String())
+	}
+	if call.ID != "toolu_1" || call.Function.Name != "web_search" || call.Function.Arguments != `{"query": "Go"}` {
+		t.Errorf("Expected the tool call to be assembled from its deltas, got %+v", call)
+	}
+	if usage == nil || usage.PromptTokens != 50 || usage.CompletionTokens != 25 {
+		t.Errorf("Expected the usage of the whole message, got %+v", usage)
+	}
+}
+
+func TestAnthropicClient_Errors(t *testing.T) {
+	t.Run("HTTPStatus", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = fmt.Fprint(w, `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`)
+		}))
+		defer server.Close()
+
+		_, err := NewAnthropicClient("sk-ant", server.URL).Generate(context.Background(), &CompletionRequest{})
+		if HTTPStatus(err) != http.StatusTooManyRequests || !IsRetryable(err) || !strings.Contains(err.Error(), "slow down") {
+			t.Errorf("Expected a retryable rate limit error, got %v", err)
+		}
+	})
+
+	t.Run("InStream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hel\"}}\n\n")
+			_, _ = fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+		}))
+		defer server.Close()
+
+		chunks, errs := NewAnthropicClient("sk-ant", server.URL).Stream(context.Background(), &CompletionRequest{})
+		for range chunks {
+		}
+		if err := <-errs; HTTPStatus(err) != 529 || !IsRetryable(err) {
+			t.Errorf("Expected a retryable overload error, got %v", err)
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, "event: ping\ndata: {\"type\":\"ping\"}\n\n")
+		}))
+		defer server.Close()
+
+		chunks, errs := NewAnthropicClient("sk-ant", server.URL).Stream(context.Background(), &CompletionRequest{})
+		for range chunks {
+		}
+		if err := <-errs; !IsRetryable(err) {
+			t.Errorf("Expected a stream without message_stop to fail as retryable, got %v", err)
+		}
+	})
+}
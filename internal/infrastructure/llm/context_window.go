@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"strings"
+)
+
+// DefaultContextWindow is assumed for models of unknown size.
+const DefaultContextWindow = 32768
+
+// contextWindowDefaults are the context windows of model families, matched
+// by prefix of the lowercased model name without its organisation ("Qwen/").
+// More specific prefixes come first.
+var contextWindowDefaults = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4o", 128000},
+	{"gpt-4.1", 1047576},
+	{"gpt-4.5", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4", 8192},
+	{"gpt-5", 400000},
+	{"gpt-3.5", 16385},
+	{"o1", 200000},
+	{"o3", 200000},
+	{"o4", 200000},
+	{"claude", 200000},
+	{"gemini-1.5-pro", 2097152},
+	{"gemini", 1048576},
+	{"deepseek", 65536},
+	{"qwen-turbo", 1000000},
+	{"qwen-plus", 131072},
+	{"qwen", 32768},
+	{"glm-4", 131072},
+}
+
+// SetContextWindows replaces the configured context windows by model name,
+// as stored in the llm_models table. Models without an entry fall back to
+// their family's default.
+func (r *Registry) SetContextWindows(windows map[string]int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.windows = windows
+}
+
+// ContextWindow returns the number of tokens a model accepts, prompt and
+// reply together.
+func (r *Registry) ContextWindow(model string) int {
+	r.mu.RLock()
+	tokens, ok := r.windows[model]
+	r.mu.RUnlock()
+	if ok && tokens > 0 {
+		return tokens
+	}
+	return ContextWindow(model)
+}
+
+// ContextWindow returns the default context window of a model's family.
+func ContextWindow(model string) int {
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, d := range contextWindowDefaults {
+		if strings.HasPrefix(name, d.prefix) {
+			return d.tokens
+		}
+	}
+	return DefaultContextWindow
+}
@@ -2,9 +2,6 @@ package llm
 
 import (
 	"context"
-	"fmt"
-
-	openai "github.com/sashabaranov/go-openai"
 )
 
 // DashScopeClient wraps OpenAIClient to handle DashScope specific logic.
@@ -18,6 +15,7 @@ func NewDashScopeClient(apiKey string) *DashScopeClient {
 	// https://help.aliyun.com/zh/model-studio/developer-reference/use-openai-python-sdk
 	baseURL := "https://dashscope.aliyuncs.com/compatible-mode/v1"
 	client := NewOpenAICompatibleClient(apiKey, baseURL)
+	client.jsonMode = jsonModeObject // No json_schema support
 	return &DashScopeClient{
 		OpenAIClient: client,
 	}
@@ -28,24 +26,14 @@ var _ LLMProvider = (*DashScopeClient)(nil)
 var _ Embedder = (*DashScopeClient)(nil)
 
 func (c *DashScopeClient) Embed(ctx context.Context, model string, text string) ([]float32, error) {
+	return firstEmbedding(c.EmbedBatch(ctx, model, []string{text}))
+}
+
+// EmbedBatch sends up to 10 texts per request, the limit of DashScope's
+// text-embedding-v3 (earlier models accept 25).
+func (c *DashScopeClient) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
 	if model == "" {
 		model = "text-embedding-v1" // Common DashScope embedding model
 	}
-
-	resp, err := c.client.CreateEmbeddings(
-		ctx,
-		openai.EmbeddingRequest{
-			Input: []string{text},
-			Model: openai.EmbeddingModel(model),
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("dashscope embed error: %w", err)
-	}
-
-	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("no embeddings returned")
-	}
-
-	return resp.Data[0].Embedding, nil
+	return c.embedBatch(ctx, "dashscope", model, texts, 10)
 }
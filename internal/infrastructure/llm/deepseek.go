@@ -16,6 +16,7 @@ func NewDeepSeekClient(apiKey string) *DeepSeekClient {
 	// https://api-docs.deepseek.com/
 	baseURL := "https://api.deepseek.com"
 	client := NewOpenAICompatibleClient(apiKey, baseURL)
+	client.jsonMode = jsonModeObject // No json_schema support
 	return &DeepSeekClient{
 		OpenAIClient: client,
 	}
@@ -30,3 +31,7 @@ var _ Embedder = (*DeepSeekClient)(nil)
 func (c *DeepSeekClient) Embed(ctx context.Context, model string, text string) ([]float32, error) {
 	return nil, fmt.Errorf("deepseek provider does not support embeddings")
 }
+
+func (c *DeepSeekClient) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	return nil, fmt.Errorf("deepseek provider does not support embeddings")
+}
@@ -0,0 +1,204 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hrygo/council/internal/infrastructure/cache"
+)
+
+const (
+	// DefaultEmbeddingCacheSize is the number of embeddings kept in process.
+	DefaultEmbeddingCacheSize = 10000
+	// DefaultEmbeddingCacheTTL is how long the shared cache keeps an embedding.
+	DefaultEmbeddingCacheTTL = 30 * 24 * time.Hour
+)
+
+// CachedEmbedder serves embeddings from a cache keyed by provider, model and
+// a hash of the text, so that the same content is embedded once. Misses of a
+// batch are embedded together in one EmbedBatch call.
+//
+// Embeddings are looked up in process first, then in the shared cache. The
+// shared cache is best effort: when it fails, the in-process cache and the
+// embedder still answer.
+type CachedEmbedder struct {
+	Embedder
+	Name  string        // Provider; embeddings of different providers differ
+	Cache cache.Cache   // Shared cache (Redis); nil keeps embeddings in process only
+	TTL   time.Duration // Expiry in the shared cache; 0 keeps them forever
+
+	local *embeddingLRU
+}
+
+// NewCachedEmbedder caches the embeddings of the embedder registered as name,
+// keeping size of them in process.
+func NewCachedEmbedder(embedder Embedder, name string, c cache.Cache, size int) *CachedEmbedder {
+	if size <= 0 {
+		size = DefaultEmbeddingCacheSize
+	}
+	return &CachedEmbedder{
+		Embedder: embedder,
+		Name:     name,
+		Cache:    c,
+		TTL:      DefaultEmbeddingCacheTTL,
+		local:    newEmbeddingLRU(size),
+	}
+}
+
+// Ensure CachedEmbedder implements Embedder
+var _ Embedder = (*CachedEmbedder)(nil)
+
+func (e *CachedEmbedder) Embed(ctx context.Context, model string, text string) ([]float32, error) {
+	return firstEmbedding(e.EmbedBatch(ctx, model, []string{text}))
+}
+
+func (e *CachedEmbedder) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = e.key(model, text)
+		embeddings[i], _ = e.local.get(keys[i])
+	}
+	e.lookupShared(ctx, keys, embeddings)
+
+	// Embed each missing text once, however often the batch repeats it
+	var missing []string
+	positions := make(map[string][]int)
+	for i, embedding := range embeddings {
+		if embedding != nil {
+			continue
+		}
+		if _, ok := positions[keys[i]]; !ok {
+			missing = append(missing, texts[i])
+		}
+		positions[keys[i]] = append(positions[keys[i]], i)
+	}
+	if len(missing) == 0 {
+		return embeddings, nil
+	}
+
+	embedded, err := e.Embedder.EmbedBatch(ctx, model, missing)
+	if err != nil {
+		return nil, err
+	}
+	for j, text := range missing {
+		key := e.key(model, text)
+		for _, i := range positions[key] {
+			embeddings[i] = embedded[j]
+		}
+		e.local.put(key, embedded[j])
+		if e.Cache != nil {
+			e.Cache.Set(ctx, key, encodeEmbedding(embedded[j]), e.TTL)
+		}
+	}
+	return embeddings, nil
+}
+
+// lookupShared fills the embeddings missing in process from the shared cache.
+func (e *CachedEmbedder) lookupShared(ctx context.Context, keys []string, embeddings [][]float32) {
+	if e.Cache == nil {
+		return
+	}
+	var lookup []string
+	var at []int
+	for i, embedding := range embeddings {
+		if embedding == nil {
+			lookup = append(lookup, keys[i])
+			at = append(at, i)
+		}
+	}
+	if len(lookup) == 0 {
+		return
+	}
+
+	values, err := e.Cache.MGet(ctx, lookup...).Result()
+	if err != nil || len(values) != len(lookup) {
+		return
+	}
+	for j, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if embedding, ok := decodeEmbedding(str); ok {
+			embeddings[at[j]] = embedding
+			e.local.put(lookup[j], embedding)
+		}
+	}
+}
+
+func (e *CachedEmbedder) key(model, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return "emb:" + e.Name + ":" + model + ":" + hex.EncodeToString(sum[:])
+}
+
+// encodeEmbedding packs an embedding as little-endian float32s, a quarter
+// of its JSON size.
+func encodeEmbedding(embedding []float32) []byte {
+	buf := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeEmbedding(value string) ([]float32, bool) {
+	if len(value) == 0 || len(value)%4 != 0 {
+		return nil, false
+	}
+	embedding := make([]float32, len(value)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32([]byte(value[4*i : 4*i+4])))
+	}
+	return embedding, true
+}
+
+// embeddingLRU keeps the most recently used embeddings.
+type embeddingLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Front is the most recently used
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	embedding []float32
+}
+
+func newEmbeddingLRU(size int) *embeddingLRU {
+	return &embeddingLRU{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *embeddingLRU) get(key string) ([]float32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry).embedding, true
+}
+
+func (c *embeddingLRU) put(key string, embedding []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruEntry).embedding = embedding
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, embedding: embedding})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
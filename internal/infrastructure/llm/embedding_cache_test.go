@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hrygo/council/internal/infrastructure/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// sharedCache backs a MockCache's MGet and Set with a map.
+func sharedCache(store map[string]string) *cache.MockCache {
+	return &cache.MockCache{
+		MGetFunc: func(ctx context.Context, keys ...string) *redis.SliceCmd {
+			values := make([]interface{}, len(keys))
+			for i, key := range keys {
+				if v, ok := store[key]; ok {
+					values[i] = v
+				}
+			}
+			cmd := redis.NewSliceCmd(ctx)
+			cmd.SetVal(values)
+			return cmd
+		},
+		SetFunc: func(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+			store[key] = string(value.([]byte))
+			return redis.NewStatusCmd(ctx)
+		},
+	}
+}
+
+func lengthEmbedding(text string) []float32 {
+	return []float32{float32(len(text)), 0.5}
+}
+
+func TestCachedEmbedder_EmbedBatch(t *testing.T) {
+	ctx := context.Background()
+	store := make(map[string]string)
+	mock := &MockProvider{EmbedFunc: lengthEmbedding}
+	embedder := NewCachedEmbedder(mock, "openai", sharedCache(store), 10)
+
+	embeddings, err := embedder.EmbedBatch(ctx, "m", []string{"a", "bb", "a"})
+	if err != nil {
+		t.Fatalf("EmbedBatch: %v", err)
+	}
+	if mock.EmbedCalls != 1 || len(mock.EmbeddedTexts) != 2 || len(store) != 2 {
+		t.Errorf("Expected the two distinct texts embedded in one call, got %v", mock.EmbeddedTexts)
+	}
+	if embeddings[0][0] != 1 || embeddings[1][0] != 2 || embeddings[2][0] != 1 {
+		t.Errorf("Expected the embeddings in input order, got %v", embeddings)
+	}
+
+	// A repeated query is served in process
+	if embedding, err := embedder.Embed(ctx, "m", "bb"); err != nil || embedding[0] != 2 || mock.EmbedCalls != 1 {
+		t.Errorf("Expected a cached embedding, got %v, %v after %d calls", embedding, err, mock.EmbedCalls)
+	}
+
+	// Another instance finds it in the shared cache, only embedding the new text
+	other := NewCachedEmbedder(mock, "openai", sharedCache(store), 10)
+	embeddings, err = other.EmbedBatch(ctx, "m", []string{"bb", "ccc"})
+	if err != nil || embeddings[0][0] != 2 || embeddings[0][1] != 0.5 || embeddings[1][0] != 3 {
+		t.Fatalf("Expected the shared embedding, got %v, %v", embeddings, err)
+	}
+	if mock.EmbedCalls != 2 || mock.EmbeddedTexts[len(mock.EmbeddedTexts)-1] != "ccc" || len(mock.EmbeddedTexts) != 3 {
+		t.Errorf("Expected only the new text embedded, got %v", mock.EmbeddedTexts)
+	}
+
+	// Another model or provider does not share embeddings
+	if _, err := embedder.Embed(ctx, "other", "a"); err != nil || mock.EmbedCalls != 3 {
+		t.Errorf("Expected a miss for another model, got %v after %d calls", err, mock.EmbedCalls)
+	}
+	if NewCachedEmbedder(mock, "gemini", nil, 10).key("m", "a") == embedder.key("m", "a") {
+		t.Error("Expected providers to be part of the key")
+	}
+}
+
+func TestCachedEmbedder_SharedCacheDown(t *testing.T) {
+	ctx := context.Background()
+	down := &cache.MockCache{
+		MGetFunc: func(ctx context.Context, keys ...string) *redis.SliceCmd {
+			cmd := redis.NewSliceCmd(ctx)
+			cmd.SetErr(errors.New("connection refused"))
+			return cmd
+		},
+	}
+	mock := &MockProvider{EmbedFunc: lengthEmbedding}
+	embedder := NewCachedEmbedder(mock, "openai", down, 2)
+
+	for _, text := range []string{"a", "a", "bb", "ccc", "a"} {
+		if _, err := embedder.Embed(ctx, "m", text); err != nil {
+			t.Fatalf("Embed: %v", err)
+		}
+	}
+	// "a" was evicted by "bb" and "ccc" from the two-entry cache
+	if mock.EmbedCalls != 4 {
+		t.Errorf("Expected the in-process cache to serve one repeat, got %d calls", mock.EmbedCalls)
+	}
+
+	mock.EmbedError = errors.New("quota exceeded")
+	if _, err := embedder.Embed(ctx, "m", "dddd"); err == nil {
+		t.Error("Expected the embedder's error")
+	}
+}
+
+func TestOpenAIClient_EmbedBatch(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Invalid request: %v", err)
+		}
+		sizes = append(sizes, len(req.Input))
+
+		// Answer in reverse order; the index places each embedding
+		var data []string
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			data = append(data, fmt.Sprintf(`{"object": "embedding", "index": %d, "embedding": [%d]}`, i, len(req.Input[i])))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"object": "list", "data": [%s]}`, strings.Join(data, ","))
+	}))
+	defer server.Close()
+
+	texts := make([]string, 300)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("%0*d", i%7+1, 0)
+	}
+	embeddings, err := NewOpenAICompatibleClient("sk-test", server.URL).EmbedBatch(context.Background(), "", texts)
+	if err != nil {
+		t.Fatalf("EmbedBatch: %v", err)
+	}
+	if len(sizes) != 2 || sizes[0] != openAIEmbedBatchSize || sizes[1] != 300-openAIEmbedBatchSize {
+		t.Errorf("Expected two requests, got %v", sizes)
+	}
+	for i, embedding := range embeddings {
+		if len(embedding) != 1 || embedding[0] != float32(i%7+1) {
+			t.Fatalf("Expected embedding %d to match its text, got %v", i, embedding)
+		}
+	}
+}
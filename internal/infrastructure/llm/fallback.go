@@ -0,0 +1,309 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/genai"
+)
+
+// FallbackTarget names a provider of a fallback chain and, optionally, the
+// model to ask it for instead of the requested one.
+type FallbackTarget struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// ParseFallbackTargets parses "provider[:model]" entries, e.g.
+// "siliconflow:deepseek-ai/DeepSeek-V3" or "ollama:qwen2.5:7b".
+func ParseFallbackTargets(entries []string) []FallbackTarget {
+	var targets []FallbackTarget
+	for _, entry := range entries {
+		provider, model, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		if provider != "" {
+			targets = append(targets, FallbackTarget{Provider: provider, Model: model})
+		}
+	}
+	return targets
+}
+
+// FallbackStep is a resolved provider of a chain.
+type FallbackStep struct {
+	Name     string // Provider name, for logs and errors
+	Provider LLMProvider
+	Model    string // Replaces the requested model; empty keeps it
+}
+
+// RetryPolicy bounds the attempts on each provider of a chain. Delays grow
+// exponentially from BaseDelay up to MaxDelay, with full jitter.
+type RetryPolicy struct {
+	MaxRetries int // Retries per provider after the first attempt
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy is used by the Registry.
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 2, BaseDelay: 500 * time.Millisecond, MaxDelay: 8 * time.Second}
+
+// FallbackProvider tries an ordered chain of providers. Transient errors
+// (429, 5xx, timeouts, dropped connections) are retried with backoff before
+// moving on to the next provider; errors of the provider itself (bad key,
+// unknown model) move on at once; invalid requests fail immediately. Retries
+// wait at least as long as a Retry-After asks, or move on if that is longer
+// than MaxDelay.
+//
+// A stream that fails midway is restarted on the next attempt: the consumer
+// receives a chunk with Restart set and must discard what it received so far.
+type FallbackProvider struct {
+	Steps []FallbackStep
+	Retry RetryPolicy
+
+	sleep func(ctx context.Context, d time.Duration) error // Replaced in tests
+}
+
+var _ LLMProvider = (*FallbackProvider)(nil)
+
+func NewFallbackProvider(retry RetryPolicy, steps ...FallbackStep) *FallbackProvider {
+	return &FallbackProvider{Steps: steps, Retry: retry}
+}
+
+func (f *FallbackProvider) Generate(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	var resp *CompletionResponse
+	err := f.try(ctx, req, func(p LLMProvider, r *CompletionRequest) error {
+		var err error
+		resp, err = p.Generate(ctx, r)
+		return err
+	})
+	return resp, err
+}
+
+func (f *FallbackProvider) Stream(ctx context.Context, req *CompletionRequest) (<-chan CompletionChunk, <-chan error) {
+	out := make(chan CompletionChunk)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errChan)
+
+		sent := false // Chunks were forwarded since the last restart
+		err := f.try(ctx, req, func(p LLMProvider, r *CompletionRequest) error {
+			if sent {
+				select {
+				case out <- CompletionChunk{Restart: true}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				sent = false
+			}
+
+			chunks, errs := p.Stream(ctx, r)
+			for chunks != nil || errs != nil {
+				select {
+				case chunk, ok := <-chunks:
+					if !ok {
+						chunks = nil
+						continue
+					}
+					select {
+					case out <- chunk:
+						sent = true
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				case err, ok := <-errs:
+					if !ok {
+						errs = nil
+						continue
+					}
+					if err != nil {
+						return err
+					}
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return out, errChan
+}
+
+// try runs call against the chain until it succeeds.
+func (f *FallbackProvider) try(ctx context.Context, req *CompletionRequest, call func(LLMProvider, *CompletionRequest) error) error {
+	var failures []error
+	for i, step := range f.Steps {
+		r := req
+		if step.Model != "" {
+			override := *req
+			override.Model = step.Model
+			r = &override
+		}
+
+		var retryAfter time.Duration
+		for attempt := 0; attempt <= f.Retry.MaxRetries; attempt++ {
+			if attempt > 0 {
+				if err := f.wait(ctx, attempt, retryAfter); err != nil {
+					return err
+				}
+			}
+			err := call(step.Provider, r)
+			if err == nil {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return err
+			}
+			failures = append(failures, fmt.Errorf("%s: %w", step.Name, err))
+
+			class := classifyError(err)
+			if class == errorFatal {
+				return err
+			}
+			retryAfter = RetryAfter(err)
+			if class == errorFailover || (f.Retry.MaxDelay > 0 && retryAfter > f.Retry.MaxDelay) {
+				// Waiting as long as the provider asks would take too long
+				break
+			}
+			log.Printf("[LLM] %s attempt %d failed: %v", step.Name, attempt+1, err)
+		}
+		if i+1 < len(f.Steps) {
+			log.Printf("[LLM] %s unavailable, falling back to %s", step.Name, f.Steps[i+1].Name)
+		}
+	}
+	if len(failures) == 0 {
+		return errors.New("no llm provider configured")
+	}
+	return fmt.Errorf("all llm providers failed: %w", errors.Join(failures...))
+}
+
+// wait sleeps before a retry: a random delay up to BaseDelay * 2^(attempt-1),
+// capped at MaxDelay, and at least the Retry-After of the failed attempt.
+func (f *FallbackProvider) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	ceiling := f.Retry.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || (f.Retry.MaxDelay > 0 && ceiling > f.Retry.MaxDelay) {
+		ceiling = f.Retry.MaxDelay
+	}
+	var delay time.Duration
+	if ceiling > 0 {
+		delay = rand.N(ceiling) + 1
+	}
+	delay = max(delay, retryAfter)
+
+	if f.sleep != nil {
+		return f.sleep(ctx, delay)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type errorClass int
+
+const (
+	errorRetryable errorClass = iota // Transient: retry, then fall back
+	errorFailover                    // The provider cannot serve the request: fall back at once
+	errorFatal                       // The request is invalid: no provider will accept it
+)
+
+// StatusError is an error response of a provider API with its HTTP status.
+type StatusError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration // From the Retry-After header, if any
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Message)
+}
+
+// HTTPStatus returns the HTTP status of a provider error, or 0 if there is none.
+func HTTPStatus(err error) int {
+	var statusErr *StatusError
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	var geminiErr genai.APIError
+	switch {
+	case errors.As(err, &statusErr):
+		return statusErr.StatusCode
+	case errors.As(err, &apiErr):
+		return apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		return reqErr.HTTPStatusCode
+	case errors.As(err, &geminiErr):
+		return geminiErr.Code
+	}
+	return 0
+}
+
+// RetryAfter returns how long a provider asked to wait before the next call,
+// or 0 if it did not say.
+func RetryAfter(err error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header: seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// IsRetryable reports whether err is transient: rate limits, server errors,
+// timeouts and dropped connections.
+func IsRetryable(err error) bool {
+	return classifyError(err) == errorRetryable
+}
+
+func classifyError(err error) errorClass {
+	switch status := HTTPStatus(err); {
+	case status == http.StatusTooManyRequests, status == http.StatusRequestTimeout, status >= 500:
+		return errorRetryable
+	case status == http.StatusBadRequest, status == http.StatusRequestEntityTooLarge, status == http.StatusUnprocessableEntity:
+		return errorFatal
+	case status != 0:
+		return errorFailover // 401, 403, 404, ...
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.As(err, &netErr):
+		return errorRetryable
+	}
+	return errorFailover
+}
@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// scriptedProvider fails with the queued errors, then answers with its name.
+// A stream error is sent after the chunks in streamChunks.
+type scriptedProvider struct {
+	name         string
+	errs         []error
+	streamChunks []string
+	models       []string
+}
+
+func (p *scriptedProvider) next(req *CompletionRequest) error {
+	p.models = append(p.models, req.Model)
+	if len(p.errs) == 0 {
+		return nil
+	}
+	err := p.errs[0]
+	p.errs = p.errs[1:]
+	return err
+}
+
+func (p *scriptedProvider) Generate(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	if err := p.next(req); err != nil {
+		return nil, err
+	}
+	return &CompletionResponse{Content: p.name}, nil
+}
+
+func (p *scriptedProvider) Stream(ctx context.Context, req *CompletionRequest) (<-chan CompletionChunk, <-chan error) {
+	chunks := make(chan CompletionChunk, 10)
+	errs := make(chan error, 1)
+	err := p.next(req)
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+		for _, c := range p.streamChunks {
+			chunks <- CompletionChunk{Content: c}
+		}
+		if err != nil {
+			errs <- err
+			return
+		}
+		chunks <- CompletionChunk{Content: p.name}
+	}()
+	return chunks, errs
+}
+
+func newTestChain(retries int, steps ...FallbackStep) (*FallbackProvider, *[]time.Duration) {
+	var delays []time.Duration
+	f := NewFallbackProvider(RetryPolicy{MaxRetries: retries, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, steps...)
+	f.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return f, &delays
+}
+
+func TestFallbackProvider_RetriesThenFallsBack(t *testing.T) {
+	rateLimited := &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests, Message: "slow down"}
+	primary := &scriptedProvider{name: "deepseek", errs: []error{rateLimited, rateLimited, rateLimited}}
+	backup := &scriptedProvider{name: "siliconflow"}
+	chain, delays := newTestChain(2,
+		FallbackStep{Name: "deepseek", Provider: primary},
+		FallbackStep{Name: "siliconflow", Provider: backup, Model: "deepseek-ai/DeepSeek-V3"},
+	)
+
+	resp, err := chain.Generate(context.Background(), &CompletionRequest{Model: "deepseek-chat"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if resp.Content != "siliconflow" {
+		t.Errorf("Expected the fallback to answer, got %q", resp.Content)
+	}
+	if len(primary.models) != 3 {
+		t.Errorf("Expected 1 attempt and 2 retries on the primary, got %d", len(primary.models))
+	}
+	if len(backup.models) != 1 || backup.models[0] != "deepseek-ai/DeepSeek-V3" {
+		t.Errorf("Expected the fallback to be asked for its own model, got %v", backup.models)
+	}
+	if len(*delays) != 2 {
+		t.Fatalf("Expected a backoff before each retry, got %v", *delays)
+	}
+	for i, d := range *delays {
+		if ceiling := 100 * time.Millisecond << i; d <= 0 || d > ceiling {
+			t.Errorf("Expected retry %d to wait up to %v, got %v", i+1, ceiling, d)
+		}
+	}
+}
+
+func TestFallbackProvider_ErrorClasses(t *testing.T) {
+	t.Run("ProviderErrorFailsOverAtOnce", func(t *testing.T) {
+		primary := &scriptedProvider{name: "deepseek", errs: []error{&StatusError{StatusCode: http.StatusUnauthorized, Message: "bad key"}}}
+		chain, delays := newTestChain(2,
+			FallbackStep{Name: "deepseek", Provider: primary},
+			FallbackStep{Name: "ollama", Provider: &scriptedProvider{name: "ollama"}},
+		)
+		resp, err := chain.Generate(context.Background(), &CompletionRequest{})
+		if err != nil || resp.Content != "ollama" || len(*delays) != 0 {
+			t.Errorf("Expected an immediate fallback, got %v (%v) after %v", resp, err, *delays)
+		}
+	})
+
+	t.Run("InvalidRequestIsFatal", func(t *testing.T) {
+		backup := &scriptedProvider{name: "ollama"}
+		chain, _ := newTestChain(2,
+			FallbackStep{Name: "deepseek", Provider: &scriptedProvider{errs: []error{&StatusError{StatusCode: http.StatusBadRequest}}}},
+			FallbackStep{Name: "ollama", Provider: backup},
+		)
+		if _, err := chain.Generate(context.Background(), &CompletionRequest{}); err == nil || len(backup.models) != 0 {
+			t.Errorf("Expected a bad request to fail without fallback, got %v", err)
+		}
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		primary := &scriptedProvider{name: "deepseek", errs: []error{
+			&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 800 * time.Millisecond},
+			&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute},
+		}}
+		chain, delays := newTestChain(2,
+			FallbackStep{Name: "deepseek", Provider: primary},
+			FallbackStep{Name: "ollama", Provider: &scriptedProvider{name: "ollama"}},
+		)
+		resp, err := chain.Generate(context.Background(), &CompletionRequest{})
+		if err != nil || resp.Content != "ollama" {
+			t.Fatalf("Expected a fallback, got %v (%v)", resp, err)
+		}
+		if len(*delays) != 1 || (*delays)[0] < 800*time.Millisecond {
+			t.Errorf("Expected one retry after the Retry-After and no wait for a minute, got %v", *delays)
+		}
+	})
+
+	t.Run("AllFail", func(t *testing.T) {
+		chain, _ := newTestChain(0,
+			FallbackStep{Name: "deepseek", Provider: &scriptedProvider{errs: []error{errors.New("boom")}}},
+			FallbackStep{Name: "ollama", Provider: &scriptedProvider{errs: []error{fmt.Errorf("wrapped: %w", context.DeadlineExceeded)}}},
+		)
+		_, err := chain.Generate(context.Background(), &CompletionRequest{})
+		if err == nil || !strings.Contains(err.Error(), "deepseek: boom") || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the errors of every provider, got %v", err)
+		}
+	})
+
+	t.Run("Classification", func(t *testing.T) {
+		for err, want := range map[error]bool{
+			&openai.APIError{HTTPStatusCode: 503}:                                      true,
+			&openai.RequestError{HTTPStatusCode: 429, Err: errors.New("rate limited")}: true,
+			&StatusError{StatusCode: 404}:                                              false,
+			fmt.Errorf("read: %w", context.DeadlineExceeded):                           true,
+			errors.New("no choices returned"):                                          false,
+		} {
+			if got := IsRetryable(err); got != want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", err, got, want)
+			}
+		}
+	})
+}
+
+func TestParseFallbackTargets(t *testing.T) {
+	targets := ParseFallbackTargets([]string{"siliconflow:deepseek-ai/DeepSeek-V3", " ollama:qwen2.5:7b", "openai", ""})
+	want := []FallbackTarget{{"siliconflow", "deepseek-ai/DeepSeek-V3"}, {"ollama", "qwen2.5:7b"}, {"openai", ""}}
+	if fmt.Sprint(targets) != fmt.Sprint(want) {
+		t.Errorf("ParseFallbackTargets() = %v, want %v", targets, want)
+	}
+}
+
+func TestFallbackProvider_StreamRestartsMidway(t *testing.T) {
+	dropped := &StatusError{StatusCode: http.StatusBadGateway, Message: "upstream closed"}
+	primary := &scriptedProvider{name: "deepseek", errs: []error{dropped}, streamChunks: []string{"Hel"}}
+	chain, _ := newTestChain(0,
+		FallbackStep{Name: "deepseek", Provider: primary},
+		FallbackStep{Name: "ollama", Provider: &scriptedProvider{name: "ollama"}},
+	)
+
+	chunks, errs := chain.Stream(context.Background(), &CompletionRequest{})
+	var content strings.Builder
+	restarts := 0
+	for chunk := range chunks {
+		if chunk.Restart {
+			restarts++
+			content.Reset()
+		}
+		content.WriteString(chunk.Content)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if restarts != 1 || content.String() != "ollama" {
+		t.Errorf("Expected one restart and the fallback's answer, got %d restarts and %q", restarts, content.String())
+	}
+}
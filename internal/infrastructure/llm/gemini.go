@@ -53,7 +53,7 @@ func (c *GeminiClient) Generate(ctx context.Context, req *CompletionRequest) (*C
 	// Let's assume standard usage for the library.
 
 	var contents []*genai.Content
-	for _, msg := range req.Messages {
+	for _, msg := range geminiMessages(req) {
 		role := "user"
 		if msg.Role == "assistant" || msg.Role == "model" {
 			role = "model"
@@ -80,6 +80,7 @@ func (c *GeminiClient) Generate(ctx context.Context, req *CompletionRequest) (*C
 		val := int32(req.MaxTokens)
 		config.MaxOutputTokens = val
 	}
+	applyResponseFormat(req, config)
 
 	// s.Models.GenerateContent signature check fix
 	resp, err := c.client.Models.GenerateContent(ctx, req.Model, contents, config)
@@ -135,7 +136,7 @@ func (c *GeminiClient) Stream(ctx context.Context, req *CompletionRequest) (<-ch
 
 		// Construct prompt parts (same as Generate)
 		var contents []*genai.Content
-		for _, msg := range req.Messages {
+		for _, msg := range geminiMessages(req) {
 			role := "user"
 			if msg.Role == "assistant" || msg.Role == "model" {
 				role = "model"
@@ -159,6 +160,7 @@ func (c *GeminiClient) Stream(ctx context.Context, req *CompletionRequest) (<-ch
 			val := int32(req.MaxTokens)
 			config.MaxOutputTokens = val
 		}
+		applyResponseFormat(req, config)
 		// Enable Tools if present
 		// Enable Tools if present
 		if len(req.Tools) > 0 {
@@ -248,6 +250,13 @@ func (c *GeminiClient) Stream(ctx context.Context, req *CompletionRequest) (<-ch
 }
 
 func (c *GeminiClient) Embed(ctx context.Context, model string, text string) ([]float32, error) {
+	return firstEmbedding(c.EmbedBatch(ctx, model, []string{text}))
+}
+
+// geminiEmbedBatchSize is the most contents batchEmbedContents accepts.
+const geminiEmbedBatchSize = 100
+
+func (c *GeminiClient) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
 	if c.client == nil {
 		return nil, fmt.Errorf("gemini client is not initialized")
 	}
@@ -256,22 +265,48 @@ func (c *GeminiClient) Embed(ctx context.Context, model string, text string) ([]
 		model = "text-embedding-004"
 	}
 
-	// Prepare request
-	part := &genai.Part{Text: text}
-	contents := []*genai.Content{{
-		Parts: []*genai.Part{part},
-	}}
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiEmbedBatchSize {
+		batch := texts[start:min(start+geminiEmbedBatchSize, len(texts))]
+		contents := make([]*genai.Content, len(batch))
+		for i, text := range batch {
+			contents[i] = &genai.Content{Parts: []*genai.Part{{Text: text}}}
+		}
 
-	resp, err := c.client.Models.EmbedContent(ctx, model, contents, nil)
-	if err != nil {
-		return nil, fmt.Errorf("gemini embed error: %w", err)
+		resp, err := c.client.Models.EmbedContent(ctx, model, contents, nil)
+		if err != nil {
+			return nil, fmt.Errorf("gemini embed error: %w", err)
+		}
+		if len(resp.Embeddings) != len(batch) {
+			return nil, fmt.Errorf("gemini returned %d embeddings for %d inputs", len(resp.Embeddings), len(batch))
+		}
+		for _, e := range resp.Embeddings {
+			embeddings = append(embeddings, e.Values)
+		}
 	}
+	return embeddings, nil
+}
 
-	if len(resp.Embeddings) == 0 {
-		return nil, fmt.Errorf("no embedding returned")
+// geminiMessages returns the messages to send: the response format is
+// requested natively unless tools are present, as function calling cannot
+// be combined with a JSON response type.
+func geminiMessages(req *CompletionRequest) []Message {
+	if req.ResponseFormat != nil && len(req.Tools) > 0 {
+		return formatMessages(req)
 	}
+	return req.Messages
+}
 
-	return resp.Embeddings[0].Values, nil
+// applyResponseFormat sets the JSON response type and schema for a
+// ResponseFormat; see geminiMessages.
+func applyResponseFormat(req *CompletionRequest, config *genai.GenerateContentConfig) {
+	if req.ResponseFormat == nil || len(req.Tools) > 0 {
+		return
+	}
+	config.ResponseMIMEType = "application/json"
+	if req.ResponseFormat.Schema != nil {
+		config.ResponseJsonSchema = req.ResponseFormat.Schema
+	}
 }
 
 // schemaFromMap converts a generic JSON Schema map to genai.Schema.
@@ -33,6 +33,8 @@ type CompletionRequest struct {
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 	Stream      bool      `json:"stream,omitempty"`
 	Tools       []Tool    `json:"tools,omitempty"`
+	// ResponseFormat requests a JSON reply matching a schema.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 }
 
 type Tool struct {
@@ -64,6 +66,9 @@ type CompletionChunk struct {
 	Content   string     `json:"content"`
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"` // Partial tool calls
 	Usage     *Usage     `json:"usage,omitempty"`
+	// Restart tells the consumer to discard everything received so far: the
+	// stream failed midway and starts over (see FallbackProvider).
+	Restart bool `json:"restart,omitempty"`
 }
 
 // LLMProvider defines the interface for a Chat Model provider.
@@ -78,6 +83,9 @@ type LLMProvider interface {
 type Embedder interface {
 	// Embed generates embeddings for the given text using the specified model.
 	Embed(ctx context.Context, model string, text string) ([]float32, error)
+	// EmbedBatch generates the embeddings of texts, in order, splitting them
+	// into as few requests as the provider allows.
+	EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error)
 }
 
 // LLMConfig holds configuration for creating an LLM provider.
@@ -17,11 +17,13 @@ type MockProvider struct {
 
 	EmbedResponse []float32
 	EmbedError    error
+	EmbedFunc     func(text string) []float32 // Overrides EmbedResponse per text
 
 	// Method call tracking
 	GenerateCalls int
 	StreamCalls   int
 	EmbedCalls    int
+	EmbeddedTexts []string // Texts passed to Embed and EmbedBatch
 }
 
 // Ensure MockProvider implements interfaces
@@ -114,6 +116,32 @@ func (m *MockProvider) Embed(ctx context.Context, model string, text string) ([]
 	if m.EmbedError != nil {
 		return nil, m.EmbedError
 	}
+	m.EmbeddedTexts = append(m.EmbeddedTexts, text)
+	return m.embedding(text)
+}
+
+// EmbedBatch counts as a single call, like a provider's batch request.
+func (m *MockProvider) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	m.EmbedCalls++
+	if m.EmbedError != nil {
+		return nil, m.EmbedError
+	}
+	m.EmbeddedTexts = append(m.EmbeddedTexts, texts...)
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embedding, err := m.embedding(text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+func (m *MockProvider) embedding(text string) ([]float32, error) {
+	if m.EmbedFunc != nil {
+		return m.EmbedFunc(text), nil
+	}
 	if len(m.EmbedResponse) == 0 {
 		return nil, fmt.Errorf("mock embeddings empty")
 	}
@@ -3,8 +3,6 @@ package llm
 import (
 	"context"
 	"fmt"
-
-	openai "github.com/sashabaranov/go-openai"
 )
 
 // OllamaClient wraps OpenAIClient to handle Ollama specific logic.
@@ -29,6 +27,12 @@ var _ LLMProvider = (*OllamaClient)(nil)
 var _ Embedder = (*OllamaClient)(nil)
 
 func (c *OllamaClient) Embed(ctx context.Context, model string, text string) ([]float32, error) {
+	return firstEmbedding(c.EmbedBatch(ctx, model, []string{text}))
+}
+
+// EmbedBatch sends up to 64 texts per request; Ollama has no input limit, but
+// embeds a request on one model instance.
+func (c *OllamaClient) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
 	if model == "" {
 		// Ollama requires a model to be pulled. We can't easily guess a default that exists.
 		// But 'nomic-embed-text' or 'mxbai-embed-large' are common.
@@ -37,21 +41,5 @@ func (c *OllamaClient) Embed(ctx context.Context, model string, text string) ([]
 		// Let's rely on user config, but avoid OpenAI default.
 		return nil, fmt.Errorf("model is required for ollama embeddings")
 	}
-
-	resp, err := c.client.CreateEmbeddings(
-		ctx,
-		openai.EmbeddingRequest{
-			Input: []string{text},
-			Model: openai.EmbeddingModel(model),
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("ollama embed error: %w", err)
-	}
-
-	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("no embeddings returned")
-	}
-
-	return resp.Data[0].Embedding, nil
+	return c.embedBatch(ctx, "ollama", model, texts, 64)
 }
@@ -2,18 +2,33 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
 )
 
 type OpenAIClient struct {
-	client *openai.Client
+	client   *openai.Client
+	jsonMode jsonMode
 }
 
+// jsonMode is how an OpenAI-compatible API honours a ResponseFormat.
+type jsonMode int
+
+const (
+	jsonModePrompt jsonMode = iota // Instructions in the system prompt only
+	jsonModeObject                 // response_format json_object, plus the instructions
+	jsonModeSchema                 // response_format json_schema (structured outputs)
+)
+
 func NewOpenAIClient(apiKey string) *OpenAIClient {
-	return NewOpenAICompatibleClient(apiKey, "https://api.openai.com/v1")
+	client := NewOpenAICompatibleClient(apiKey, "https://api.openai.com/v1")
+	client.jsonMode = jsonModeSchema
+	return client
 }
 
 func NewOpenAICompatibleClient(apiKey, baseURL string) *OpenAIClient {
@@ -21,6 +36,7 @@ func NewOpenAICompatibleClient(apiKey, baseURL string) *OpenAIClient {
 	if baseURL != "" {
 		config.BaseURL = baseURL
 	}
+	config.HTTPClient = retryAfterDoer{http.DefaultClient}
 	client := openai.NewClientWithConfig(config)
 	return &OpenAIClient{
 		client: client,
@@ -34,8 +50,9 @@ var _ Embedder = (*OpenAIClient)(nil)
 func (c *OpenAIClient) Generate(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
 	// Model is required. If empty, the upstream API will return an error which we handle below.
 
-	messages := make([]openai.ChatCompletionMessage, len(req.Messages))
-	for i, msg := range req.Messages {
+	reqMessages, format := c.responseFormat(req)
+	messages := make([]openai.ChatCompletionMessage, len(reqMessages))
+	for i, msg := range reqMessages {
 		messages[i] = openai.ChatCompletionMessage{
 			Role:    msg.Role,
 			Content: msg.Content,
@@ -45,11 +62,12 @@ func (c *OpenAIClient) Generate(ctx context.Context, req *CompletionRequest) (*C
 	resp, err := c.client.CreateChatCompletion(
 		ctx,
 		openai.ChatCompletionRequest{
-			Model:       req.Model,
-			Messages:    messages,
-			Temperature: req.Temperature,
-			TopP:        req.TopP,
-			MaxTokens:   req.MaxTokens,
+			Model:          req.Model,
+			Messages:       messages,
+			Temperature:    req.Temperature,
+			TopP:           req.TopP,
+			MaxTokens:      req.MaxTokens,
+			ResponseFormat: format,
 		},
 	)
 
@@ -83,8 +101,9 @@ func (c *OpenAIClient) Stream(ctx context.Context, req *CompletionRequest) (<-ch
 		// If empty, the provider usually returns an error (handled below).
 		// We avoid hardcoding GPT-4o here to support compatible providers (DeepSeek, GLM, etc.).
 
-		messages := make([]openai.ChatCompletionMessage, len(req.Messages))
-		for i, msg := range req.Messages {
+		reqMessages, format := c.responseFormat(req)
+		messages := make([]openai.ChatCompletionMessage, len(reqMessages))
+		for i, msg := range reqMessages {
 			messages[i] = openai.ChatCompletionMessage{
 				Role:    msg.Role,
 				Content: msg.Content,
@@ -137,6 +156,7 @@ func (c *OpenAIClient) Stream(ctx context.Context, req *CompletionRequest) (<-ch
 				StreamOptions: &openai.StreamOptions{
 					IncludeUsage: true,
 				},
+				ResponseFormat: format,
 			},
 		)
 		if err != nil {
@@ -197,25 +217,111 @@ func (c *OpenAIClient) Stream(ctx context.Context, req *CompletionRequest) (<-ch
 	return outputChan, errChan
 }
 
+// responseFormat maps req.ResponseFormat to the API's response_format for
+// the client's jsonMode, returning the messages to send.
+func (c *OpenAIClient) responseFormat(req *CompletionRequest) ([]Message, *openai.ChatCompletionResponseFormat) {
+	f := req.ResponseFormat
+	switch {
+	case f == nil:
+		return req.Messages, nil
+	case c.jsonMode == jsonModeSchema && f.Schema != nil:
+		schema, err := json.Marshal(f.Schema)
+		if err != nil {
+			return formatMessages(req), nil
+		}
+		return req.Messages, &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   f.name(),
+				Schema: json.RawMessage(schema),
+			},
+		}
+	case c.jsonMode == jsonModePrompt:
+		return formatMessages(req), nil
+	}
+	return formatMessages(req), &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+}
+
+// openAIEmbedBatchSize is the number of inputs per embeddings request; the
+// API accepts 2048, but large batches of long chunks exceed its token cap.
+const openAIEmbedBatchSize = 256
+
 func (c *OpenAIClient) Embed(ctx context.Context, model string, text string) ([]float32, error) {
+	return firstEmbedding(c.EmbedBatch(ctx, model, []string{text}))
+}
+
+func (c *OpenAIClient) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
 	if model == "" {
 		model = string(openai.SmallEmbedding3)
 	}
+	return c.embedBatch(ctx, "openai", model, texts, openAIEmbedBatchSize)
+}
 
-	resp, err := c.client.CreateEmbeddings(
-		ctx,
-		openai.EmbeddingRequest{
-			Input: []string{text},
-			Model: openai.EmbeddingModel(model),
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("openai embed error: %w", err)
+// embedBatch embeds texts through the OpenAI-compatible embeddings endpoint,
+// size inputs per request. provider names the API in errors.
+func (c *OpenAIClient) embedBatch(ctx context.Context, provider, model string, texts []string, size int) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for start := 0; start < len(texts); start += size {
+		batch := texts[start:min(start+size, len(texts))]
+		resp, err := c.client.CreateEmbeddings(
+			ctx,
+			openai.EmbeddingRequest{
+				Input: batch,
+				Model: openai.EmbeddingModel(model),
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s embed error: %w", provider, err)
+		}
+		if len(resp.Data) != len(batch) {
+			return nil, fmt.Errorf("%s returned %d embeddings for %d inputs", provider, len(resp.Data), len(batch))
+		}
+
+		// The data carries the index of its input; it is not guaranteed in order
+		for i, data := range resp.Data {
+			index := data.Index
+			if index < 0 || index >= len(batch) {
+				index = i
+			}
+			embeddings[start+index] = data.Embedding
+		}
 	}
+	return embeddings, nil
+}
 
-	if len(resp.Data) == 0 {
+// firstEmbedding returns the single embedding of a one-text batch.
+func firstEmbedding(embeddings [][]float32, err error) ([]float32, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 || embeddings[0] == nil {
 		return nil, fmt.Errorf("no embeddings returned")
 	}
+	return embeddings[0], nil
+}
+
+// retryAfterDoer turns error responses carrying a Retry-After header into a
+// StatusError, as go-openai drops the response headers of errors.
+type retryAfterDoer struct {
+	client openai.HTTPDoer
+}
 
-	return resp.Data[0].Embedding, nil
+func (d retryAfterDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.client.Do(req)
+	if err != nil || resp.StatusCode < 400 {
+		return resp, err
+	}
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if retryAfter == 0 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	statusErr := &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(raw)), RetryAfter: retryAfter}
+	var body openai.ErrorResponse
+	if json.Unmarshal(raw, &body) == nil && body.Error != nil {
+		statusErr.Message = body.Error.Message
+	}
+	return nil, statusErr
 }
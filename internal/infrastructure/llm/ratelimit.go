@@ -0,0 +1,540 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit bounds the calls to a provider or to one of its models.
+// Zero fields are unlimited.
+type RateLimit struct {
+	RequestsPerMinute int
+	TokensPerMinute   int // Prompt and completion tokens
+	MaxConcurrency    int
+}
+
+// ParseRateLimits parses "key=rpm:60,tpm:100000,concurrency:4" entries.
+// The key is a provider ("deepseek") or a model of a provider
+// ("siliconflow/deepseek-ai/DeepSeek-V3"); a call must satisfy both.
+func ParseRateLimits(entries []string) (map[string]RateLimit, error) {
+	limits := make(map[string]RateLimit)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, spec, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid rate limit %q: expected provider[/model]=rpm:N,tpm:N,concurrency:N", entry)
+		}
+		provider, model, _ := strings.Cut(key, "/")
+		key = rateLimitKey(provider, model)
+
+		var limit RateLimit
+		for _, field := range strings.Split(spec, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(field), ":")
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid rate limit %q: bad value for %q", entry, name)
+			}
+			switch strings.TrimSpace(name) {
+			case "rpm":
+				limit.RequestsPerMinute = n
+			case "tpm":
+				limit.TokensPerMinute = n
+			case "concurrency":
+				limit.MaxConcurrency = n
+			default:
+				return nil, fmt.Errorf("invalid rate limit %q: unknown limit %q", entry, name)
+			}
+		}
+		limits[key] = limit
+	}
+	return limits, nil
+}
+
+func rateLimitKey(provider, model string) string {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if model == "" {
+		return provider
+	}
+	return provider + "/" + model
+}
+
+// Throttle reasons
+const (
+	ThrottleConcurrency = "concurrency"         // MaxConcurrency calls are running
+	ThrottleRequests    = "requests_per_minute" // RequestsPerMinute is used up
+	ThrottleTokens      = "tokens_per_minute"   // TokensPerMinute is used up
+	ThrottleRetryAfter  = "retry_after"         // The provider asked to back off
+	ThrottleQueued      = "queued"              // Earlier calls of the session wait
+)
+
+// Throttle describes a call that has to wait for a rate limit.
+type Throttle struct {
+	Provider string
+	Model    string
+	Reason   string
+	Wait     time.Duration // Expected wait; 0 when it depends on running calls
+}
+
+type contextKey int
+
+const (
+	sessionKey contextKey = iota
+	throttleKey
+)
+
+// WithSession tags the calls made with ctx with a session. Calls waiting for
+// a rate limit are served round-robin across sessions.
+func WithSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionKey, sessionID)
+}
+
+// WithThrottleListener registers fn to be told when a call made with ctx has
+// to wait for a rate limit. fn runs on the caller's goroutine.
+func WithThrottleListener(ctx context.Context, fn func(Throttle)) context.Context {
+	return context.WithValue(ctx, throttleKey, fn)
+}
+
+// RateLimiter admits calls to providers within their limits. Calls that do
+// not fit wait in a queue per session; sessions take turns and calls of a
+// session keep their order.
+type RateLimiter struct {
+	limits map[string]RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	queues  map[string][]*waiter // Waiting calls per session
+	order   []string             // Sessions with waiting calls, next turn first
+	timer   *time.Timer
+	now     func() time.Time // Replaced in tests
+}
+
+// bucket tracks the usage of one limit key. Request and token allowances
+// refill continuously up to one minute's worth.
+type bucket struct {
+	limit       RateLimit
+	inFlight    int
+	requests    float64
+	tokens      float64
+	updated     time.Time
+	pausedUntil time.Time
+}
+
+type waiter struct {
+	session string
+	buckets []*bucket
+	cost    int // Tokens reserved
+	ready   chan struct{}
+}
+
+func NewRateLimiter(limits map[string]RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limits:  limits,
+		buckets: make(map[string]*bucket),
+		queues:  make(map[string][]*waiter),
+		now:     time.Now,
+	}
+}
+
+// Limits reports whether calls to provider are limited.
+func (l *RateLimiter) Limits(provider string) bool {
+	if l == nil {
+		return false
+	}
+	provider = rateLimitKey(provider, "")
+	for key := range l.limits {
+		if key == provider || strings.HasPrefix(key, provider+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// countsTokens reports whether calls to the model are limited in tokens, so
+// that their prompts need to be counted.
+func (l *RateLimiter) countsTokens(provider, model string) bool {
+	return l.limits[rateLimitKey(provider, "")].TokensPerMinute > 0 ||
+		l.limits[rateLimitKey(provider, model)].TokensPerMinute > 0
+}
+
+// Acquire waits until a call of cost tokens to the model may start. The
+// returned function must be called when the call has finished, with the
+// tokens it actually used, or 0 to keep the reservation.
+func (l *RateLimiter) Acquire(ctx context.Context, provider, model string, cost int) (func(used int), error) {
+	session, _ := ctx.Value(sessionKey).(string)
+
+	l.mu.Lock()
+	now := l.now()
+	w := &waiter{session: session, cost: cost, ready: make(chan struct{})}
+	w.buckets = append(w.buckets, l.bucket(rateLimitKey(provider, ""), now))
+	if _, ok := l.limits[rateLimitKey(provider, model)]; ok && model != "" {
+		w.buckets = append(w.buckets, l.bucket(rateLimitKey(provider, model), now))
+	}
+	if len(l.queues[session]) == 0 {
+		l.order = append(l.order, session)
+	}
+	l.queues[session] = append(l.queues[session], w)
+	l.dispatch()
+
+	select {
+	case <-w.ready:
+		l.mu.Unlock()
+		return l.releaser(w), nil
+	default:
+	}
+	throttle := Throttle{Provider: provider, Model: model, Reason: ThrottleQueued}
+	if queue := l.queues[session]; len(queue) > 0 && queue[0] == w {
+		throttle.Reason, throttle.Wait = l.blockedBy(w, l.now())
+	}
+	l.mu.Unlock()
+
+	if notify, ok := ctx.Value(throttleKey).(func(Throttle)); ok {
+		notify(throttle)
+	}
+
+	select {
+	case <-w.ready:
+		return l.releaser(w), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		select {
+		case <-w.ready: // Admitted meanwhile: hand the slot back
+			l.release(w, 0)
+		default:
+			l.dequeue(w)
+			l.dispatch()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Pause holds back calls to provider for d, as asked by a Retry-After.
+func (l *RateLimiter) Pause(provider string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b := l.bucket(rateLimitKey(provider, ""), now)
+	if until := now.Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	l.dispatch()
+}
+
+func (l *RateLimiter) releaser(w *waiter) func(int) {
+	var once sync.Once
+	return func(used int) {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.release(w, used)
+		})
+	}
+}
+
+func (l *RateLimiter) release(w *waiter, used int) {
+	now := l.now()
+	for _, b := range w.buckets {
+		b.refill(now)
+		b.inFlight--
+		if b.limit.TokensPerMinute > 0 && used > 0 {
+			// Settle the reservation with the actual usage; the allowance
+			// may go negative and recovers as it refills
+			b.tokens -= float64(used - b.reservation(w.cost))
+		}
+	}
+	l.dispatch()
+}
+
+// bucket returns the bucket of key, creating it with a full allowance.
+func (l *RateLimiter) bucket(key string, now time.Time) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		limit := l.limits[key]
+		b = &bucket{
+			limit:    limit,
+			requests: float64(limit.RequestsPerMinute),
+			tokens:   float64(limit.TokensPerMinute),
+			updated:  now,
+		}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// dispatch admits waiting calls that fit, giving each session a turn in
+// order, and schedules itself for when the next one may fit.
+func (l *RateLimiter) dispatch() {
+	now := l.now()
+	for _, b := range l.buckets {
+		b.refill(now)
+	}
+
+	for admitted := true; admitted; {
+		admitted = false
+		for i, session := range l.order {
+			w := l.queues[session][0]
+			if reason, _ := l.blockedBy(w, now); reason != "" {
+				continue
+			}
+			for _, b := range w.buckets {
+				b.inFlight++
+				b.requests--
+				b.tokens -= float64(b.reservation(w.cost))
+			}
+			close(w.ready)
+
+			// The session's turn is over: move it to the back
+			l.queues[session] = l.queues[session][1:]
+			l.order = append(l.order[:i], l.order[i+1:]...)
+			if len(l.queues[session]) > 0 {
+				l.order = append(l.order, session)
+			} else {
+				delete(l.queues, session)
+			}
+			admitted = true
+			break
+		}
+	}
+
+	// Calls waiting for running ones are woken by release; the others
+	// need a timer
+	var next time.Duration
+	for _, session := range l.order {
+		if reason, wait := l.blockedBy(l.queues[session][0], now); reason != ThrottleConcurrency && (next == 0 || wait < next) {
+			next = wait
+		}
+	}
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if next > 0 {
+		l.timer = time.AfterFunc(next, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.dispatch()
+		})
+	}
+}
+
+// blockedBy returns why w cannot start now and how long it should wait, or
+// "" if it can start.
+func (l *RateLimiter) blockedBy(w *waiter, now time.Time) (string, time.Duration) {
+	reason, wait := "", time.Duration(0)
+	block := func(r string, d time.Duration) {
+		if reason == "" || d > wait {
+			reason, wait = r, d
+		}
+	}
+	busy := false
+	for _, b := range w.buckets {
+		if now.Before(b.pausedUntil) {
+			block(ThrottleRetryAfter, b.pausedUntil.Sub(now))
+		}
+		if b.limit.MaxConcurrency > 0 && b.inFlight >= b.limit.MaxConcurrency {
+			busy = true
+		}
+		if b.limit.RequestsPerMinute > 0 && b.requests < 1 {
+			block(ThrottleRequests, refillTime(1-b.requests, b.limit.RequestsPerMinute))
+		}
+		if need := float64(b.reservation(w.cost)); b.limit.TokensPerMinute > 0 && b.tokens < need {
+			block(ThrottleTokens, refillTime(need-b.tokens, b.limit.TokensPerMinute))
+		}
+	}
+	if busy {
+		// Running calls must finish first, however long they take
+		return ThrottleConcurrency, 0
+	}
+	return reason, wait
+}
+
+func (l *RateLimiter) dequeue(w *waiter) {
+	queue := l.queues[w.session]
+	for i, queued := range queue {
+		if queued == w {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(queue) > 0 {
+		l.queues[w.session] = queue
+		return
+	}
+	delete(l.queues, w.session)
+	for i, session := range l.order {
+		if session == w.session {
+			l.order = append(l.order[:i], l.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Minutes()
+	if elapsed <= 0 {
+		return
+	}
+	b.updated = now
+	b.requests = min(b.requests+elapsed*float64(b.limit.RequestsPerMinute), float64(b.limit.RequestsPerMinute))
+	b.tokens = min(b.tokens+elapsed*float64(b.limit.TokensPerMinute), float64(b.limit.TokensPerMinute))
+}
+
+// reservation returns the tokens reserved for a call of cost tokens. Calls
+// larger than a minute's allowance reserve all of it, so they can still run.
+func (b *bucket) reservation(cost int) int {
+	if b.limit.TokensPerMinute == 0 {
+		return 0
+	}
+	return min(cost, b.limit.TokensPerMinute)
+}
+
+// refillTime returns how long a per-minute allowance takes to refill amount.
+func refillTime(amount float64, perMinute int) time.Duration {
+	return time.Duration(amount/float64(perMinute)*float64(time.Minute)).Round(time.Millisecond) + time.Millisecond
+}
+
+// RateLimitedProvider admits the calls of an LLMProvider through a
+// RateLimiter. A 429 with a Retry-After pauses the provider for that long.
+type RateLimitedProvider struct {
+	LLMProvider
+	Name    string // Provider name the limits are configured for
+	Limiter *RateLimiter
+}
+
+func (p *RateLimitedProvider) Generate(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	release, err := p.Limiter.Acquire(ctx, p.Name, req.Model, p.cost(req))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.LLMProvider.Generate(ctx, req)
+	if err != nil {
+		p.backOff(err)
+		release(0)
+		return nil, err
+	}
+	release(resp.Usage.TotalTokens)
+	return resp, nil
+}
+
+func (p *RateLimitedProvider) Stream(ctx context.Context, req *CompletionRequest) (<-chan CompletionChunk, <-chan error) {
+	out := make(chan CompletionChunk)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errChan)
+
+		release, err := p.Limiter.Acquire(ctx, p.Name, req.Model, p.cost(req))
+		if err != nil {
+			errChan <- err
+			return
+		}
+		used := 0
+		defer func() { release(used) }()
+
+		chunks, errs := p.LLMProvider.Stream(ctx, req)
+		for chunks != nil || errs != nil {
+			select {
+			case chunk, ok := <-chunks:
+				if !ok {
+					chunks = nil
+					continue
+				}
+				if chunk.Usage != nil {
+					used = chunk.Usage.TotalTokens
+				}
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					errChan <- ctx.Err()
+					return
+				}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				if err != nil {
+					p.backOff(err)
+					errChan <- err
+					return
+				}
+			}
+		}
+	}()
+
+	return out, errChan
+}
+
+// cost returns the tokens to reserve for req: its prompt and at most
+// MaxTokens of completion.
+func (p *RateLimitedProvider) cost(req *CompletionRequest) int {
+	if !p.Limiter.countsTokens(p.Name, req.Model) {
+		return 0
+	}
+	cost := req.MaxTokens
+	for _, msg := range req.Messages {
+		cost += CountTokens(req.Model, msg.Content)
+	}
+	return cost
+}
+
+func (p *RateLimitedProvider) backOff(err error) {
+	if d := RetryAfter(err); d > 0 {
+		p.Limiter.Pause(p.Name, d)
+	}
+}
+
+// RateLimitedEmbedder admits the calls of an Embedder through a RateLimiter.
+type RateLimitedEmbedder struct {
+	Embedder
+	Name    string
+	Limiter *RateLimiter
+}
+
+func (e *RateLimitedEmbedder) Embed(ctx context.Context, model string, text string) ([]float32, error) {
+	cost := 0
+	if e.Limiter.countsTokens(e.Name, model) {
+		cost = CountTokens(model, text)
+	}
+	release, err := e.Limiter.Acquire(ctx, e.Name, model, cost)
+	if err != nil {
+		return nil, err
+	}
+	defer release(0)
+
+	embedding, err := e.Embedder.Embed(ctx, model, text)
+	if d := RetryAfter(err); d > 0 {
+		e.Limiter.Pause(e.Name, d)
+	}
+	return embedding, err
+}
+
+// EmbedBatch is admitted as one call carrying the tokens of all texts.
+func (e *RateLimitedEmbedder) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	cost := 0
+	if e.Limiter.countsTokens(e.Name, model) {
+		for _, text := range texts {
+			cost += CountTokens(model, text)
+		}
+	}
+	release, err := e.Limiter.Acquire(ctx, e.Name, model, cost)
+	if err != nil {
+		return nil, err
+	}
+	defer release(0)
+
+	embeddings, err := e.Embedder.EmbedBatch(ctx, model, texts)
+	if d := RetryAfter(err); d > 0 {
+		e.Limiter.Pause(e.Name, d)
+	}
+	return embeddings, err
+}
@@ -0,0 +1,167 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits([]string{"DeepSeek=rpm:60,concurrency:4", " siliconflow/deepseek-ai/DeepSeek-V3=tpm:100000 ", ""})
+	if err != nil {
+		t.Fatalf("ParseRateLimits: %v", err)
+	}
+	if limits["deepseek"] != (RateLimit{RequestsPerMinute: 60, MaxConcurrency: 4}) ||
+		limits["siliconflow/deepseek-ai/DeepSeek-V3"] != (RateLimit{TokensPerMinute: 100000}) {
+		t.Errorf("Unexpected limits %v", limits)
+	}
+
+	for _, entry := range []string{"deepseek", "deepseek=rpm", "deepseek=rps:1", "deepseek=rpm:-1"} {
+		if _, err := ParseRateLimits([]string{entry}); err == nil {
+			t.Errorf("Expected %q to be rejected", entry)
+		}
+	}
+}
+
+// queue starts an Acquire for session in the background and waits until it
+// is admitted or throttled. Admitted calls are sent to admitted.
+func queue(t *testing.T, l *RateLimiter, session string, admitted chan<- string) {
+	t.Helper()
+	throttled := make(chan Throttle, 1)
+	ctx := WithThrottleListener(WithSession(context.Background(), session), func(th Throttle) { throttled <- th })
+	acquired := make(chan func(int), 1)
+	go func() {
+		release, err := l.Acquire(ctx, "deepseek", "deepseek-chat", 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		acquired <- release
+	}()
+	select {
+	case <-throttled:
+		go func() {
+			release := <-acquired
+			admitted <- session
+			release(0)
+		}()
+	case release := <-acquired:
+		t.Cleanup(func() { release(0) })
+	}
+}
+
+func TestRateLimiter_FairAcrossSessions(t *testing.T) {
+	l := NewRateLimiter(map[string]RateLimit{"deepseek": {MaxConcurrency: 1}})
+	release, err := l.Acquire(WithSession(context.Background(), "a"), "deepseek", "deepseek-chat", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Session a floods the queue before b asks once; waiting calls are sent
+	// to admitted one at a time as the previous one releases its slot
+	admitted := make(chan string, 4)
+	queue(t, l, "a", admitted)
+	queue(t, l, "a", admitted)
+	queue(t, l, "a", admitted)
+	queue(t, l, "b", admitted)
+	release(0)
+
+	var order []string
+	for range 4 {
+		select {
+		case s := <-admitted:
+			order = append(order, s)
+		case <-time.After(time.Second):
+			t.Fatalf("Calls stalled after %v", order)
+		}
+	}
+	if fmt.Sprint(order) != "[a b a a]" {
+		t.Errorf("Expected b to get the second turn, got %v", order)
+	}
+}
+
+func TestRateLimiter_RequestsAndTokens(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter(map[string]RateLimit{"deepseek": {RequestsPerMinute: 2}, "deepseek/deepseek-chat": {TokensPerMinute: 100}})
+	l.now = func() time.Time { return now }
+
+	release, err := l.Acquire(context.Background(), "deepseek", "deepseek-chat", 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release(30) // The unused part of the reservation is returned
+
+	release, err = l.Acquire(context.Background(), "deepseek", "deepseek-chat", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release(0)
+
+	var throttle Throttle
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = WithThrottleListener(ctx, func(th Throttle) {
+		throttle = th
+		cancel()
+	})
+	if _, err := l.Acquire(ctx, "deepseek", "deepseek-chat", 10); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the third request of the minute to wait, got %v", err)
+	}
+	if throttle.Reason != ThrottleRequests || throttle.Wait < 25*time.Second || throttle.Wait > 35*time.Second {
+		t.Errorf("Expected to wait about 30s for the request allowance, got %+v", throttle)
+	}
+	if len(l.queues) != 0 || len(l.order) != 0 {
+		t.Errorf("Expected cancelled calls to leave the queue, got %v", l.queues)
+	}
+
+	now = now.Add(30 * time.Second)
+	_, err = l.Acquire(WithThrottleListener(context.Background(), func(th Throttle) {
+		t.Errorf("Expected the refilled allowance to admit the call, got %+v", th)
+	}), "deepseek", "other-model", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimitedProvider_RetryAfter(t *testing.T) {
+	limited := &StatusError{StatusCode: http.StatusTooManyRequests, Message: "slow down", RetryAfter: 100 * time.Millisecond}
+	p := &RateLimitedProvider{
+		LLMProvider: &scriptedProvider{name: "deepseek", errs: []error{limited}},
+		Name:        "deepseek",
+		Limiter:     NewRateLimiter(map[string]RateLimit{"deepseek": {MaxConcurrency: 4}}),
+	}
+
+	if _, err := p.Generate(context.Background(), &CompletionRequest{}); !errors.Is(err, limited) {
+		t.Fatalf("Expected the rate limit error, got %v", err)
+	}
+
+	var throttle Throttle
+	ctx := WithThrottleListener(context.Background(), func(th Throttle) { throttle = th })
+	started := time.Now()
+	chunks, errs := p.Stream(ctx, &CompletionRequest{})
+	for range chunks {
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if throttle.Reason != ThrottleRetryAfter || time.Since(started) < 90*time.Millisecond {
+		t.Errorf("Expected the next call to wait for Retry-After, got %+v after %v", throttle, time.Since(started))
+	}
+}
+
+func TestOpenAIClient_RetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = fmt.Fprint(w, `{"error": {"message": "Rate limit reached", "type": "requests"}}`)
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAICompatibleClient("sk-test", server.URL).Generate(context.Background(), &CompletionRequest{Model: "gpt-4o"})
+	if HTTPStatus(err) != http.StatusTooManyRequests || RetryAfter(err) != 7*time.Second || !IsRetryable(err) {
+		t.Errorf("Expected a retryable 429 with its Retry-After, got %v", err)
+	}
+}
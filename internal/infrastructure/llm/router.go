@@ -2,6 +2,7 @@ package llm
 
 import (
 	"fmt"
+	"log"
 	"strings"
 	"sync"
 
@@ -13,15 +14,26 @@ import (
 type Registry struct {
 	cfg       *config.Config
 	providers map[string]LLMProvider
+	limiter   *RateLimiter   // Shared by all providers with LLM_RATE_LIMITS
+	windows   map[string]int // Context windows from the llm_models table
 	mu        sync.RWMutex
 }
 
 // NewRegistry creates a new LLM provider registry
 func NewRegistry(cfg *config.Config) *Registry {
-	return &Registry{
+	r := &Registry{
 		cfg:       cfg,
 		providers: make(map[string]LLMProvider),
 	}
+	if cfg != nil && len(cfg.LLM.RateLimits) > 0 {
+		limits, err := ParseRateLimits(cfg.LLM.RateLimits)
+		if err != nil {
+			log.Printf("[LLM] Ignoring LLM_RATE_LIMITS: %v", err)
+		} else {
+			r.limiter = NewRateLimiter(limits)
+		}
+	}
+	return r
 }
 
 // RegisterProvider explicitly registers a provider instance (useful for testing or custom providers)
@@ -72,6 +84,11 @@ func (r *Registry) GetLLMProvider(providerName string) (LLMProvider, error) {
 		apiKey = r.cfg.SiliconFlowKey
 	case "gemini", "google":
 		apiKey = r.cfg.GeminiKey
+	case "anthropic":
+		apiKey = r.cfg.AnthropicKey
+		if r.cfg.LLM.Provider == "anthropic" {
+			baseURL = r.cfg.LLM.BaseURL
+		}
 	case "ollama":
 		// Ollama usually uses BaseURL, check LLM BaseURL if provider matches, or default
 		if r.cfg.LLM.Provider == "ollama" {
@@ -101,19 +118,53 @@ func (r *Registry) GetLLMProvider(providerName string) (LLMProvider, error) {
 	if err != nil {
 		return nil, err
 	}
+	if r.limiter.Limits(providerName) {
+		provider = &RateLimitedProvider{LLMProvider: provider, Name: providerName, Limiter: r.limiter}
+	}
 
 	// Cache it
 	r.providers[providerName] = provider
 	return provider, nil
 }
 
+// WithFallbacks wraps primary, the provider resolved for providerName, in a
+// FallbackProvider that retries transient errors and then tries fallbacks in
+// order, or the global LLM_FALLBACKS chain when fallbacks is empty. Without
+// a chain and retries primary is returned as is. Fallback providers that
+// cannot be created are left out.
+func (r *Registry) WithFallbacks(primary LLMProvider, providerName string, fallbacks []FallbackTarget) LLMProvider {
+	if len(fallbacks) == 0 {
+		fallbacks = ParseFallbackTargets(r.cfg.LLM.Fallbacks)
+	}
+	retry := DefaultRetryPolicy
+	retry.MaxRetries = r.cfg.LLM.MaxRetries
+	if len(fallbacks) == 0 && retry.MaxRetries <= 0 {
+		return primary
+	}
+
+	name := r.ProviderName(providerName)
+	steps := []FallbackStep{{Name: name, Provider: primary}}
+	for _, target := range fallbacks {
+		if r.ProviderName(target.Provider) == name && target.Model == "" {
+			continue
+		}
+		provider, err := r.GetLLMProvider(target.Provider)
+		if err != nil {
+			log.Printf("[LLM] Skipping fallback %s: %v", target.Provider, err)
+			continue
+		}
+		steps = append(steps, FallbackStep{Name: r.ProviderName(target.Provider), Provider: provider, Model: target.Model})
+	}
+	return NewFallbackProvider(retry, steps...)
+}
+
 // createProvider is the internal factory (formerly GetLLMProvider)
 func (r *Registry) createProvider(config LLMConfig) (LLMProvider, error) {
 	switch config.Type {
 	case "openai":
 		return NewOpenAIClient(config.APIKey), nil
 	case "anthropic":
-		return nil, fmt.Errorf("anthropic provider not implemented")
+		return NewAnthropicClient(config.APIKey, config.BaseURL), nil
 	case "google", "gemini":
 		return NewGeminiClient(config.APIKey), nil
 	case "deepseek":
@@ -147,6 +198,8 @@ func (r *Registry) GetDefaultModel() string {
 		return "deepseek-chat"
 	case "dashscope":
 		return "qwen-max"
+	case "anthropic":
+		return "claude-sonnet-4-5"
 	default:
 		if r.cfg.LLM.Model != "" {
 			return r.cfg.LLM.Model
@@ -158,6 +211,14 @@ func (r *Registry) GetDefaultModel() string {
 // NewEmbedder creates a new Embedder based on embedding config.
 // Embedder configuration is usually stricter (must match vector DB), so we keep it separate from dynamic LLM registry for now.
 func (r *Registry) NewEmbedder(config EmbeddingConfig) (Embedder, error) {
+	embedder, err := r.newEmbedder(config)
+	if err != nil || !r.limiter.Limits(config.Type) {
+		return embedder, err
+	}
+	return &RateLimitedEmbedder{Embedder: embedder, Name: config.Type, Limiter: r.limiter}, nil
+}
+
+func (r *Registry) newEmbedder(config EmbeddingConfig) (Embedder, error) {
 	// Re-use logic or keep simple.
 	// Embedding usually doesn't need "dynamic switching" per agent,
 	// but the Client implementations are often the same.
@@ -183,19 +244,45 @@ func (r *Registry) NewEmbedder(config EmbeddingConfig) (Embedder, error) {
 
 // GetProviderByModel attempts to resolve a provider based on the model name.
 func (r *Registry) GetProviderByModel(model string) (LLMProvider, error) {
-	providerName := ""
-	model = strings.ToLower(model)
-	if strings.HasPrefix(model, "gpt") {
-		providerName = "openai"
-	} else if strings.HasPrefix(model, "gemini") {
-		providerName = "gemini"
-	} else if strings.HasPrefix(model, "deepseek") {
-		providerName = "deepseek"
-	} else if strings.HasPrefix(model, "qwen") {
-		providerName = "dashscope"
-	} else {
+	providerName := modelFamily(model)
+	if providerName == "" {
 		// Fallback to default
 		return r.GetLLMProvider("default")
 	}
 	return r.GetLLMProvider(providerName)
 }
+
+// ProviderNameForModel returns the name of the provider GetProviderByModel
+// resolves model to.
+func (r *Registry) ProviderNameForModel(model string) string {
+	return r.ProviderName(modelFamily(model))
+}
+
+// modelFamily returns the provider of well-known model families, or "".
+func modelFamily(model string) string {
+	model = strings.ToLower(model)
+	switch {
+	case strings.HasPrefix(model, "gpt"):
+		return "openai"
+	case strings.HasPrefix(model, "gemini"):
+		return "gemini"
+	case strings.HasPrefix(model, "deepseek"):
+		return "deepseek"
+	case strings.HasPrefix(model, "qwen"):
+		return "dashscope"
+	case strings.HasPrefix(model, "claude"):
+		return "anthropic"
+	default:
+		return ""
+	}
+}
+
+// ProviderName resolves "default" or an empty name to the configured
+// provider, as GetLLMProvider does.
+func (r *Registry) ProviderName(providerName string) string {
+	providerName = strings.ToLower(strings.TrimSpace(providerName))
+	if providerName == "" || providerName == "default" {
+		return r.cfg.LLM.Provider
+	}
+	return providerName
+}
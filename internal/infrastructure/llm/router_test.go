@@ -51,6 +51,7 @@ func TestRegistry_GetLLMProvider(t *testing.T) {
 	}{
 		{"Default", "default", false},
 		{"OpenAI Explicit", "openai", false},
+		{"Anthropic", "anthropic", false},
 	}
 
 	for _, tt := range tests {
@@ -66,3 +67,52 @@ func TestRegistry_GetLLMProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistry_WithFallbacks(t *testing.T) {
+	primary := NewMockProvider()
+
+	registry := NewRegistry(&config.Config{LLM: config.LLMConfig{Provider: "deepseek"}})
+	if p := registry.WithFallbacks(primary, "default", nil); p != LLMProvider(primary) {
+		t.Error("Expected the primary provider as is without a chain or retries")
+	}
+
+	registry = NewRegistry(&config.Config{LLM: config.LLMConfig{
+		Provider:   "deepseek",
+		Fallbacks:  []string{"deepseek", "backup:small-model", "unknown"},
+		MaxRetries: 1,
+	}})
+	registry.RegisterProvider("backup", NewMockProvider())
+	chain, ok := registry.WithFallbacks(primary, "default", nil).(*FallbackProvider)
+	if !ok {
+		t.Fatal("Expected a FallbackProvider")
+	}
+	if len(chain.Steps) != 2 || chain.Steps[0].Name != "deepseek" || chain.Steps[1].Name != "backup" ||
+		chain.Steps[1].Model != "small-model" || chain.Retry.MaxRetries != 1 {
+		t.Errorf("Expected the global chain without the primary and unknown providers, got %+v", chain.Steps)
+	}
+
+	chain = registry.WithFallbacks(primary, "deepseek", []FallbackTarget{{Provider: "backup"}}).(*FallbackProvider)
+	if len(chain.Steps) != 2 || chain.Steps[1].Model != "" {
+		t.Errorf("Expected the agent's chain to replace the global one, got %+v", chain.Steps)
+	}
+}
+
+func TestRegistry_ContextWindow(t *testing.T) {
+	registry := NewRegistry(&config.Config{})
+	registry.SetContextWindows(map[string]int{"deepseek-chat": 128000})
+
+	tests := map[string]int{
+		"deepseek-chat":             128000, // From llm_models
+		"deepseek-reasoner":         65536,
+		"gpt-4o-mini":               128000,
+		"gpt-4":                     8192,
+		"Qwen/Qwen2.5-72B-Instruct": 32768,
+		"claude-sonnet-4-5":         200000,
+		"llama3.1:8b":               DefaultContextWindow,
+	}
+	for model, want := range tests {
+		if got := registry.ContextWindow(model); got != want {
+			t.Errorf("ContextWindow(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
@@ -2,9 +2,6 @@ package llm
 
 import (
 	"context"
-	"fmt"
-
-	openai "github.com/sashabaranov/go-openai"
 )
 
 // SiliconFlowClient wraps OpenAIClient to handle SiliconFlow specific logic.
@@ -28,25 +25,14 @@ var _ LLMProvider = (*SiliconFlowClient)(nil)
 var _ Embedder = (*SiliconFlowClient)(nil)
 
 func (c *SiliconFlowClient) Embed(ctx context.Context, model string, text string) ([]float32, error) {
+	return firstEmbedding(c.EmbedBatch(ctx, model, []string{text}))
+}
+
+// EmbedBatch sends up to 32 texts per request, SiliconFlow's input limit.
+func (c *SiliconFlowClient) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float32, error) {
 	if model == "" {
 		// SiliconFlow has popular models like BAAI/bge-m3
 		model = "BAAI/bge-m3"
 	}
-
-	resp, err := c.client.CreateEmbeddings(
-		ctx,
-		openai.EmbeddingRequest{
-			Input: []string{text},
-			Model: openai.EmbeddingModel(model),
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("siliconflow embed error: %w", err)
-	}
-
-	if len(resp.Data) == 0 {
-		return nil, fmt.Errorf("no embeddings returned")
-	}
-
-	return resp.Data[0].Embedding, nil
+	return c.embedBatch(ctx, "siliconflow", model, texts, 32)
 }
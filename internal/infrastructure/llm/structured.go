@@ -0,0 +1,401 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ResponseFormat asks for a reply that is a single JSON value matching
+// Schema. Providers with a native JSON mode use it (OpenAI structured
+// outputs, Gemini response schemas, the JSON object mode of DashScope and
+// DeepSeek); the others are instructed through the prompt. Either way the
+// reply should be checked with DecodeStructured, or requested through
+// GenerateStructured, which also repairs invalid replies.
+type ResponseFormat struct {
+	Name   string                 `json:"name,omitempty"`   // Identifier of the schema, e.g. "verdict"
+	Schema map[string]interface{} `json:"schema,omitempty"` // JSON Schema; nil accepts any JSON object
+
+	// Normalize, if set, tidies the decoded reply before it is validated,
+	// e.g. lowercasing an enum value, so that harmless variations do not
+	// cost a repair.
+	Normalize func(value interface{}) interface{} `json:"-"`
+}
+
+// StructuredRepairs is how many times an invalid structured reply is sent
+// back to the model with the validation errors before giving up.
+const StructuredRepairs = 1
+
+// SchemaError lists why a reply does not match a ResponseFormat.
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return "output does not match schema: " + strings.Join(e.Problems, "; ")
+}
+
+// name returns the schema name accepted by OpenAI-style APIs.
+func (f *ResponseFormat) name() string {
+	if f.Name == "" {
+		return "response"
+	}
+	return f.Name
+}
+
+// instruction describes the expected reply for providers without a native
+// schema mode. It always mentions JSON, which JSON object modes require.
+func (f *ResponseFormat) instruction() string {
+	if f.Schema == nil {
+		return "Reply with a single JSON object only, without code fences or commentary."
+	}
+	schema, _ := json.MarshalIndent(f.Schema, "", "  ")
+	return "Reply with a single JSON value only, without code fences or commentary, matching this JSON Schema:\n" + string(schema)
+}
+
+// formatMessages returns req.Messages with the response format instruction
+// added to the first system message, or prepended as one.
+func formatMessages(req *CompletionRequest) []Message {
+	if req.ResponseFormat == nil {
+		return req.Messages
+	}
+	instruction := req.ResponseFormat.instruction()
+	messages := make([]Message, 0, len(req.Messages)+1)
+	for i, msg := range req.Messages {
+		if msg.Role == "system" {
+			messages = append(messages, req.Messages[:i]...)
+			msg.Content = strings.TrimSpace(msg.Content + "\n\n" + instruction)
+			messages = append(messages, msg)
+			return append(messages, req.Messages[i+1:]...)
+		}
+	}
+	messages = append(messages, Message{Role: "system", Content: instruction})
+	return append(messages, req.Messages...)
+}
+
+var jsonFenceRe = regexp.MustCompile("(?s)```(?:json)?\\s*(.*?)\\s*```")
+
+// extractJSON returns the JSON value in an LLM reply, tolerating markdown
+// fences and surrounding prose.
+func extractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	if json.Valid([]byte(content)) {
+		return content
+	}
+	if m := jsonFenceRe.FindStringSubmatch(content); len(m) == 2 && json.Valid([]byte(m[1])) {
+		return m[1]
+	}
+	for _, delims := range []string{"{}", "[]"} {
+		start := strings.IndexByte(content, delims[0])
+		end := strings.LastIndexByte(content, delims[1])
+		if start >= 0 && end > start && json.Valid([]byte(content[start:end+1])) {
+			return content[start : end+1]
+		}
+	}
+	return content
+}
+
+// DecodeStructured parses the JSON value in content, validates it against
+// format's schema and stores it in out, which may be a pointer to a struct,
+// a map or an interface{}. Validation failures are a *SchemaError.
+func DecodeStructured(content string, format *ResponseFormat, out interface{}) error {
+	raw := extractJSON(content)
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return &SchemaError{Problems: []string{"reply is not valid JSON: " + err.Error()}}
+	}
+
+	schema := map[string]interface{}{"type": "object"}
+	if format != nil && format.Schema != nil {
+		schema = format.Schema
+	}
+	if format != nil && format.Normalize != nil {
+		value = format.Normalize(value)
+	}
+	if err := ValidateJSON(value, schema); err != nil {
+		return err
+	}
+
+	if p, ok := out.(*interface{}); ok {
+		*p = value
+		return nil
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return &SchemaError{Problems: []string{err.Error()}}
+	}
+	if err := json.Unmarshal(normalized, out); err != nil {
+		return &SchemaError{Problems: []string{err.Error()}}
+	}
+	return nil
+}
+
+// RepairMessage asks the model to correct a reply that failed
+// DecodeStructured. It follows the assistant message with the reply.
+func RepairMessage(err error) Message {
+	return Message{
+		Role: "user",
+		Content: fmt.Sprintf("Your reply was rejected: %v.\nReply again with the corrected JSON only, without code fences or commentary.",
+			err),
+	}
+}
+
+// GenerateStructured calls Generate with req.ResponseFormat and decodes the
+// reply into out. An invalid reply is sent back with the validation errors
+// up to StructuredRepairs times. The returned response has the content of
+// the last reply and the usage of all calls.
+func GenerateStructured(ctx context.Context, p LLMProvider, req *CompletionRequest, out interface{}) (*CompletionResponse, error) {
+	if req.ResponseFormat == nil {
+		return nil, errors.New("structured generation requires a response format")
+	}
+
+	attempt := *req
+	attempt.Messages = append([]Message(nil), req.Messages...)
+	total := &CompletionResponse{}
+	for repairs := 0; ; repairs++ {
+		resp, err := p.Generate(ctx, &attempt)
+		if err != nil {
+			return nil, err
+		}
+		total.Content = resp.Content
+		total.Usage.PromptTokens += resp.Usage.PromptTokens
+		total.Usage.CompletionTokens += resp.Usage.CompletionTokens
+		total.Usage.TotalTokens += resp.Usage.TotalTokens
+
+		err = DecodeStructured(resp.Content, req.ResponseFormat, out)
+		if err == nil {
+			return total, nil
+		}
+		if repairs == StructuredRepairs {
+			return total, err
+		}
+		attempt.Messages = append(attempt.Messages, Message{Role: "assistant", Content: resp.Content}, RepairMessage(err))
+	}
+}
+
+// ValidateJSON checks a decoded JSON value against a JSON Schema. It
+// supports the keywords used to describe LLM replies: type, enum, const,
+// properties, required, additionalProperties, items, anyOf and the
+// length and range bounds. Unknown keywords are ignored.
+func ValidateJSON(value interface{}, schema map[string]interface{}) error {
+	var problems []string
+	validate(value, schema, "$", &problems)
+	if len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+func validate(value interface{}, schema map[string]interface{}, path string, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := stringList(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("expected %s, got %s", strings.Join(types, " or "), typeOf(value))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(value, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s", compactJSON(enum))
+		}
+	} else if enum := stringList(schema["enum"]); len(enum) > 0 {
+		if s, ok := value.(string); !ok || !slices.Contains(enum, s) {
+			fail("must be one of %s", compactJSON(enum))
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(value, c) {
+		fail("must be %s", compactJSON(c))
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, s := range anyOf {
+			if sub, ok := s.(map[string]interface{}); ok {
+				var subProblems []string
+				if validate(value, sub, path, &subProblems); len(subProblems) == 0 {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			fail("does not match any of the allowed schemas")
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, key := range stringList(schema["required"]) {
+			if _, ok := v[key]; !ok {
+				fail("missing required property %q", key)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if sub, ok := props[key].(map[string]interface{}); ok {
+				validate(v[key], sub, path+"."+key, problems)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					fail("unexpected property %q", key)
+				}
+			case map[string]interface{}:
+				validate(v[key], extra, path+"."+key, problems)
+			}
+		}
+
+	case []interface{}:
+		if n, ok := number(schema["minItems"]); ok && float64(len(v)) < n {
+			fail("must have at least %v items", n)
+		}
+		if n, ok := number(schema["maxItems"]); ok && float64(len(v)) > n {
+			fail("must have at most %v items", n)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validate(item, items, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+
+	case string:
+		length := float64(len([]rune(v)))
+		if n, ok := number(schema["minLength"]); ok && length < n {
+			fail("must be at least %v characters", n)
+		}
+		if n, ok := number(schema["maxLength"]); ok && length > n {
+			fail("must be at most %v characters", n)
+		}
+
+	case float64:
+		if n, ok := number(schema["minimum"]); ok && v < n {
+			fail("must be >= %v", n)
+		}
+		if n, ok := number(schema["maximum"]); ok && v > n {
+			fail("must be <= %v", n)
+		}
+		if n, ok := number(schema["exclusiveMinimum"]); ok && v <= n {
+			fail("must be > %v", n)
+		}
+		if n, ok := number(schema["exclusiveMaximum"]); ok && v >= n {
+			fail("must be < %v", n)
+		}
+	}
+}
+
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// stringList reads a keyword that is a string or a list of strings, as
+// decoded from JSON ([]interface{}) or written in Go ([]string).
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var out []string
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// jsonEqual compares a decoded value with a schema literal, which may use
+// Go types such as int.
+func jsonEqual(a, b interface{}) bool {
+	return compactJSON(a) == compactJSON(b)
+}
+
+func compactJSON(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,178 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var verdictFormat = &ResponseFormat{Name: "verdict", Schema: map[string]interface{}{
+	"type":     "object",
+	"required": []string{"verdict", "confidence"},
+	"properties": map[string]interface{}{
+		"verdict":    map[string]interface{}{"type": "string", "enum": []string{"supported", "refuted"}},
+		"confidence": map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+		"citations":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+	},
+	"additionalProperties": false,
+}}
+
+func TestValidateJSON(t *testing.T) {
+	var schema map[string]interface{}
+	_ = json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["score", "issues"],
+		"properties": {
+			"score": {"type": "integer", "minimum": 0, "maximum": 100},
+			"issues": {"type": "array", "maxItems": 2, "items": {"type": "string", "minLength": 1}}
+		}
+	}`), &schema)
+
+	tests := []struct {
+		value    string
+		problems []string
+	}{
+		{`{"score": 90, "issues": [], "extra": true}`, nil},
+		{`{"score": 90.5, "issues": ["", "b", "c"]}`, []string{
+			"$.issues: must have at most 2 items",
+			"$.issues[0]: must be at least 1 characters",
+			"$.score: expected integer, got number",
+		}},
+		{`{"score": 120}`, []string{`$: missing required property "issues"`, "$.score: must be <= 100"}},
+		{`["score"]`, []string{"$: expected object, got array"}},
+	}
+	for _, tt := range tests {
+		var value interface{}
+		_ = json.Unmarshal([]byte(tt.value), &value)
+		err := ValidateJSON(value, schema)
+		var schemaErr *SchemaError
+		if tt.problems == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.value, err)
+			}
+		} else if !errors.As(err, &schemaErr) || fmt.Sprint(schemaErr.Problems) != fmt.Sprint(tt.problems) {
+			t.Errorf("%s: expected %v, got %v", tt.value, tt.problems, err)
+		}
+	}
+}
+
+func TestDecodeStructured(t *testing.T) {
+	var verdict struct {
+		Verdict    string   `json:"verdict"`
+		Confidence float64  `json:"confidence"`
+		Citations  []string `json:"citations"`
+	}
+	reply := "Here is my verdict:\n```json\n{\"verdict\": \"refuted\", \"confidence\": 0.8, \"citations\": [\"https://go.dev\"]}\n```"
+	if err := DecodeStructured(reply, verdictFormat, &verdict); err != nil {
+		t.Fatalf("DecodeStructured: %v", err)
+	}
+	if verdict.Verdict != "refuted" || verdict.Confidence != 0.8 || len(verdict.Citations) != 1 {
+		t.Errorf("Unexpected verdict %+v", verdict)
+	}
+
+	var value interface{}
+	err := DecodeStructured(`{"verdict": "maybe", "confidence": 0.5, "note": "?"}`, verdictFormat, &value)
+	if err == nil || !strings.Contains(err.Error(), `$.verdict: must be one of ["supported","refuted"]`) ||
+		!strings.Contains(err.Error(), `unexpected property "note"`) {
+		t.Errorf("Expected the enum and additional property to be rejected, got %v", err)
+	}
+	if err := DecodeStructured("I cannot answer that.", nil, &value); err == nil {
+		t.Error("Expected prose to be rejected")
+	}
+}
+
+func TestGenerateStructured_Repairs(t *testing.T) {
+	provider := NewMockProvider()
+	provider.GenerateResponseQueue = []*CompletionResponse{
+		{Content: `{"verdict": "true", "confidence": 2}`, Usage: Usage{PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110}},
+		{Content: `{"verdict": "supported", "confidence": 0.9}`, Usage: Usage{PromptTokens: 150, CompletionTokens: 10, TotalTokens: 160}},
+	}
+
+	var out map[string]interface{}
+	resp, err := GenerateStructured(context.Background(), provider, &CompletionRequest{
+		Messages:       []Message{{Role: "user", Content: "Judge the claim."}},
+		ResponseFormat: verdictFormat,
+	}, &out)
+	if err != nil {
+		t.Fatalf("GenerateStructured: %v", err)
+	}
+	if out["verdict"] != "supported" || provider.GenerateCalls != 2 || resp.Usage.TotalTokens != 270 {
+		t.Errorf("Expected the repaired verdict with the usage of both calls, got %v after %d calls (%+v)", out, provider.GenerateCalls, resp.Usage)
+	}
+
+	provider.GenerateResponseQueue = []*CompletionResponse{{Content: "no"}, {Content: "still no"}}
+	_, err = GenerateStructured(context.Background(), provider, &CompletionRequest{ResponseFormat: verdictFormat}, &out)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || provider.GenerateCalls != 4 {
+		t.Errorf("Expected a schema error after one repair, got %v", err)
+	}
+}
+
+func TestResponseFormat_Providers(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if strings.HasSuffix(r.URL.Path, "/messages") {
+			_, _ = fmt.Fprint(w, `{"content": [{"type": "text", "text": "{}"}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "{}"}}]}`)
+	}))
+	defer server.Close()
+
+	req := &CompletionRequest{
+		Model:          "model",
+		Messages:       []Message{{Role: "user", Content: "Judge the claim."}},
+		ResponseFormat: verdictFormat,
+	}
+	system := func(messages interface{}) string {
+		first, _ := messages.([]interface{})[0].(map[string]interface{})
+		if first["role"] != "system" {
+			return ""
+		}
+		return fmt.Sprint(first["content"])
+	}
+
+	openaiClient := NewOpenAICompatibleClient("sk", server.URL)
+	openaiClient.jsonMode = jsonModeSchema
+	if _, err := openaiClient.Generate(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	format, _ := got["response_format"].(map[string]interface{})
+	jsonSchema, _ := format["json_schema"].(map[string]interface{})
+	if format["type"] != "json_schema" || jsonSchema["name"] != "verdict" || jsonSchema["schema"] == nil || system(got["messages"]) != "" {
+		t.Errorf("Expected a native json_schema response format, got %v", got)
+	}
+
+	openaiClient.jsonMode = jsonModeObject
+	if _, err := openaiClient.Generate(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	format, _ = got["response_format"].(map[string]interface{})
+	if format["type"] != "json_object" || !strings.Contains(system(got["messages"]), `"enum"`) {
+		t.Errorf("Expected json_object mode with the schema in the prompt, got %v", got)
+	}
+
+	openaiClient.jsonMode = jsonModePrompt
+	if _, err := openaiClient.Generate(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if got["response_format"] != nil || !strings.Contains(system(got["messages"]), "JSON Schema") {
+		t.Errorf("Expected the schema in the prompt only, got %v", got)
+	}
+
+	anthropicReq := *req
+	anthropicReq.Messages = append([]Message{{Role: "system", Content: "You are the adjudicator."}}, req.Messages...)
+	if _, err := NewAnthropicClient("sk-ant", server.URL).Generate(context.Background(), &anthropicReq); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := got["system"].(string); !strings.HasPrefix(s, "You are the adjudicator.\n\nReply with a single JSON value") {
+		t.Errorf("Expected the schema appended to the system prompt, got %q", got["system"])
+	}
+}
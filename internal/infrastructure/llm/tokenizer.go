@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// fallbackEncoding counts tokens of models tiktoken does not know (Gemini,
+// DeepSeek, Qwen, ...). Their tokenizers differ, but cl100k_base is within a
+// few percent for English and Chinese prose.
+const fallbackEncoding = "cl100k_base"
+
+var (
+	tokenizerOnce sync.Once
+	encodingsMu   sync.Mutex
+	encodings     = make(map[string]*tiktoken.Tiktoken)
+)
+
+// CountTokens returns the number of tokens text takes in the model's prompt.
+// Encodings are embedded, so counting never touches the network.
+func CountTokens(model, text string) int {
+	if text == "" {
+		return 0
+	}
+	enc := encodingFor(model)
+	if enc == nil {
+		return len(text) / 4 // Rough rule of thumb if no encoding could be loaded
+	}
+	return len(enc.Encode(text, nil, nil))
+}
+
+func encodingFor(model string) *tiktoken.Tiktoken {
+	tokenizerOnce.Do(func() {
+		tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+	})
+
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	if enc, ok := encodings[model]; ok {
+		return enc
+	}
+	enc, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		enc, err = tiktoken.GetEncoding(fallbackEncoding)
+	}
+	if err != nil {
+		return nil
+	}
+	encodings[model] = enc
+	return enc
+}
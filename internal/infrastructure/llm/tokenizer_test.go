@@ -0,0 +1,21 @@
+package llm
+
+import "testing"
+
+func TestCountTokens(t *testing.T) {
+	// "hello world" is two tokens in cl100k_base and o200k_base
+	if n := CountTokens("gpt-4", "hello world"); n != 2 {
+		t.Errorf("Expected 2 tokens for gpt-4, got %d", n)
+	}
+	if n := CountTokens("gpt-4o", "hello world"); n != 2 {
+		t.Errorf("Expected 2 tokens for gpt-4o, got %d", n)
+	}
+
+	// Unknown models fall back to cl100k_base
+	if n := CountTokens("deepseek-chat", "hello world"); n != 2 {
+		t.Errorf("Expected 2 tokens with the fallback encoding, got %d", n)
+	}
+	if n := CountTokens("gpt-4", ""); n != 0 {
+		t.Errorf("Expected 0 tokens for empty text, got %d", n)
+	}
+}
@@ -0,0 +1,209 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/tenant"
+)
+
+// AuthMockRepository implements auth.Repository for testing.
+type AuthMockRepository struct {
+	mu      sync.Mutex
+	Users   map[uuid.UUID]*auth.User
+	Keys    map[string]*auth.APIKey // By hash
+	Members map[uuid.UUID]map[uuid.UUID]auth.Role
+	Audit   []*auth.AuditEvent
+	Err     error
+}
+
+func NewAuthMockRepository() *AuthMockRepository {
+	return &AuthMockRepository{
+		Users:   make(map[uuid.UUID]*auth.User),
+		Keys:    make(map[string]*auth.APIKey),
+		Members: make(map[uuid.UUID]map[uuid.UUID]auth.Role),
+	}
+}
+
+func (m *AuthMockRepository) CreateUser(ctx context.Context, u *auth.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		u.TenantID = tenantID
+	}
+	m.Users[u.ID] = u
+	return nil
+}
+
+func (m *AuthMockRepository) GetUser(ctx context.Context, id uuid.UUID) (*auth.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	u, ok := m.Users[id]
+	if !ok {
+		return nil, auth.ErrNotFound
+	}
+	return u, nil
+}
+
+func (m *AuthMockRepository) GetUserByUsername(ctx context.Context, username string) (*auth.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	for _, u := range m.Users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, auth.ErrNotFound
+}
+
+func (m *AuthMockRepository) CreateAPIKey(ctx context.Context, k *auth.APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	m.Keys[k.KeyHash] = k
+	return nil
+}
+
+func (m *AuthMockRepository) GetUserByAPIKey(ctx context.Context, keyHash string) (*auth.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	k, ok := m.Keys[keyHash]
+	if !ok {
+		return nil, auth.ErrNotFound
+	}
+	u, ok := m.Users[k.UserID]
+	if !ok {
+		return nil, auth.ErrNotFound
+	}
+	return u, nil
+}
+
+func (m *AuthMockRepository) SetMembership(ctx context.Context, mb *auth.Membership) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Members[mb.GroupID] == nil {
+		m.Members[mb.GroupID] = make(map[uuid.UUID]auth.Role)
+	}
+	m.Members[mb.GroupID][mb.UserID] = mb.Role
+	return nil
+}
+
+func (m *AuthMockRepository) RemoveMembership(ctx context.Context, groupID, userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	if _, ok := m.Members[groupID][userID]; !ok {
+		return auth.ErrNotFound
+	}
+	delete(m.Members[groupID], userID)
+	return nil
+}
+
+func (m *AuthMockRepository) GetRole(ctx context.Context, groupID, userID uuid.UUID) (auth.Role, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return "", m.Err
+	}
+	role, ok := m.Members[groupID][userID]
+	if !ok {
+		return "", auth.ErrNotFound
+	}
+	return role, nil
+}
+
+func (m *AuthMockRepository) ListMemberships(ctx context.Context, userID uuid.UUID) ([]*auth.Membership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var list []*auth.Membership
+	for groupID, members := range m.Members {
+		if role, ok := members[userID]; ok {
+			list = append(list, &auth.Membership{GroupID: groupID, UserID: userID, Role: role})
+		}
+	}
+	return list, nil
+}
+
+func (m *AuthMockRepository) ListMembers(ctx context.Context, groupID uuid.UUID) ([]*auth.Membership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var list []*auth.Membership
+	for userID, role := range m.Members[groupID] {
+		mb := &auth.Membership{GroupID: groupID, UserID: userID, Role: role}
+		if u, ok := m.Users[userID]; ok {
+			mb.Username = u.Username
+		}
+		list = append(list, mb)
+	}
+	return list, nil
+}
+
+func (m *AuthMockRepository) RecordAudit(ctx context.Context, e *auth.AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	e.ID = int64(len(m.Audit) + 1)
+	m.Audit = append(m.Audit, e)
+	return nil
+}
+
+func (m *AuthMockRepository) ListAudit(ctx context.Context, sessionID uuid.UUID) ([]*auth.AuditEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var list []*auth.AuditEvent
+	for _, e := range m.Audit {
+		if e.SessionID != nil && *e.SessionID == sessionID {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+// AuditActions returns the recorded actions in order.
+func (m *AuthMockRepository) AuditActions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	actions := make([]string, len(m.Audit))
+	for i, e := range m.Audit {
+		actions[i] = e.Action
+	}
+	return actions
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hrygo/council/internal/core/workflow"
+)
+
+type CostLedgerMock struct {
+	Entries []*workflow.LedgerEntry
+	Rows    []*workflow.CostReportRow // Returned by Report
+
+	// Captured arguments of the last List or Report call
+	Filter     workflow.CostFilter
+	Dimensions []string
+
+	Err error
+	mu  sync.Mutex
+}
+
+func NewCostLedgerMock() *CostLedgerMock {
+	return &CostLedgerMock{}
+}
+
+func (m *CostLedgerMock) Record(ctx context.Context, entry *workflow.LedgerEntry) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *entry
+	m.Entries = append(m.Entries, &copied)
+	return nil
+}
+
+func (m *CostLedgerMock) List(ctx context.Context, filter workflow.CostFilter) ([]*workflow.LedgerEntry, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Filter = filter
+	return m.Entries, nil
+}
+
+func (m *CostLedgerMock) Report(ctx context.Context, filter workflow.CostFilter, dimensions []string) ([]*workflow.CostReportRow, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Filter = filter
+	m.Dimensions = dimensions
+	return m.Rows, nil
+}
+
+// Recorded returns a snapshot of the recorded entries.
+func (m *CostLedgerMock) Recorded() []*workflow.LedgerEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*workflow.LedgerEntry(nil), m.Entries...)
+}
@@ -2,13 +2,21 @@ package mocks
 
 import (
 	"context"
+	"sync"
 
 	"github.com/hrygo/council/internal/core/workflow"
 )
 
 type SessionMockRepository struct {
 	CapturedSessions []*workflow.Session
+	Recoverable      []*workflow.SessionEntity
+	NodeOutputs      map[string]map[string]interface{}
+	Executions       []*workflow.NodeExecution
+	Budgets          map[string]workflow.Budgets
+	GroupSpend       workflow.Usage
+	Status           workflow.SessionStatus // Returned by Get; running if unset
 	Err              error
+	mu               sync.Mutex
 }
 
 func NewSessionMockRepository() *SessionMockRepository {
@@ -27,11 +35,15 @@ func (m *SessionMockRepository) Get(ctx context.Context, id string) (*workflow.S
 	if m.Err != nil {
 		return nil, m.Err
 	}
+	status := m.Status
+	if status == "" {
+		status = workflow.SessionRunning
+	}
 	// Return a dummy entity with a default group
 	return &workflow.SessionEntity{
 		ID:      id,
 		GroupID: "test-group",
-		Status:  workflow.SessionRunning,
+		Status:  status,
 	}, nil
 }
 
@@ -43,6 +55,30 @@ func (m *SessionMockRepository) UpdateNodeStatus(ctx context.Context, sessionID
 	return m.Err
 }
 
+func (m *SessionMockRepository) UpdateNodeOutput(ctx context.Context, sessionID string, nodeID string, output map[string]interface{}) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.NodeOutputs == nil {
+		m.NodeOutputs = make(map[string]map[string]interface{})
+	}
+	m.NodeOutputs[nodeID] = output
+	return nil
+}
+
+func (m *SessionMockRepository) UpdateContextData(ctx context.Context, sessionID string, data map[string]interface{}) error {
+	return m.Err
+}
+
+func (m *SessionMockRepository) ListRecoverable(ctx context.Context) ([]*workflow.SessionEntity, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Recoverable, nil
+}
+
 type MockSessionFileRepository struct {
 	Files    map[string]*workflow.FileEntity
 	Versions map[string][]*workflow.FileEntity
@@ -100,3 +136,48 @@ func (m *MockSessionFileRepository) ListVersions(ctx context.Context, sessionID,
 	key := sessionID + ":" + path
 	return m.Versions[key], nil
 }
+
+func (m *SessionMockRepository) AppendExecution(ctx context.Context, exec *workflow.NodeExecution) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Executions = append(m.Executions, exec)
+	return nil
+}
+
+func (m *SessionMockRepository) ListExecutions(ctx context.Context, sessionID string) ([]*workflow.NodeExecution, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []*workflow.NodeExecution
+	for _, exec := range m.Executions {
+		if exec.SessionID == sessionID {
+			res = append(res, exec)
+		}
+	}
+	return res, nil
+}
+
+func (m *SessionMockRepository) UpdateBudgets(ctx context.Context, sessionID string, budgets workflow.Budgets) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Budgets == nil {
+		m.Budgets = make(map[string]workflow.Budgets)
+	}
+	m.Budgets[sessionID] = budgets
+	return nil
+}
+
+func (m *SessionMockRepository) GroupUsage(ctx context.Context, groupID string) (workflow.Usage, error) {
+	if m.Err != nil {
+		return workflow.Usage{}, m.Err
+	}
+	return m.GroupSpend, nil
+}
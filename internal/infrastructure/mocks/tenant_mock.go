@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/tenant"
+)
+
+// TenantMockRepository implements tenant.Repository for testing.
+type TenantMockRepository struct {
+	Tenants []*tenant.Tenant
+	Err     error
+}
+
+func NewTenantMockRepository() *TenantMockRepository {
+	return &TenantMockRepository{}
+}
+
+func (m *TenantMockRepository) Create(ctx context.Context, t *tenant.Tenant) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	t.CreatedAt = time.Now()
+	m.Tenants = append(m.Tenants, t)
+	return nil
+}
+
+func (m *TenantMockRepository) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Tenants, nil
+}
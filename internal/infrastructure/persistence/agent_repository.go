@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hrygo/council/internal/core/agent"
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/infrastructure/db"
 )
 
@@ -21,12 +22,16 @@ func NewAgentRepository(pool db.DB) *AgentRepository {
 }
 
 func (r *AgentRepository) Create(ctx context.Context, a *agent.Agent) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
-		INSERT INTO agents (name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		INSERT INTO agents (name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at, tenant_uuid)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8)
 		RETURNING agent_uuid, created_at, updated_at
 	`
-	err := r.pool.QueryRow(ctx, query,
+	err = r.pool.QueryRow(ctx, query,
 		a.Name,
 		a.Avatar,
 		a.Description,
@@ -34,15 +39,20 @@ func (r *AgentRepository) Create(ctx context.Context, a *agent.Agent) error {
 		a.ModelConfig,
 		a.Capabilities,
 		time.Now(),
+		tenantID,
 	).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt)
 
 	return err
 }
 
 func (r *AgentRepository) GetByID(ctx context.Context, id uuid.UUID) (*agent.Agent, error) {
-	query := `SELECT agent_uuid, name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at FROM agents WHERE agent_uuid = $1`
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT agent_uuid, name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at FROM agents WHERE agent_uuid = $1 AND tenant_uuid = $2`
 	var a agent.Agent
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err = r.pool.QueryRow(ctx, query, id, tenantID).Scan(
 		&a.ID,
 		&a.Name,
 		&a.Avatar,
@@ -60,8 +70,12 @@ func (r *AgentRepository) GetByID(ctx context.Context, id uuid.UUID) (*agent.Age
 }
 
 func (r *AgentRepository) List(ctx context.Context) ([]*agent.Agent, error) {
-	query := `SELECT agent_uuid, name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at FROM agents ORDER BY created_at DESC`
-	rows, err := r.pool.Query(ctx, query)
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT agent_uuid, name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at FROM agents WHERE tenant_uuid = $1 ORDER BY created_at DESC`
+	rows, err := r.pool.Query(ctx, query, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -89,13 +103,17 @@ func (r *AgentRepository) List(ctx context.Context) ([]*agent.Agent, error) {
 }
 
 func (r *AgentRepository) Update(ctx context.Context, a *agent.Agent) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
 		UPDATE agents
 		SET name = $1, avatar = $2, description = $3, persona_prompt = $4, model_config = $5, capabilities = $6, updated_at = $7
-		WHERE agent_uuid = $8
+		WHERE agent_uuid = $8 AND tenant_uuid = $9
 	`
 	a.UpdatedAt = time.Now()
-	_, err := r.pool.Exec(ctx, query,
+	_, err = r.pool.Exec(ctx, query,
 		a.Name,
 		a.Avatar,
 		a.Description,
@@ -104,12 +122,17 @@ func (r *AgentRepository) Update(ctx context.Context, a *agent.Agent) error {
 		a.Capabilities,
 		a.UpdatedAt,
 		a.ID,
+		tenantID,
 	)
 	return err
 }
 
 func (r *AgentRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM agents WHERE agent_uuid = $1`
-	_, err := r.pool.Exec(ctx, query, id)
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	query := `DELETE FROM agents WHERE agent_uuid = $1 AND tenant_uuid = $2`
+	_, err = r.pool.Exec(ctx, query, id, tenantID)
 	return err
 }
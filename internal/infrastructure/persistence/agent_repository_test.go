@@ -1,7 +1,6 @@
 package persistence
 
 import (
-	"context"
 	"testing"
 	"time"
 
@@ -28,10 +27,10 @@ func TestAgentRepository_GetByID(t *testing.T) {
 		AddRow(id, "Agent 1", strPtr("avatar"), strPtr("desc"), strPtr("persona"), agent.ModelConfig{}, agent.Capabilities{}, time.Now(), time.Now())
 
 	mock.ExpectQuery("SELECT agent_uuid, name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at FROM agents WHERE agent_uuid = \\$1").
-		WithArgs(id).
+		WithArgs(id, testTenant).
 		WillReturnRows(rows)
 
-	a, err := repo.GetByID(context.Background(), id)
+	a, err := repo.GetByID(testCtx, id)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -57,10 +56,10 @@ func TestAgentRepository_Create(t *testing.T) {
 	}
 
 	mock.ExpectQuery("INSERT INTO agents").
-		WithArgs(a.Name, a.Avatar, a.Description, a.PersonaPrompt, a.ModelConfig, a.Capabilities, pgxmock.AnyArg()).
+		WithArgs(a.Name, a.Avatar, a.Description, a.PersonaPrompt, a.ModelConfig, a.Capabilities, pgxmock.AnyArg(), testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"agent_uuid", "created_at", "updated_at"}).AddRow(uuid.New(), time.Now(), time.Now()))
 
-	err = repo.Create(context.Background(), a)
+	err = repo.Create(testCtx, a)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -79,11 +78,12 @@ func TestAgentRepository_List(t *testing.T) {
 
 	repo := NewAgentRepository(mock)
 
-	mock.ExpectQuery("SELECT agent_uuid, name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at FROM agents").
+	mock.ExpectQuery("SELECT agent_uuid, name, avatar, description, persona_prompt, model_config, capabilities, created_at, updated_at FROM agents WHERE tenant_uuid = \\$1").
+		WithArgs(testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"agent_uuid", "name", "avatar", "description", "persona_prompt", "model_config", "capabilities", "created_at", "updated_at"}).
 			AddRow(uuid.New(), "A1", nil, nil, nil, agent.ModelConfig{}, agent.Capabilities{}, time.Now(), time.Now()))
 
-	list, err := repo.List(context.Background())
+	list, err := repo.List(testCtx)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -104,10 +104,10 @@ func TestAgentRepository_Update(t *testing.T) {
 	a := &agent.Agent{ID: id, Name: "Updated Name"}
 
 	mock.ExpectExec("UPDATE agents").
-		WithArgs(a.Name, a.Avatar, a.Description, a.PersonaPrompt, a.ModelConfig, a.Capabilities, pgxmock.AnyArg(), a.ID).
+		WithArgs(a.Name, a.Avatar, a.Description, a.PersonaPrompt, a.ModelConfig, a.Capabilities, pgxmock.AnyArg(), a.ID, testTenant).
 		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
-	err = repo.Update(context.Background(), a)
+	err = repo.Update(testCtx, a)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -124,10 +124,10 @@ func TestAgentRepository_Delete(t *testing.T) {
 	id := uuid.New()
 
 	mock.ExpectExec("DELETE FROM agents").
-		WithArgs(id).
+		WithArgs(id, testTenant).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
-	err = repo.Delete(context.Background(), id)
+	err = repo.Delete(testCtx, id)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
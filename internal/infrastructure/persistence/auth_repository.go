@@ -0,0 +1,232 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/infrastructure/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// AuthRepository implements auth.Repository using Postgres. User lookups
+// identify the caller before its tenant is known, so they are not scoped and
+// return the user's TenantID instead; memberships and audit events are scoped
+// through their group or session.
+type AuthRepository struct {
+	pool db.DB
+}
+
+func NewAuthRepository(pool db.DB) *AuthRepository {
+	return &AuthRepository{pool: pool}
+}
+
+const userColumns = `user_uuid, tenant_uuid, username, display_name, password_hash, is_admin, created_at, updated_at`
+
+func scanUser(row pgx.Row) (*auth.User, error) {
+	var u auth.User
+	err := row.Scan(&u.ID, &u.TenantID, &u.Username, &u.DisplayName, &u.PasswordHash, &u.IsAdmin, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, auth.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &u, nil
+}
+
+func (r *AuthRepository) CreateUser(ctx context.Context, u *auth.User) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO users (username, display_name, password_hash, is_admin, created_at, updated_at, tenant_uuid)
+		VALUES ($1, $2, $3, $4, $5, $5, $6)
+		RETURNING user_uuid, created_at, updated_at
+	`
+	err = r.pool.QueryRow(ctx, query, u.Username, u.DisplayName, u.PasswordHash, u.IsAdmin, time.Now(), tenantID).
+		Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	u.TenantID = tenantID
+	return nil
+}
+
+func (r *AuthRepository) GetUser(ctx context.Context, id uuid.UUID) (*auth.User, error) {
+	return scanUser(r.pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE user_uuid = $1`, id))
+}
+
+func (r *AuthRepository) GetUserByUsername(ctx context.Context, username string) (*auth.User, error) {
+	return scanUser(r.pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE username = $1`, username))
+}
+
+func (r *AuthRepository) CreateAPIKey(ctx context.Context, k *auth.APIKey) error {
+	query := `
+		INSERT INTO api_keys (user_uuid, name, prefix, key_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING key_uuid, created_at
+	`
+	err := r.pool.QueryRow(ctx, query, k.UserID, k.Name, k.Prefix, k.KeyHash, time.Now()).Scan(&k.ID, &k.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthRepository) GetUserByAPIKey(ctx context.Context, keyHash string) (*auth.User, error) {
+	query := `
+		UPDATE api_keys SET last_used_at = NOW()
+		FROM users
+		WHERE api_keys.key_hash = $1 AND users.user_uuid = api_keys.user_uuid
+		RETURNING users.user_uuid, users.tenant_uuid, users.username, users.display_name, users.password_hash,
+			users.is_admin, users.created_at, users.updated_at
+	`
+	return scanUser(r.pool.QueryRow(ctx, query, keyHash))
+}
+
+// ownedGroup is the condition restricting group_members to the groups of
+// the tenant bound to parameter $n.
+func ownedGroup(n int) string {
+	return fmt.Sprintf("group_uuid IN (SELECT group_uuid FROM groups WHERE tenant_uuid = $%d)", n)
+}
+
+// SetMembership returns auth.ErrNotFound unless both the group and the user
+// belong to the tenant.
+func (r *AuthRepository) SetMembership(ctx context.Context, m *auth.Membership) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO group_members (group_uuid, user_uuid, role, created_at)
+		SELECT $1, $2, $3, NOW()
+		WHERE EXISTS (SELECT 1 FROM groups WHERE group_uuid = $1 AND tenant_uuid = $4)
+		  AND EXISTS (SELECT 1 FROM users WHERE user_uuid = $2 AND tenant_uuid = $4)
+		ON CONFLICT (group_uuid, user_uuid) DO UPDATE SET role = EXCLUDED.role
+	`
+	tag, err := r.pool.Exec(ctx, query, m.GroupID, m.UserID, string(m.Role), tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to set membership: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return auth.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AuthRepository) RemoveMembership(ctx context.Context, groupID, userID uuid.UUID) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	tag, err := r.pool.Exec(ctx, `DELETE FROM group_members WHERE group_uuid = $1 AND user_uuid = $2 AND `+ownedGroup(3), groupID, userID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to remove membership: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return auth.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AuthRepository) GetRole(ctx context.Context, groupID, userID uuid.UUID) (auth.Role, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return "", err
+	}
+	var role string
+	err = r.pool.QueryRow(ctx, `SELECT role FROM group_members WHERE group_uuid = $1 AND user_uuid = $2 AND `+ownedGroup(3), groupID, userID, tenantID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", auth.ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get role: %w", err)
+	}
+	return auth.Role(role), nil
+}
+
+const membershipQuery = `
+		SELECT m.group_uuid, m.user_uuid, u.username, m.role, m.created_at
+		FROM group_members m JOIN users u ON u.user_uuid = m.user_uuid`
+
+func (r *AuthRepository) ListMemberships(ctx context.Context, userID uuid.UUID) ([]*auth.Membership, error) {
+	return r.listMemberships(ctx, membershipQuery+` WHERE m.user_uuid = $1 AND m.`+ownedGroup(2)+` ORDER BY m.created_at`, userID)
+}
+
+func (r *AuthRepository) ListMembers(ctx context.Context, groupID uuid.UUID) ([]*auth.Membership, error) {
+	return r.listMemberships(ctx, membershipQuery+` WHERE m.group_uuid = $1 AND m.`+ownedGroup(2)+` ORDER BY m.created_at`, groupID)
+}
+
+func (r *AuthRepository) listMemberships(ctx context.Context, query string, arg uuid.UUID) ([]*auth.Membership, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx, query, arg, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*auth.Membership
+	for rows.Next() {
+		var m auth.Membership
+		var role string
+		if err := rows.Scan(&m.GroupID, &m.UserID, &m.Username, &role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Role = auth.Role(role)
+		members = append(members, &m)
+	}
+	return members, rows.Err()
+}
+
+func (r *AuthRepository) RecordAudit(ctx context.Context, e *auth.AuditEvent) error {
+	query := `
+		INSERT INTO audit_logs (user_uuid, username, action, group_uuid, session_uuid, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING audit_id, created_at
+	`
+	details := e.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	err := r.pool.QueryRow(ctx, query, e.UserID, e.Username, e.Action, e.GroupID, e.SessionID, details, time.Now()).
+		Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+func (r *AuthRepository) ListAudit(ctx context.Context, sessionID uuid.UUID) ([]*auth.AuditEvent, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT audit_id, user_uuid, username, action, group_uuid, session_uuid, COALESCE(details, '{}'::jsonb), created_at
+		FROM audit_logs WHERE session_uuid = $1 AND ` + ownedSession(2) + ` ORDER BY audit_id
+	`
+	rows, err := r.pool.Query(ctx, query, sessionID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*auth.AuditEvent
+	for rows.Next() {
+		var e auth.AuditEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Username, &e.Action, &e.GroupID, &e.SessionID, &e.Details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/auth"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+var _ auth.Repository = (*AuthRepository)(nil)
+
+func TestAuthRepository_GetUserByUsername(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewAuthRepository(mock)
+	id := uuid.New()
+	hash := "hash"
+
+	mock.ExpectQuery("SELECT user_uuid, tenant_uuid, username, display_name, password_hash, is_admin, created_at, updated_at FROM users WHERE username = \\$1").
+		WithArgs("alice").
+		WillReturnRows(pgxmock.NewRows([]string{"user_uuid", "tenant_uuid", "username", "display_name", "password_hash", "is_admin", "created_at", "updated_at"}).
+			AddRow(id, testTenant, "alice", (*string)(nil), &hash, false, time.Now(), time.Now()))
+	mock.ExpectQuery("FROM users WHERE username = \\$1").
+		WithArgs("bob").
+		WillReturnError(pgx.ErrNoRows)
+
+	u, err := repo.GetUserByUsername(testCtx, "alice")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.ID != id || u.TenantID != testTenant || u.Name() != "alice" {
+		t.Errorf("unexpected user %+v", u)
+	}
+
+	if _, err := repo.GetUserByUsername(testCtx, "bob"); err != auth.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuthRepository_Memberships(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewAuthRepository(mock)
+	groupID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectExec("INSERT INTO group_members").
+		WithArgs(groupID, userID, "editor", testTenant).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery("SELECT role FROM group_members").
+		WithArgs(groupID, userID, testTenant).
+		WillReturnRows(pgxmock.NewRows([]string{"role"}).AddRow("editor"))
+	mock.ExpectQuery("SELECT role FROM group_members").
+		WithArgs(groupID, uuid.Nil, testTenant).
+		WillReturnError(pgx.ErrNoRows)
+
+	if err := repo.SetMembership(testCtx, &auth.Membership{GroupID: groupID, UserID: userID, Role: auth.RoleEditor}); err != nil {
+		t.Fatalf("SetMembership: %v", err)
+	}
+	role, err := repo.GetRole(testCtx, groupID, userID)
+	if err != nil || role != auth.RoleEditor {
+		t.Errorf("expected editor, got %q (%v)", role, err)
+	}
+	if _, err := repo.GetRole(testCtx, groupID, uuid.Nil); err != auth.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,165 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/hrygo/council/internal/infrastructure/db"
+)
+
+// costDimensions maps report dimensions to the SQL expression they group by.
+var costDimensions = map[string]string{
+	workflow.CostByGroup:    "COALESCE(group_uuid::text, '')",
+	workflow.CostByWorkflow: "COALESCE(workflow_uuid::text, '')",
+	workflow.CostByAgent:    "COALESCE(agent_uuid, '')",
+	workflow.CostByProvider: "COALESCE(provider, '')",
+	workflow.CostByModel:    "COALESCE(model, '')",
+	workflow.CostByDay:      "to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')",
+	workflow.CostByMonth:    "to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM')",
+}
+
+// CostLedgerRepository implements workflow.CostLedger using Postgres.
+type CostLedgerRepository struct {
+	pool db.DB
+}
+
+func NewCostLedgerRepository(pool db.DB) workflow.CostLedger {
+	return &CostLedgerRepository{pool: pool}
+}
+
+func (r *CostLedgerRepository) Record(ctx context.Context, entry *workflow.LedgerEntry) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	// Inserts nothing (ErrNoRows) unless the session belongs to the tenant
+	query := `
+		INSERT INTO cost_ledger (tenant_uuid, session_uuid, group_uuid, workflow_uuid, node_id, agent_uuid, provider, model,
+		                         prompt_tokens, completion_tokens, cost_usd, latency_ms, created_at)
+		SELECT s.tenant_uuid, s.session_uuid, s.group_uuid, s.workflow_uuid, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		FROM sessions s WHERE s.session_uuid = $1 AND s.tenant_uuid = $11
+		RETURNING entry_id, COALESCE(group_uuid::text, ''), COALESCE(workflow_uuid::text, '')
+	`
+	var agentID interface{}
+	if entry.AgentID != "" {
+		agentID = entry.AgentID
+	}
+	err = r.pool.QueryRow(ctx, query,
+		entry.SessionID, entry.NodeID, agentID, entry.Provider, entry.Model,
+		entry.PromptTokens, entry.CompletionTokens, entry.CostUSD, entry.LatencyMs, entry.CreatedAt, tenantID,
+	).Scan(&entry.ID, &entry.GroupID, &entry.WorkflowID)
+	if err != nil {
+		return fmt.Errorf("failed to record cost: %w", err)
+	}
+	return nil
+}
+
+func (r *CostLedgerRepository) List(ctx context.Context, filter workflow.CostFilter) ([]*workflow.LedgerEntry, error) {
+	where, args, err := costWhere(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT entry_id, COALESCE(session_uuid::text, ''), COALESCE(group_uuid::text, ''), COALESCE(workflow_uuid::text, ''),
+		       node_id, COALESCE(agent_uuid, ''), COALESCE(provider, ''), COALESCE(model, ''),
+		       prompt_tokens, completion_tokens, cost_usd::float8, latency_ms, created_at
+		FROM cost_ledger WHERE ` + where + `
+		ORDER BY created_at DESC, entry_id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cost ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*workflow.LedgerEntry
+	for rows.Next() {
+		var e workflow.LedgerEntry
+		if err := rows.Scan(
+			&e.ID, &e.SessionID, &e.GroupID, &e.WorkflowID,
+			&e.NodeID, &e.AgentID, &e.Provider, &e.Model,
+			&e.PromptTokens, &e.CompletionTokens, &e.CostUSD, &e.LatencyMs, &e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *CostLedgerRepository) Report(ctx context.Context, filter workflow.CostFilter, dimensions []string) ([]*workflow.CostReportRow, error) {
+	where, args, err := costWhere(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var selects, positions []string
+	for i, d := range dimensions {
+		expr, ok := costDimensions[d]
+		if !ok {
+			return nil, fmt.Errorf("unknown cost dimension %q", d)
+		}
+		selects = append(selects, expr)
+		positions = append(positions, fmt.Sprint(i+1))
+	}
+	selects = append(selects, "COUNT(*)", "COALESCE(SUM(prompt_tokens), 0)", "COALESCE(SUM(completion_tokens), 0)", "COALESCE(SUM(cost_usd), 0)::float8")
+
+	query := "SELECT " + strings.Join(selects, ", ") + " FROM cost_ledger WHERE " + where
+	if len(positions) > 0 {
+		query += " GROUP BY " + strings.Join(positions, ", ") + " ORDER BY " + strings.Join(positions, ", ")
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report costs: %w", err)
+	}
+	defer rows.Close()
+
+	var report []*workflow.CostReportRow
+	for rows.Next() {
+		row := &workflow.CostReportRow{Keys: make(map[string]string, len(dimensions))}
+		keys := make([]string, len(dimensions))
+		dest := make([]interface{}, 0, len(dimensions)+4)
+		for i := range keys {
+			dest = append(dest, &keys[i])
+		}
+		dest = append(dest, &row.Calls, &row.PromptTokens, &row.CompletionTokens, &row.CostUSD)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan cost report: %w", err)
+		}
+		for i, d := range dimensions {
+			row.Keys[d] = keys[i]
+		}
+		report = append(report, row)
+	}
+	return report, rows.Err()
+}
+
+// costWhere builds the tenant-scoped condition selecting the entries of filter.
+func costWhere(ctx context.Context, filter workflow.CostFilter) (string, []interface{}, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	conds := []string{"tenant_uuid = $1"}
+	args := []interface{}{tenantID}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	if filter.GroupID != "" {
+		args = append(args, filter.GroupID)
+		conds = append(conds, fmt.Sprintf("group_uuid = $%d", len(args)))
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
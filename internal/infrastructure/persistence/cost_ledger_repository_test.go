@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+var _ workflow.CostLedger = (*CostLedgerRepository)(nil)
+
+func TestCostLedgerRepository_Record(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	now := time.Now()
+	entry := &workflow.LedgerEntry{
+		SessionID: "s1", NodeID: "agent_1", AgentID: "a1", Provider: "openai", Model: "gpt-4o",
+		PromptTokens: 100, CompletionTokens: 20, CostUSD: 0.0012, LatencyMs: 850, CreatedAt: now,
+	}
+	mock.ExpectQuery("(?s)INSERT INTO cost_ledger.*FROM sessions s WHERE s.session_uuid = \\$1 AND s.tenant_uuid = \\$11").
+		WithArgs("s1", "agent_1", "a1", "openai", "gpt-4o", 100, 20, 0.0012, int64(850), now, testTenant).
+		WillReturnRows(pgxmock.NewRows([]string{"entry_id", "group_uuid", "workflow_uuid"}).AddRow(int64(7), "g1", ""))
+
+	if err := NewCostLedgerRepository(mock).Record(testCtx, entry); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if entry.ID != 7 || entry.GroupID != "g1" {
+		t.Errorf("expected the entry to take the session's group, got %+v", entry)
+	}
+
+	if err := NewCostLedgerRepository(mock).Record(context.Background(), entry); err == nil {
+		t.Error("expected recording without a tenant to fail")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCostLedgerRepository_Report(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mock.ExpectQuery("(?s)SELECT to_char\\(created_at AT TIME ZONE 'UTC', 'YYYY-MM'\\), COALESCE\\(group_uuid::text, ''\\), COUNT\\(\\*\\).*"+
+		"WHERE tenant_uuid = \\$1 AND created_at >= \\$2 AND created_at < \\$3 AND group_uuid = \\$4 GROUP BY 1, 2 ORDER BY 1, 2").
+		WithArgs(testTenant, from, to, "g1").
+		WillReturnRows(pgxmock.NewRows([]string{"month", "group", "calls", "prompt", "completion", "cost"}).
+			AddRow("2025-01", "g1", 12, 3000, 1000, 0.42))
+
+	report, err := NewCostLedgerRepository(mock).Report(testCtx, workflow.CostFilter{From: from, To: to, GroupID: "g1"}, []string{"month", "group"})
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(report) != 1 || report[0].Keys["month"] != "2025-01" || report[0].Keys["group"] != "g1" || report[0].Calls != 12 || report[0].CostUSD != 0.42 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	if _, err := NewCostLedgerRepository(mock).Report(testCtx, workflow.CostFilter{}, []string{"group_uuid; DROP TABLE x"}); err == nil {
+		t.Error("expected unknown dimensions to be rejected")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCostLedgerRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("(?s)FROM cost_ledger WHERE tenant_uuid = \\$1.*ORDER BY created_at DESC, entry_id DESC LIMIT \\$2").
+		WithArgs(testTenant, 50).
+		WillReturnRows(pgxmock.NewRows([]string{"entry_id", "session_uuid", "group_uuid", "workflow_uuid", "node_id", "agent_uuid",
+			"provider", "model", "prompt_tokens", "completion_tokens", "cost_usd", "latency_ms", "created_at"}).
+			AddRow(int64(1), "s1", "g1", "w1", "llm_1", "", "openai", "gpt-4o", 10, 5, 0.0001, int64(300), now))
+
+	entries, err := NewCostLedgerRepository(mock).List(testCtx, workflow.CostFilter{Limit: 50})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Model != "gpt-4o" || entries[0].WorkflowID != "w1" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
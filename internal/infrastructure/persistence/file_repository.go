@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/infrastructure/db"
 )
@@ -17,17 +18,23 @@ func NewSessionFileRepository(pool db.DB) workflow.SessionFileRepository {
 }
 
 func (r *SessionFileRepository) AddVersion(ctx context.Context, sessionID, path, content, author, reason string) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// Inserts nothing (ErrNoRows) unless the session belongs to the tenant
 	query := `
 		INSERT INTO session_files (session_uuid, path, version, content, author, reason, created_at)
-		VALUES ($1, $2, (
+		SELECT $1, $2, (
 			SELECT COALESCE(MAX(version), 0) + 1 
 			FROM session_files 
 			WHERE session_uuid = $1 AND path = $2
-		), $3, $4, $5, NOW())
+		), $3, $4, $5, NOW()
+		WHERE EXISTS (SELECT 1 FROM sessions WHERE session_uuid = $1 AND tenant_uuid = $6)
 		RETURNING version
 	`
 	var newVersion int
-	err := r.pool.QueryRow(ctx, query, sessionID, path, content, author, reason).Scan(&newVersion)
+	err = r.pool.QueryRow(ctx, query, sessionID, path, content, author, reason, tenantID).Scan(&newVersion)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add file version: %w", err)
 	}
@@ -35,15 +42,19 @@ func (r *SessionFileRepository) AddVersion(ctx context.Context, sessionID, path,
 }
 
 func (r *SessionFileRepository) GetLatest(ctx context.Context, sessionID, path string) (*workflow.FileEntity, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
 		SELECT file_uuid, session_uuid, path, version, content, author, reason, created_at
 		FROM session_files
-		WHERE session_uuid = $1 AND path = $2
+		WHERE session_uuid = $1 AND path = $2 AND ` + ownedSession(3) + `
 		ORDER BY version DESC
 		LIMIT 1
 	`
 	var f workflow.FileEntity
-	err := r.pool.QueryRow(ctx, query, sessionID, path).Scan(
+	err = r.pool.QueryRow(ctx, query, sessionID, path, tenantID).Scan(
 		&f.ID, &f.SessionID, &f.Path, &f.Version, &f.Content, &f.Author, &f.Reason, &f.CreatedAt,
 	)
 	if err != nil {
@@ -55,13 +66,17 @@ func (r *SessionFileRepository) GetLatest(ctx context.Context, sessionID, path s
 func (r *SessionFileRepository) ListFiles(ctx context.Context, sessionID string) ([]*workflow.FileEntity, error) {
 	// We want the latest version for EACH path in the session.
 	// DISTINCT ON (path) ORDER BY path, version DESC is the Postgres way.
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
 		SELECT DISTINCT ON (path) file_uuid, session_uuid, path, version, content, author, reason, created_at
 		FROM session_files
-		WHERE session_uuid = $1
+		WHERE session_uuid = $1 AND ` + ownedSession(2) + `
 		ORDER BY path, version DESC
 	`
-	rows, err := r.pool.Query(ctx, query, sessionID)
+	rows, err := r.pool.Query(ctx, query, sessionID, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list files: %w", err)
 	}
@@ -79,13 +94,17 @@ func (r *SessionFileRepository) ListFiles(ctx context.Context, sessionID string)
 }
 
 func (r *SessionFileRepository) ListVersions(ctx context.Context, sessionID, path string) ([]*workflow.FileEntity, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
 		SELECT file_uuid, session_uuid, path, version, content, author, reason, created_at
 		FROM session_files
-		WHERE session_uuid = $1 AND path = $2
+		WHERE session_uuid = $1 AND path = $2 AND ` + ownedSession(3) + `
 		ORDER BY version DESC
 	`
-	rows, err := r.pool.Query(ctx, query, sessionID, path)
+	rows, err := r.pool.Query(ctx, query, sessionID, path, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list file versions: %w", err)
 	}
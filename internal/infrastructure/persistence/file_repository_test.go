@@ -1,7 +1,6 @@
 package persistence
 
 import (
-	"context"
 	"testing"
 	"time"
 
@@ -17,7 +16,6 @@ func TestAddVersion(t *testing.T) {
 	defer mock.Close()
 
 	repo := NewSessionFileRepository(mock)
-	ctx := context.Background()
 
 	sessionID := "sess-123"
 	path := "main.go"
@@ -26,10 +24,10 @@ func TestAddVersion(t *testing.T) {
 	reason := "init"
 
 	mock.ExpectQuery("INSERT INTO session_files").
-		WithArgs(sessionID, path, content, author, reason).
+		WithArgs(sessionID, path, content, author, reason, testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(1))
 
-	ver, err := repo.AddVersion(ctx, sessionID, path, content, author, reason)
+	ver, err := repo.AddVersion(testCtx, sessionID, path, content, author, reason)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, ver)
 
@@ -46,18 +44,17 @@ func TestGetLatest(t *testing.T) {
 	defer mock.Close()
 
 	repo := NewSessionFileRepository(mock)
-	ctx := context.Background()
 
 	sessionID := "sess-123"
 	path := "main.go"
 	now := time.Now()
 
 	mock.ExpectQuery("SELECT file_uuid, session_uuid, path, version, content, author, reason, created_at FROM session_files").
-		WithArgs(sessionID, path).
+		WithArgs(sessionID, path, testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"file_uuid", "session_uuid", "path", "version", "content", "author", "reason", "created_at"}).
 			AddRow("uuid-1", sessionID, path, 5, "content", "author", "reason", now))
 
-	f, err := repo.GetLatest(ctx, sessionID, path)
+	f, err := repo.GetLatest(testCtx, sessionID, path)
 	assert.NoError(t, err)
 	assert.Equal(t, 5, f.Version)
 	assert.Equal(t, "content", f.Content)
@@ -75,18 +72,17 @@ func TestListFiles(t *testing.T) {
 	defer mock.Close()
 
 	repo := NewSessionFileRepository(mock)
-	ctx := context.Background()
 
 	sessionID := "sess-123"
 	now := time.Now()
 
 	mock.ExpectQuery("SELECT DISTINCT ON \\(path\\) .* FROM session_files").
-		WithArgs(sessionID).
+		WithArgs(sessionID, testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"file_uuid", "session_uuid", "path", "version", "content", "author", "reason", "created_at"}).
 			AddRow("uuid-1", sessionID, "main.go", 2, "pkg main", "agent", "fix", now).
 			AddRow("uuid-2", sessionID, "README.md", 1, "# Hello", "user", "init", now))
 
-	files, err := repo.ListFiles(ctx, sessionID)
+	files, err := repo.ListFiles(testCtx, sessionID)
 	assert.NoError(t, err)
 	assert.Len(t, files, 2)
 	foundMain := false
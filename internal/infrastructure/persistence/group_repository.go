@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hrygo/council/internal/core/group"
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/infrastructure/db"
 )
 
@@ -21,9 +22,13 @@ func NewGroupRepository(pool db.DB) *GroupRepository {
 }
 
 func (r *GroupRepository) Create(ctx context.Context, g *group.Group) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
-		INSERT INTO groups (name, icon, system_prompt, default_agent_uuids, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $5)
+		INSERT INTO groups (name, icon, system_prompt, default_agent_uuids, created_at, updated_at, tenant_uuid)
+		VALUES ($1, $2, $3, $4, $5, $5, $6)
 		RETURNING group_uuid, created_at, updated_at
 	`
 	// Ensure default_agent_uuids is empty array if nil, but DB default handles it.
@@ -32,21 +37,26 @@ func (r *GroupRepository) Create(ctx context.Context, g *group.Group) error {
 		g.DefaultAgentUUIDs = []uuid.UUID{}
 	}
 
-	err := r.pool.QueryRow(ctx, query,
+	err = r.pool.QueryRow(ctx, query,
 		g.Name,
 		g.Icon,
 		g.SystemPrompt,
 		g.DefaultAgentUUIDs,
 		time.Now(),
+		tenantID,
 	).Scan(&g.ID, &g.CreatedAt, &g.UpdatedAt)
 
 	return err
 }
 
 func (r *GroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*group.Group, error) {
-	query := `SELECT group_uuid, name, icon, system_prompt, default_agent_uuids, created_at, updated_at FROM groups WHERE group_uuid = $1`
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT group_uuid, name, icon, system_prompt, default_agent_uuids, created_at, updated_at FROM groups WHERE group_uuid = $1 AND tenant_uuid = $2`
 	var g group.Group
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err = r.pool.QueryRow(ctx, query, id, tenantID).Scan(
 		&g.ID,
 		&g.Name,
 		&g.Icon,
@@ -62,8 +72,12 @@ func (r *GroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*group.Gro
 }
 
 func (r *GroupRepository) List(ctx context.Context) ([]*group.Group, error) {
-	query := `SELECT group_uuid, name, icon, system_prompt, default_agent_uuids, created_at, updated_at FROM groups ORDER BY created_at DESC`
-	rows, err := r.pool.Query(ctx, query)
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT group_uuid, name, icon, system_prompt, default_agent_uuids, created_at, updated_at FROM groups WHERE tenant_uuid = $1 ORDER BY created_at DESC`
+	rows, err := r.pool.Query(ctx, query, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -89,25 +103,34 @@ func (r *GroupRepository) List(ctx context.Context) ([]*group.Group, error) {
 }
 
 func (r *GroupRepository) Update(ctx context.Context, g *group.Group) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
 		UPDATE groups
 		SET name = $1, icon = $2, system_prompt = $3, default_agent_uuids = $4, updated_at = $5
-		WHERE group_uuid = $6
+		WHERE group_uuid = $6 AND tenant_uuid = $7
 	`
 	g.UpdatedAt = time.Now()
-	_, err := r.pool.Exec(ctx, query,
+	_, err = r.pool.Exec(ctx, query,
 		g.Name,
 		g.Icon,
 		g.SystemPrompt,
 		g.DefaultAgentUUIDs,
 		g.UpdatedAt,
 		g.ID,
+		tenantID,
 	)
 	return err
 }
 
 func (r *GroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM groups WHERE group_uuid = $1`
-	_, err := r.pool.Exec(ctx, query, id)
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	query := `DELETE FROM groups WHERE group_uuid = $1 AND tenant_uuid = $2`
+	_, err = r.pool.Exec(ctx, query, id, tenantID)
 	return err
 }
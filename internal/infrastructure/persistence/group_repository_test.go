@@ -1,7 +1,6 @@
 package persistence
 
 import (
-	"context"
 	"testing"
 	"time"
 
@@ -24,10 +23,10 @@ func TestGroupRepository_GetByID(t *testing.T) {
 		AddRow(id, "Group 1", "icon", "prompt", []uuid.UUID{}, time.Now(), time.Now())
 
 	mock.ExpectQuery("SELECT group_uuid, name, icon, system_prompt, default_agent_uuids, created_at, updated_at FROM groups WHERE group_uuid = \\$1").
-		WithArgs(id).
+		WithArgs(id, testTenant).
 		WillReturnRows(rows)
 
-	g, err := repo.GetByID(context.Background(), id)
+	g, err := repo.GetByID(testCtx, id)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -57,10 +56,10 @@ func TestGroupRepository_Create(t *testing.T) {
 	}
 
 	mock.ExpectQuery("INSERT INTO groups").
-		WithArgs(g.Name, g.Icon, g.SystemPrompt, g.DefaultAgentUUIDs, pgxmock.AnyArg()).
+		WithArgs(g.Name, g.Icon, g.SystemPrompt, g.DefaultAgentUUIDs, pgxmock.AnyArg(), testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"group_uuid", "created_at", "updated_at"}).AddRow(id, time.Now(), time.Now()))
 
-	err = repo.Create(context.Background(), g)
+	err = repo.Create(testCtx, g)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -82,11 +81,12 @@ func TestGroupRepository_List(t *testing.T) {
 
 	repo := NewGroupRepository(mock)
 
-	mock.ExpectQuery("SELECT group_uuid, name, icon, system_prompt, default_agent_uuids, created_at, updated_at FROM groups ORDER BY created_at DESC").
+	mock.ExpectQuery("SELECT group_uuid, name, icon, system_prompt, default_agent_uuids, created_at, updated_at FROM groups WHERE tenant_uuid = \\$1 ORDER BY created_at DESC").
+		WithArgs(testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"group_uuid", "name", "icon", "system_prompt", "default_agent_uuids", "created_at", "updated_at"}).
 			AddRow(uuid.New(), "G1", strPtr("icon"), strPtr("prompt"), []uuid.UUID{}, time.Now(), time.Now()))
 
-	list, err := repo.List(context.Background())
+	list, err := repo.List(testCtx)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -107,10 +107,10 @@ func TestGroupRepository_Update(t *testing.T) {
 	g := &group.Group{ID: id, Name: "Updated Name"}
 
 	mock.ExpectExec("UPDATE groups").
-		WithArgs(g.Name, g.Icon, g.SystemPrompt, g.DefaultAgentUUIDs, pgxmock.AnyArg(), g.ID).
+		WithArgs(g.Name, g.Icon, g.SystemPrompt, g.DefaultAgentUUIDs, pgxmock.AnyArg(), g.ID, testTenant).
 		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
-	err = repo.Update(context.Background(), g)
+	err = repo.Update(testCtx, g)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -127,10 +127,10 @@ func TestGroupRepository_Delete(t *testing.T) {
 	id := uuid.New()
 
 	mock.ExpectExec("DELETE FROM groups").
-		WithArgs(id).
+		WithArgs(id, testTenant).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
-	err = repo.Delete(context.Background(), id)
+	err = repo.Delete(testCtx, id)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
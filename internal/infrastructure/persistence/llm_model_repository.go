@@ -0,0 +1,38 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hrygo/council/internal/infrastructure/db"
+)
+
+// LLMModelRepository reads model metadata from the llm_models table, which
+// is shared by all tenants.
+type LLMModelRepository struct {
+	pool db.DB
+}
+
+func NewLLMModelRepository(pool db.DB) *LLMModelRepository {
+	return &LLMModelRepository{pool: pool}
+}
+
+// ContextWindows returns the context window of every model that has one.
+func (r *LLMModelRepository) ContextWindows(ctx context.Context) (map[string]int, error) {
+	rows, err := r.pool.Query(ctx, `SELECT model_id, context_window FROM llm_models WHERE context_window > 0`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list context windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := make(map[string]int)
+	for rows.Next() {
+		var model string
+		var tokens int
+		if err := rows.Scan(&model, &tokens); err != nil {
+			return nil, fmt.Errorf("failed to scan context window: %w", err)
+		}
+		windows[model] = tokens
+	}
+	return windows, rows.Err()
+}
@@ -0,0 +1,32 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestLLMModelRepository_ContextWindows(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT model_id, context_window FROM llm_models").
+		WillReturnRows(pgxmock.NewRows([]string{"model_id", "context_window"}).
+			AddRow("deepseek-chat", 128000).
+			AddRow("qwen-max", 32768))
+
+	windows, err := NewLLMModelRepository(mock).ContextWindows(context.Background())
+	if err != nil {
+		t.Fatalf("ContextWindows: %v", err)
+	}
+	if len(windows) != 2 || windows["deepseek-chat"] != 128000 || windows["qwen-max"] != 32768 {
+		t.Errorf("unexpected context windows %v", windows)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -2,10 +2,13 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/infrastructure/db"
+	"github.com/jackc/pgx/v5"
 )
 
 type SessionRepository struct {
@@ -17,9 +20,13 @@ func NewSessionRepository(pool db.DB) workflow.SessionRepository {
 }
 
 func (r *SessionRepository) Create(ctx context.Context, session *workflow.Session, groupID string, workflowID string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
-		INSERT INTO sessions (session_uuid, group_uuid, workflow_uuid, status, proposal, node_statuses, started_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		INSERT INTO sessions (session_uuid, group_uuid, workflow_uuid, status, proposal, node_statuses, inputs, graph_definition, budgets, started_at, tenant_uuid)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), $10)
 	`
 	// Handle empty strings - PostgreSQL expects NULL for empty UUID values
 	var grpID interface{} = groupID
@@ -37,50 +44,243 @@ func (r *SessionRepository) Create(ctx context.Context, session *workflow.Sessio
 		nodeStatuses = make(map[string]workflow.NodeStatus)
 	}
 
-	_, err := r.pool.Exec(ctx, query, session.ID, grpID, wfID, string(session.Status), proposal, nodeStatuses)
+	inputs := session.Inputs
+	if inputs == nil {
+		inputs = make(map[string]interface{})
+	}
+
+	_, err = r.pool.Exec(ctx, query, session.ID, grpID, wfID, string(session.Status), proposal, nodeStatuses, inputs, session.Graph, session.Budgets(), tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 	return nil
 }
 
-func (r *SessionRepository) Get(ctx context.Context, id string) (*workflow.SessionEntity, error) {
-	query := `
-		SELECT session_uuid, COALESCE(group_uuid::text, ''), COALESCE(workflow_uuid::text, ''), 
-		       status, proposal, COALESCE(node_statuses, '{}'::jsonb), started_at, ended_at 
-		FROM sessions WHERE session_uuid = $1
-	`
+const sessionColumns = `
+		session_uuid, COALESCE(group_uuid::text, ''), COALESCE(workflow_uuid::text, ''),
+		status, proposal, COALESCE(node_statuses, '{}'::jsonb), COALESCE(node_outputs, '{}'::jsonb),
+		COALESCE(context_data, '{}'::jsonb), COALESCE(inputs, '{}'::jsonb), graph_definition,
+		COALESCE(budgets, '{}'::jsonb), (` + sessionUsage + ` WHERE e.session_uuid = sessions.session_uuid),
+		started_at, ended_at`
+
+// sessionUsage sums the journal rows e into a workflow.Usage document.
+const sessionUsage = `
+		SELECT jsonb_build_object(
+		         'prompt_tokens', COALESCE(SUM(e.prompt_tokens), 0),
+		         'completion_tokens', COALESCE(SUM(e.completion_tokens), 0),
+		         'cost_usd', COALESCE(SUM(e.cost_usd), 0))
+		FROM node_executions e`
+
+func scanSession(row pgx.Row) (*workflow.SessionEntity, error) {
 	var s workflow.SessionEntity
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&s.ID, &s.GroupID, &s.WorkflowID, &s.Status, &s.Proposal, &s.NodeStatuses, &s.StartedAt, &s.EndedAt,
+	err := row.Scan(
+		&s.ID, &s.GroupID, &s.WorkflowID, &s.Status, &s.Proposal, &s.NodeStatuses, &s.NodeOutputs,
+		&s.ContextData, &s.Inputs, &s.Graph, &s.Budgets, &s.Usage, &s.StartedAt, &s.EndedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get session: %w", err)
+		return nil, err
 	}
 	return &s, nil
 }
 
+func (r *SessionRepository) Get(ctx context.Context, id string) (*workflow.SessionEntity, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE session_uuid = $1 AND tenant_uuid = $2`
+	s, err := scanSession(r.pool.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return s, nil
+}
+
+func (r *SessionRepository) ListRecoverable(ctx context.Context) ([]*workflow.SessionEntity, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE status IN ($1, $2) AND tenant_uuid = $3 ORDER BY started_at`
+	rows, err := r.pool.Query(ctx, query, string(workflow.SessionRunning), string(workflow.SessionPaused), tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recoverable sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*workflow.SessionEntity
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
 func (r *SessionRepository) UpdateStatus(ctx context.Context, id string, status workflow.SessionStatus) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `UPDATE sessions SET status = $2, updated_at = NOW()`
 	if status == workflow.SessionCompleted || status == workflow.SessionFailed || status == workflow.SessionCancelled {
 		query += ", ended_at = NOW()"
 	}
-	query += " WHERE session_uuid = $1"
-	_, err := r.pool.Exec(ctx, query, id, string(status))
+	query += " WHERE session_uuid = $1 AND tenant_uuid = $3"
+	_, err = r.pool.Exec(ctx, query, id, string(status), tenantID)
 	return err
 }
 
 func (r *SessionRepository) UpdateNodeStatus(ctx context.Context, sessionID string, nodeID string, status workflow.NodeStatus) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	// Use jsonb || operator to merge/update the status for the specific node
 	query := `
 		UPDATE sessions 
 		SET node_statuses = COALESCE(node_statuses, '{}'::jsonb) || jsonb_build_object($2::text, $3::text),
 		    updated_at = NOW()
-		WHERE session_uuid = $1
+		WHERE session_uuid = $1 AND tenant_uuid = $4
 	`
-	_, err := r.pool.Exec(ctx, query, sessionID, nodeID, string(status))
+	_, err = r.pool.Exec(ctx, query, sessionID, nodeID, string(status), tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to update node status: %w", err)
 	}
 	return nil
 }
+
+func (r *SessionRepository) UpdateNodeOutput(ctx context.Context, sessionID string, nodeID string, output map[string]interface{}) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(output)
+	if err != nil {
+		return fmt.Errorf("failed to marshal node output: %w", err)
+	}
+	query := `
+		UPDATE sessions
+		SET node_outputs = COALESCE(node_outputs, '{}'::jsonb) || jsonb_build_object($2::text, $3::jsonb),
+		    updated_at = NOW()
+		WHERE session_uuid = $1 AND tenant_uuid = $4
+	`
+	if _, err := r.pool.Exec(ctx, query, sessionID, nodeID, string(payload), tenantID); err != nil {
+		return fmt.Errorf("failed to update node output: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) UpdateContextData(ctx context.Context, sessionID string, data map[string]interface{}) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE sessions SET context_data = $2, updated_at = NOW() WHERE session_uuid = $1 AND tenant_uuid = $3`
+	if _, err := r.pool.Exec(ctx, query, sessionID, data, tenantID); err != nil {
+		return fmt.Errorf("failed to update context data: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) AppendExecution(ctx context.Context, exec *workflow.NodeExecution) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	// Inserts nothing (ErrNoRows) unless the session belongs to the tenant
+	query := `
+		INSERT INTO node_executions (session_uuid, node_id, node_type, iteration, status, input, output, error,
+		                             prompt_tokens, completion_tokens, total_tokens, cost_usd, started_at, ended_at, duration_ms)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		WHERE EXISTS (SELECT 1 FROM sessions WHERE session_uuid = $1 AND tenant_uuid = $16)
+		RETURNING execution_id
+	`
+	input, err := json.Marshal(exec.Input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution input: %w", err)
+	}
+	var output interface{}
+	if exec.Output != nil {
+		raw, err := json.Marshal(exec.Output)
+		if err != nil {
+			return fmt.Errorf("failed to marshal execution output: %w", err)
+		}
+		output = string(raw)
+	}
+	var errText interface{}
+	if exec.Error != "" {
+		errText = exec.Error
+	}
+
+	err = r.pool.QueryRow(ctx, query,
+		exec.SessionID, exec.NodeID, string(exec.NodeType), exec.Iteration, string(exec.Status), string(input), output, errText,
+		exec.PromptTokens, exec.CompletionTokens, exec.TotalTokens, exec.CostUSD, exec.StartedAt, exec.EndedAt, exec.DurationMs, tenantID,
+	).Scan(&exec.ID)
+	if err != nil {
+		return fmt.Errorf("failed to append execution: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) ListExecutions(ctx context.Context, sessionID string) ([]*workflow.NodeExecution, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT execution_id, session_uuid::text, node_id, node_type, iteration, status,
+		       COALESCE(input, '{}'::jsonb), output, COALESCE(error, ''),
+		       prompt_tokens, completion_tokens, total_tokens, COALESCE(cost_usd, 0)::float8, started_at, ended_at, duration_ms
+		FROM node_executions WHERE session_uuid = $1 AND ` + ownedSession(2) + `
+		ORDER BY execution_id
+	`
+	rows, err := r.pool.Query(ctx, query, sessionID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+	defer rows.Close()
+
+	var execs []*workflow.NodeExecution
+	for rows.Next() {
+		var e workflow.NodeExecution
+		if err := rows.Scan(
+			&e.ID, &e.SessionID, &e.NodeID, &e.NodeType, &e.Iteration, &e.Status,
+			&e.Input, &e.Output, &e.Error,
+			&e.PromptTokens, &e.CompletionTokens, &e.TotalTokens, &e.CostUSD, &e.StartedAt, &e.EndedAt, &e.DurationMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan execution: %w", err)
+		}
+		execs = append(execs, &e)
+	}
+	return execs, rows.Err()
+}
+
+func (r *SessionRepository) UpdateBudgets(ctx context.Context, sessionID string, budgets workflow.Budgets) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE sessions SET budgets = $2, updated_at = NOW() WHERE session_uuid = $1 AND tenant_uuid = $3`
+	if _, err := r.pool.Exec(ctx, query, sessionID, budgets, tenantID); err != nil {
+		return fmt.Errorf("failed to update budgets: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) GroupUsage(ctx context.Context, groupID string) (workflow.Usage, error) {
+	var usage workflow.Usage
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return usage, err
+	}
+	query := sessionUsage + `
+		JOIN sessions s ON s.session_uuid = e.session_uuid
+		WHERE s.group_uuid = $1 AND s.tenant_uuid = $2`
+	if err := r.pool.QueryRow(ctx, query, groupID, tenantID).Scan(&usage); err != nil {
+		return usage, fmt.Errorf("failed to sum group usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,144 @@
+package persistence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hrygo/council/internal/core/workflow"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestSessionRepository_UpdateNodeOutput(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSessionRepository(mock)
+
+	mock.ExpectExec("UPDATE sessions\\s+SET node_outputs").
+		WithArgs("s1", "agent_1", `{"agent_output":"hello"}`, testTenant).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.UpdateNodeOutput(testCtx, "s1", "agent_1", map[string]interface{}{"agent_output": "hello"})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSessionRepository_ListRecoverable(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSessionRepository(mock)
+	now := time.Now()
+	graph := &workflow.GraphDefinition{ID: "wf-1", StartNodeID: "start"}
+
+	rows := pgxmock.NewRows([]string{
+		"session_uuid", "group_uuid", "workflow_uuid", "status", "proposal", "node_statuses", "node_outputs",
+		"context_data", "inputs", "graph_definition", "budgets", "usage", "started_at", "ended_at",
+	}).AddRow(
+		"s1", "g1", "wf-1", workflow.SessionRunning, map[string]interface{}{},
+		map[string]workflow.NodeStatus{"start": workflow.StatusCompleted},
+		map[string]map[string]interface{}{"start": {"proposal": "x"}},
+		map[string]interface{}{"score_history": []interface{}{80.0}},
+		map[string]interface{}{"proposal": "x"},
+		graph, workflow.Budgets{Session: &workflow.Budget{MaxTokens: 5000}},
+		workflow.Usage{PromptTokens: 900, CompletionTokens: 100, CostUSD: 0.02},
+		&now, (*time.Time)(nil),
+	)
+
+	mock.ExpectQuery("SELECT .* FROM sessions WHERE status IN").
+		WithArgs(string(workflow.SessionRunning), string(workflow.SessionPaused), testTenant).
+		WillReturnRows(rows)
+
+	sessions, err := repo.ListRecoverable(testCtx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(sessions))
+	}
+	if sessions[0].Graph == nil || sessions[0].Graph.StartNodeID != "start" {
+		t.Errorf("expected graph snapshot to be loaded, got %+v", sessions[0].Graph)
+	}
+	if sessions[0].NodeStatuses["start"] != workflow.StatusCompleted {
+		t.Errorf("expected node statuses to be loaded, got %v", sessions[0].NodeStatuses)
+	}
+	if sessions[0].Budgets.Session.MaxTokens != 5000 || sessions[0].Usage.TotalTokens() != 1000 {
+		t.Errorf("expected budgets and usage to be loaded, got %+v / %+v", sessions[0].Budgets, sessions[0].Usage)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSessionRepository_AppendExecution(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSessionRepository(mock)
+	now := time.Now()
+	exec := &workflow.NodeExecution{
+		SessionID:    "s1",
+		NodeID:       "agent_1",
+		NodeType:     workflow.NodeTypeAgent,
+		Iteration:    2,
+		Status:       workflow.StatusFailed,
+		Input:        map[string]interface{}{"proposal": "x"},
+		Error:        "boom",
+		PromptTokens: 12,
+		CostUSD:      0.0001,
+		StartedAt:    now,
+		EndedAt:      now,
+	}
+
+	mock.ExpectQuery("INSERT INTO node_executions").
+		WithArgs("s1", "agent_1", "agent", 2, "failed", `{"proposal":"x"}`, nil, "boom",
+			12, 0, 0, 0.0001, now, now, int64(0), testTenant).
+		WillReturnRows(pgxmock.NewRows([]string{"execution_id"}).AddRow(int64(42)))
+
+	if err := repo.AppendExecution(testCtx, exec); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if exec.ID != 42 {
+		t.Errorf("expected execution id 42, got %d", exec.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSessionRepository_GroupUsage(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("(?s)SUM\\(e.cost_usd\\).*JOIN sessions s.*s.group_uuid = \\$1 AND s.tenant_uuid = \\$2").
+		WithArgs("g1", testTenant).
+		WillReturnRows(pgxmock.NewRows([]string{"usage"}).
+			AddRow(workflow.Usage{PromptTokens: 3000, CompletionTokens: 1000, CostUSD: 0.5}))
+
+	usage, err := NewSessionRepository(mock).GroupUsage(testCtx, "g1")
+	if err != nil || usage.TotalTokens() != 4000 || usage.CostUSD != 0.5 {
+		t.Errorf("unexpected group usage %+v (%v)", usage, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
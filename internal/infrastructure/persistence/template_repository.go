@@ -6,6 +6,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/infrastructure/db"
 	"github.com/jackc/pgx/v5"
@@ -22,7 +23,11 @@ func NewTemplateRepository(pool db.DB) *TemplateRepository {
 // Note: Init method removed as schema is managed by migrations.
 
 func (r *TemplateRepository) List(ctx context.Context) ([]workflow.Template, error) {
-	rows, err := r.pool.Query(ctx, "SELECT template_uuid, name, description, is_system, graph_definition, created_at, updated_at FROM workflow_templates ORDER BY created_at DESC")
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx, "SELECT template_uuid, name, description, is_system, graph_definition, created_at, updated_at FROM workflow_templates WHERE tenant_uuid = $1 ORDER BY created_at DESC", tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -51,24 +56,32 @@ func (r *TemplateRepository) List(ctx context.Context) ([]workflow.Template, err
 }
 
 func (r *TemplateRepository) Create(ctx context.Context, t *workflow.Template) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	graphJSON, err := json.Marshal(t.Graph)
 	if err != nil {
 		return err
 	}
 
 	query := `
-		INSERT INTO workflow_templates (template_uuid, name, description, is_system, graph_definition, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO workflow_templates (template_uuid, name, description, is_system, graph_definition, created_at, updated_at, tenant_uuid)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 	// Category is ignored as it's not in DB
-	_, err = r.pool.Exec(ctx, query, t.ID, t.Name, t.Description, t.IsSystem, graphJSON, time.Now(), time.Now())
+	_, err = r.pool.Exec(ctx, query, t.ID, t.Name, t.Description, t.IsSystem, graphJSON, time.Now(), time.Now(), tenantID)
 	return err
 }
 
 func (r *TemplateRepository) Get(ctx context.Context, id string) (*workflow.Template, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var t workflow.Template
 	var graphBytes []byte
-	err := r.pool.QueryRow(ctx, "SELECT template_uuid, name, description, is_system, graph_definition, created_at, updated_at FROM workflow_templates WHERE template_uuid = $1", id).
+	err = r.pool.QueryRow(ctx, "SELECT template_uuid, name, description, is_system, graph_definition, created_at, updated_at FROM workflow_templates WHERE template_uuid = $1 AND tenant_uuid = $2", id, tenantID).
 		Scan(&t.ID, &t.Name, &t.Description, &t.IsSystem, &graphBytes, &t.CreatedAt, &t.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -90,6 +103,10 @@ func (r *TemplateRepository) Get(ctx context.Context, id string) (*workflow.Temp
 }
 
 func (r *TemplateRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.pool.Exec(ctx, "DELETE FROM workflow_templates WHERE template_uuid = $1", id)
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, "DELETE FROM workflow_templates WHERE template_uuid = $1 AND tenant_uuid = $2", id, tenantID)
 	return err
 }
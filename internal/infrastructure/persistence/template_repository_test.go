@@ -1,7 +1,6 @@
 package persistence
 
 import (
-	"context"
 	"encoding/json"
 	"testing"
 	"time"
@@ -22,11 +21,11 @@ func TestTemplateRepository_Get(t *testing.T) {
 	graphJSON, _ := json.Marshal(workflow.GraphDefinition{})
 
 	mock.ExpectQuery("SELECT template_uuid, name, description, is_system, graph_definition, created_at, updated_at FROM workflow_templates WHERE template_uuid = \\$1").
-		WithArgs(id).
+		WithArgs(id, testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"template_uuid", "name", "description", "is_system", "graph_definition", "created_at", "updated_at"}).
 			AddRow(id, "Template 1", "desc", false, graphJSON, time.Now(), time.Now()))
 
-	t1, err := repo.Get(context.Background(), id)
+	t1, err := repo.Get(testCtx, id)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -48,11 +47,12 @@ func TestTemplateRepository_List(t *testing.T) {
 
 	repo := NewTemplateRepository(mock)
 
-	mock.ExpectQuery("SELECT template_uuid, name, description, is_system, graph_definition, created_at, updated_at FROM workflow_templates ORDER BY created_at DESC").
+	mock.ExpectQuery("SELECT template_uuid, name, description, is_system, graph_definition, created_at, updated_at FROM workflow_templates WHERE tenant_uuid = \\$1 ORDER BY created_at DESC").
+		WithArgs(testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"template_uuid", "name", "description", "is_system", "graph_definition", "created_at", "updated_at"}).
 			AddRow("1", "T1", "", false, []byte("{}"), time.Now(), time.Now()))
 
-	list, err := repo.List(context.Background())
+	list, err := repo.List(testCtx)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -80,10 +80,10 @@ func TestTemplateRepository_Create(t *testing.T) {
 	}
 
 	mock.ExpectExec("INSERT INTO workflow_templates").
-		WithArgs(tpl.ID, tpl.Name, tpl.Description, tpl.IsSystem, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WithArgs(tpl.ID, tpl.Name, tpl.Description, tpl.IsSystem, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), testTenant).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
-	err = repo.Create(context.Background(), tpl)
+	err = repo.Create(testCtx, tpl)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -100,10 +100,10 @@ func TestTemplateRepository_Delete(t *testing.T) {
 	id := "tpl-1"
 
 	mock.ExpectExec("DELETE FROM workflow_templates WHERE template_uuid = \\$1").
-		WithArgs(id).
+		WithArgs(id, testTenant).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
-	err = repo.Delete(context.Background(), id)
+	err = repo.Delete(testCtx, id)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/infrastructure/db"
+)
+
+// Every repository except TenantRepository reads the tenant from the context
+// with tenant.Require and adds it to each statement, so that no query can
+// reach another tenant's rows. Tables without a tenant_uuid column are scoped
+// through their session (session files, node executions, audit logs) or
+// their group (group members).
+
+// ownedSession is the condition restricting a session child table to the
+// sessions of the tenant bound to parameter $n.
+func ownedSession(n int) string {
+	return fmt.Sprintf("session_uuid IN (SELECT session_uuid FROM sessions WHERE tenant_uuid = $%d)", n)
+}
+
+// TenantRepository implements tenant.Repository using Postgres.
+type TenantRepository struct {
+	pool db.DB
+}
+
+func NewTenantRepository(pool db.DB) *TenantRepository {
+	return &TenantRepository{pool: pool}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, t *tenant.Tenant) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	err := r.pool.QueryRow(ctx, `INSERT INTO tenants (tenant_uuid, name) VALUES ($1, $2) RETURNING created_at`, t.ID, t.Name).
+		Scan(&t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+	return nil
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	rows, err := r.pool.Query(ctx, `SELECT tenant_uuid, name, created_at FROM tenants ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []*tenant.Tenant
+	for rows.Next() {
+		var t tenant.Tenant
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, &t)
+	}
+	return tenants, rows.Err()
+}
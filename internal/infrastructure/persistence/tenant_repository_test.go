@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+var _ tenant.Repository = (*TenantRepository)(nil)
+
+// testTenant is the tenant the repository tests act for.
+var (
+	testTenant = uuid.MustParse("7d1c2a6e-52a4-4f0e-9a35-0b7c4e1f2d90")
+	testCtx    = tenant.WithID(context.Background(), testTenant)
+)
+
+func TestTenantRepository_CreateAndList(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewTenantRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO tenants").
+		WithArgs(pgxmock.AnyArg(), "Acme").
+		WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(now))
+	mock.ExpectQuery("SELECT tenant_uuid, name, created_at FROM tenants").
+		WillReturnRows(pgxmock.NewRows([]string{"tenant_uuid", "name", "created_at"}).
+			AddRow(tenant.DefaultID, "Default", now))
+
+	acme := &tenant.Tenant{Name: "Acme"}
+	if err := repo.Create(context.Background(), acme); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if acme.ID == uuid.Nil || !acme.CreatedAt.Equal(now) {
+		t.Errorf("unexpected tenant %+v", acme)
+	}
+
+	list, err := repo.List(context.Background())
+	if err != nil || len(list) != 1 || list[0].ID != tenant.DefaultID {
+		t.Errorf("unexpected tenants %v (%v)", list, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// Scoped repositories fail closed: without a tenant they refuse to query.
+func TestRepositories_RequireTenant(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	ctx := context.Background()
+	id := uuid.New()
+	calls := map[string]error{}
+	_, calls["agents"] = NewAgentRepository(mock).List(ctx)
+	_, calls["groups"] = NewGroupRepository(mock).GetByID(ctx, id)
+	_, calls["workflows"] = NewWorkflowRepository(mock).List(ctx)
+	_, calls["templates"] = NewTemplateRepository(mock).List(ctx)
+	_, calls["sessions"] = NewSessionRepository(mock).ListRecoverable(ctx)
+	_, calls["files"] = NewSessionFileRepository(mock).ListFiles(ctx, id.String())
+	_, calls["members"] = NewAuthRepository(mock).ListMembers(ctx, id)
+
+	for name, err := range calls {
+		if err != tenant.ErrMissing {
+			t.Errorf("%s: expected ErrMissing, got %v", name, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected no queries, got: %s", err)
+	}
+}
+
+func TestSessionFileRepository_ScopedToTenant(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("(?s)FROM session_files.*session_uuid IN \\(SELECT session_uuid FROM sessions WHERE tenant_uuid = \\$2\\)").
+		WithArgs("s1", testTenant).
+		WillReturnRows(pgxmock.NewRows([]string{"file_uuid", "session_uuid", "path", "version", "content", "author", "reason", "created_at"}))
+
+	files, err := NewSessionFileRepository(mock).ListFiles(testCtx, "s1")
+	if err != nil || len(files) != 0 {
+		t.Errorf("expected no files of another tenant's session, got %v (%v)", files, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/core/workflow"
 	"github.com/hrygo/council/internal/infrastructure/db"
 )
@@ -19,9 +20,13 @@ func NewWorkflowRepository(pool db.DB) workflow.Repository {
 }
 
 func (r *WorkflowRepository) Create(ctx context.Context, graph *workflow.GraphDefinition) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
-		INSERT INTO workflows (workflow_uuid, name, graph_definition)
-		VALUES ($1, $2, $3)
+		INSERT INTO workflows (workflow_uuid, name, graph_definition, tenant_uuid)
+		VALUES ($1, $2, $3, $4)
 	`
 	// For MVP, we are not strictly enforcing GroupID yet in the input GraphDefinition
 	// But the schema might require it if not nullable?
@@ -39,7 +44,7 @@ func (r *WorkflowRepository) Create(ctx context.Context, graph *workflow.GraphDe
 		return fmt.Errorf("failed to marshal graph definition: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, graph.ID, graph.Name, graphJSON)
+	_, err = r.pool.Exec(ctx, query, graph.ID, graph.Name, graphJSON, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to create workflow: %w", err)
 	}
@@ -47,11 +52,15 @@ func (r *WorkflowRepository) Create(ctx context.Context, graph *workflow.GraphDe
 }
 
 func (r *WorkflowRepository) Get(ctx context.Context, id string) (*workflow.GraphDefinition, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		SELECT graph_definition FROM workflows WHERE workflow_uuid = $1
+		SELECT graph_definition FROM workflows WHERE workflow_uuid = $1 AND tenant_uuid = $2
 	`
 	var graphJSON []byte
-	err := r.pool.QueryRow(ctx, query, id).Scan(&graphJSON)
+	err = r.pool.QueryRow(ctx, query, id, tenantID).Scan(&graphJSON)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workflow: %w", err)
 	}
@@ -66,17 +75,21 @@ func (r *WorkflowRepository) Get(ctx context.Context, id string) (*workflow.Grap
 }
 
 func (r *WorkflowRepository) Update(ctx context.Context, graph *workflow.GraphDefinition) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
 	query := `
 		UPDATE workflows 
 		SET name = $2, graph_definition = $3, updated_at = NOW()
-		WHERE workflow_uuid = $1
+		WHERE workflow_uuid = $1 AND tenant_uuid = $4
 	`
 	graphJSON, err := json.Marshal(graph)
 	if err != nil {
 		return fmt.Errorf("failed to marshal graph definition: %w", err)
 	}
 
-	cmdTag, err := r.pool.Exec(ctx, query, graph.ID, graph.Name, graphJSON)
+	cmdTag, err := r.pool.Exec(ctx, query, graph.ID, graph.Name, graphJSON, tenantID)
 	if err != nil {
 		return fmt.Errorf("failed to update workflow: %w", err)
 	}
@@ -87,10 +100,14 @@ func (r *WorkflowRepository) Update(ctx context.Context, graph *workflow.GraphDe
 }
 
 func (r *WorkflowRepository) List(ctx context.Context) ([]*workflow.WorkflowEntity, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		SELECT workflow_uuid, name, graph_definition, created_at, updated_at FROM workflows ORDER BY updated_at DESC
+		SELECT workflow_uuid, name, graph_definition, created_at, updated_at FROM workflows WHERE tenant_uuid = $1 ORDER BY updated_at DESC
 	`
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list workflows: %w", err)
 	}
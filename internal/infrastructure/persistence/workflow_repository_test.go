@@ -1,7 +1,6 @@
 package persistence
 
 import (
-	"context"
 	"encoding/json"
 	"testing"
 	"time"
@@ -24,10 +23,10 @@ func TestWorkflowRepository_Get(t *testing.T) {
 	graphJSON, _ := json.Marshal(graph)
 
 	mock.ExpectQuery("SELECT graph_definition FROM workflows WHERE workflow_uuid = \\$1").
-		WithArgs(id).
+		WithArgs(id, testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"graph_definition"}).AddRow(graphJSON))
 
-	g, err := repo.Get(context.Background(), id)
+	g, err := repo.Get(testCtx, id)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -52,10 +51,10 @@ func TestWorkflowRepository_Create(t *testing.T) {
 	graph := &workflow.GraphDefinition{ID: id, Name: "New Workflow"}
 
 	mock.ExpectExec("INSERT INTO workflows").
-		WithArgs(id, graph.Name, pgxmock.AnyArg()).
+		WithArgs(id, graph.Name, pgxmock.AnyArg(), testTenant).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
-	err = repo.Create(context.Background(), graph)
+	err = repo.Create(testCtx, graph)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -77,10 +76,10 @@ func TestWorkflowRepository_Update(t *testing.T) {
 	graph := &workflow.GraphDefinition{ID: id, Name: "Updated Name"}
 
 	mock.ExpectExec("UPDATE workflows").
-		WithArgs(id, graph.Name, pgxmock.AnyArg()).
+		WithArgs(id, graph.Name, pgxmock.AnyArg(), testTenant).
 		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
-	err = repo.Update(context.Background(), graph)
+	err = repo.Update(testCtx, graph)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -95,11 +94,12 @@ func TestWorkflowRepository_List(t *testing.T) {
 
 	repo := NewWorkflowRepository(mock)
 
-	mock.ExpectQuery("SELECT workflow_uuid, name, graph_definition, created_at, updated_at FROM workflows").
+	mock.ExpectQuery("SELECT workflow_uuid, name, graph_definition, created_at, updated_at FROM workflows WHERE tenant_uuid = \\$1").
+		WithArgs(testTenant).
 		WillReturnRows(pgxmock.NewRows([]string{"workflow_uuid", "name", "graph_definition", "created_at", "updated_at"}).
 			AddRow("1", "W1", []byte("{}"), time.Now(), time.Now()))
 
-	list, err := repo.List(context.Background())
+	list, err := repo.List(testCtx)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -0,0 +1,123 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/hrygo/council/internal/infrastructure/db"
+)
+
+// maxSnippetRunes bounds the content returned per corpus hit.
+const maxSnippetRunes = 600
+
+// CorpusClient implements SearchClient with Postgres full-text search over the
+// group's memories and the session's VFS files. It needs no external service.
+//
+// Scope: opts.GroupID, or the group of opts.SessionID, restricts memories;
+// opts.SessionID selects the VFS files (latest version of each path).
+// Without any scope, all memories are searched. Only documents of the
+// context's tenant are ever returned.
+type CorpusClient struct {
+	pool db.DB
+}
+
+// NewCorpusClient creates a corpus search client.
+func NewCorpusClient(pool db.DB) *CorpusClient {
+	return &CorpusClient{pool: pool}
+}
+
+// Search ranks matching documents with ts_rank. Any query term may match.
+func (c *CorpusClient) Search(ctx context.Context, query string, opts SearchOptions) (*SearchResult, error) {
+	result := &SearchResult{Query: query}
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tsQuery := buildTSQuery(query)
+	if tsQuery == "" {
+		return result, nil
+	}
+
+	maxResults := opts.MaxResults
+	if maxResults == 0 {
+		maxResults = 5
+	}
+
+	rows, err := c.pool.Query(ctx, `
+		WITH q AS (SELECT to_tsquery('simple', $1) AS query),
+		scope AS (
+			SELECT COALESCE(
+				NULLIF($2, '')::uuid,
+				(SELECT group_uuid FROM sessions WHERE session_uuid::text = NULLIF($3, '') AND tenant_uuid = $5)
+			) AS group_uuid
+		),
+		docs AS (
+			SELECT 'memory://' || m.memory_uuid::text AS url, 'Memory' AS title, m.content
+			FROM memories m, scope
+			WHERE m.tenant_uuid = $5 AND (scope.group_uuid IS NULL OR m.group_uuid = scope.group_uuid)
+			UNION ALL
+			SELECT * FROM (
+				SELECT DISTINCT ON (f.path) 'vfs://' || f.session_uuid::text || '/' || f.path AS url, f.path AS title, f.content
+				FROM session_files f
+				WHERE f.session_uuid::text = NULLIF($3, '') AND f.session_uuid IN (SELECT session_uuid FROM sessions WHERE tenant_uuid = $5)
+				ORDER BY f.path, f.version DESC
+			) latest_files
+		)
+		SELECT docs.url, docs.title, docs.content, ts_rank(to_tsvector('simple', docs.content), q.query) AS rank
+		FROM docs, q
+		WHERE to_tsvector('simple', docs.content) @@ q.query
+		ORDER BY rank DESC
+		LIMIT $4
+	`, tsQuery, opts.GroupID, opts.SessionID, maxResults, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("corpus search failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item SearchItem
+		var rank float32
+		if err := rows.Scan(&item.URL, &item.Title, &item.Content, &rank); err != nil {
+			return nil, fmt.Errorf("failed to scan corpus result: %w", err)
+		}
+		item.Score = float64(rank)
+		item.Content = truncateRunes(item.Content, maxSnippetRunes)
+		result.Results = append(result.Results, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("corpus search failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// buildTSQuery turns free text into an OR tsquery of its words. Only letters
+// and digits are kept, so the result is always valid to_tsquery syntax.
+// Short ASCII words are dropped as they are mostly stop words.
+func buildTSQuery(text string) string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		ascii := utf8.RuneCountInString(word) == len(word)
+		if (ascii && len(word) < 3) || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return strings.Join(terms, " | ")
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "..."
+}
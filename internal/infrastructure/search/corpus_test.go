@@ -0,0 +1,54 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrygo/council/internal/core/tenant"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestCorpusClient_Search(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("(?s)FROM memories.*FROM session_files.*ts_rank").
+		WithArgs("water | boils | 100c", "", "s1", 3, tenant.DefaultID).
+		WillReturnRows(pgxmock.NewRows([]string{"url", "title", "content", "rank"}).
+			AddRow("vfs://s1/notes.md", "notes.md", "Water boils at 100 degrees.", float32(0.6)).
+			AddRow("memory://m1", "Memory", "Boiling point facts", float32(0.2)))
+
+	client := NewCorpusClient(mock)
+	res, err := client.Search(tenant.WithID(context.Background(), tenant.DefaultID), "Water boils at 100C? Water!", SearchOptions{MaxResults: 3, SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(res.Results) != 2 || res.Results[0].URL != "vfs://s1/notes.md" {
+		t.Errorf("unexpected results: %+v", res.Results)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+
+	if _, err := client.Search(context.Background(), "water", SearchOptions{}); err != tenant.ErrMissing {
+		t.Errorf("expected ErrMissing without a tenant, got %v", err)
+	}
+}
+
+func TestBuildTSQuery(t *testing.T) {
+	cases := map[string]string{
+		"Water boils at 100C":     "water | boils | 100c",
+		"It's a & b | c:*":        "",
+		"GDP 增长 3%":               "gdp | 增长",
+		"repeat Repeat REPEAT ok": "repeat",
+	}
+	for input, want := range cases {
+		if got := buildTSQuery(input); got != want {
+			t.Errorf("buildTSQuery(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// SearXNGClient implements SearchClient against a self-hosted SearXNG instance,
+// or any backend serving the same JSON API (GET /search?q=...&format=json).
+type SearXNGClient struct {
+	BaseURL string
+	APIKey  string // Optional; sent as a Bearer token for instances behind a proxy
+	Client  *http.Client
+}
+
+// searxngResponse is the subset of the SearXNG JSON response we use.
+type searxngResponse struct {
+	Results []struct {
+		Title   string  `json:"title"`
+		URL     string  `json:"url"`
+		Content string  `json:"content"`
+		Score   float64 `json:"score"`
+	} `json:"results"`
+	// Answers are plain strings in older SearXNG versions and objects in newer ones
+	Answers []json.RawMessage `json:"answers"`
+}
+
+// NewSearXNGClient creates a client for the SearXNG instance at SEARXNG_URL.
+func NewSearXNGClient() *SearXNGClient {
+	return &SearXNGClient{
+		BaseURL: strings.TrimRight(os.Getenv("SEARXNG_URL"), "/"),
+		APIKey:  os.Getenv("SEARXNG_API_KEY"),
+		Client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Search performs a search using the SearXNG JSON API.
+func (s *SearXNGClient) Search(ctx context.Context, query string, opts SearchOptions) (*SearchResult, error) {
+	if s.BaseURL == "" {
+		return nil, fmt.Errorf("SEARXNG_URL not set")
+	}
+
+	maxResults := opts.MaxResults
+	if maxResults == 0 {
+		maxResults = 5
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.BaseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("searxng request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("searxng returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var searxResp searxngResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searxResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := &SearchResult{Query: query}
+	for _, raw := range searxResp.Answers {
+		if answer := decodeAnswer(raw); answer != "" {
+			result.Answer = answer
+			break
+		}
+	}
+
+	for _, r := range searxResp.Results {
+		if !matchesDomains(r.URL, opts.Domains) {
+			continue
+		}
+		result.Results = append(result.Results, SearchItem{
+			Title:   r.Title,
+			URL:     r.URL,
+			Content: r.Content,
+			Score:   r.Score,
+		})
+		if len(result.Results) == maxResults {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func decodeAnswer(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var obj struct {
+		Answer string `json:"answer"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return obj.Answer
+	}
+	return ""
+}
+
+// matchesDomains reports whether rawURL belongs to one of domains (or any domain if none).
+func matchesDomains(rawURL string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "www."))
+		if host == d || strings.HasSuffix(host, "."+d) || host == "www."+d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearXNGClient_Search(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			t.Errorf("expected /search, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("format") != "json" || r.URL.Query().Get("q") != "test query" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"answers": [{"answer": "SearXNG answer"}],
+			"results": [
+				{"title": "Result 1", "url": "https://docs.example.com/1", "content": "Body 1", "score": 2.5},
+				{"title": "Result 2", "url": "https://other.org/2", "content": "Body 2", "score": 1.0}
+			]
+		}`))
+	}))
+	defer ts.Close()
+
+	client := &SearXNGClient{BaseURL: ts.URL, Client: ts.Client()}
+
+	res, err := client.Search(context.Background(), "test query", SearchOptions{Domains: []string{"example.com"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Answer != "SearXNG answer" {
+		t.Errorf("expected 'SearXNG answer', got '%s'", res.Answer)
+	}
+	if len(res.Results) != 1 || res.Results[0].URL != "https://docs.example.com/1" {
+		t.Errorf("expected results filtered by domain, got %+v", res.Results)
+	}
+}
+
+func TestSearXNGClient_NoBaseURL(t *testing.T) {
+	client := &SearXNGClient{}
+	if _, err := client.Search(context.Background(), "q", SearchOptions{}); err == nil {
+		t.Error("expected error for missing base URL, got nil")
+	}
+}
@@ -21,6 +21,10 @@ type SearchOptions struct {
 	MaxResults int
 	SearchType string // "search" | "answer"
 	Domains    []string
+
+	// Scope for corpus providers; ignored by web providers
+	GroupID   string
+	SessionID string
 }
 
 // SearchResult holds the response from a search query.
@@ -147,6 +151,8 @@ func NewSearchClient(provider string) (SearchClient, error) {
 	switch provider {
 	case "tavily", "":
 		return NewTavilyClient(), nil
+	case "searxng":
+		return NewSearXNGClient(), nil
 	default:
 		return nil, fmt.Errorf("unknown search provider: %s", provider)
 	}
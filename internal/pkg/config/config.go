@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -9,13 +10,21 @@ type Config struct {
 	Port           string
 	DatabaseURL    string
 	TavilyAPIKey   string
+	SearchProvider string // tavily | searxng | corpus; empty disables search
+	CodeExecution  bool   // Registers the sandboxed run_code tool
 	OpenAIKey      string
 	DeepSeekKey    string
 	DashScopeKey   string
 	GeminiKey      string
 	SiliconFlowKey string
+	AnthropicKey   string
 	RedisURL       string
 
+	AuthEnabled   bool   // Requires a session token or API key on every API route
+	AuthSecret    string // HMAC key signing session tokens
+	AdminUsername string // Administrator created on startup if missing
+	AdminPassword string
+
 	LLM       LLMConfig
 	Embedding EmbeddingConfig
 }
@@ -30,6 +39,15 @@ type LLMConfig struct {
 	APIKey   string // Optional override. Usually defaults to the global key for the chosen provider.
 	BaseURL  string // Used primarily for "ollama" or custom OpenAI-compatible proxies (OneAPI).
 	Model    string // e.g., "deepseek-v3", "gpt-4o"
+
+	// Fallbacks is the default failover chain as "provider[:model]" entries,
+	// e.g. "siliconflow:deepseek-ai/DeepSeek-V3", "ollama:qwen2.5".
+	Fallbacks []string
+	// MaxRetries is the number of retries of transient errors per provider.
+	MaxRetries int
+	// RateLimits are "provider[/model]=rpm:N,tpm:N,concurrency:N" entries,
+	// e.g. "deepseek=rpm:60,concurrency:4".
+	RateLimits []string
 }
 
 // EmbeddingConfig defines the System Wide Embedding configuration.
@@ -64,6 +82,13 @@ func Load() *Config {
 		BaseURL:  os.Getenv("LLM_BASE_URL"),
 		Model:    getEnv("LLM_MODEL", "deepseek-v3"),
 	}
+	if fallbacks := os.Getenv("LLM_FALLBACKS"); fallbacks != "" {
+		cfg.LLM.Fallbacks = strings.Split(fallbacks, ",")
+	}
+	cfg.LLM.MaxRetries, _ = strconv.Atoi(getEnv("LLM_MAX_RETRIES", "2"))
+	if limits := os.Getenv("LLM_RATE_LIMITS"); limits != "" {
+		cfg.LLM.RateLimits = strings.Split(limits, ";")
+	}
 
 	// Embedding Config
 	provider := getEnv("EMBEDDING_PROVIDER", DefaultEmbedding)
@@ -81,6 +106,27 @@ func Load() *Config {
 	cfg.DashScopeKey = os.Getenv("DASHSCOPE_API_KEY")
 	cfg.GeminiKey = os.Getenv("GEMINI_API_KEY")
 	cfg.SiliconFlowKey = os.Getenv("SILICONFLOW_API_KEY")
+	cfg.AnthropicKey = os.Getenv("ANTHROPIC_API_KEY")
+
+	// Search: Tavily stays the default when its key is present
+	defaultSearch := ""
+	if cfg.TavilyAPIKey != "" {
+		defaultSearch = "tavily"
+	}
+	cfg.SearchProvider = getEnv("SEARCH_PROVIDER", defaultSearch)
+
+	// Code execution runs agent snippets on this host, so it is opt-in
+	cfg.CodeExecution = getEnv("CODE_EXECUTION_ENABLED", "false") == "true"
+
+	// Auth: enabled by default once a signing secret is configured
+	cfg.AuthSecret = os.Getenv("AUTH_SECRET")
+	defaultAuth := "false"
+	if cfg.AuthSecret != "" {
+		defaultAuth = "true"
+	}
+	cfg.AuthEnabled = getEnv("AUTH_ENABLED", defaultAuth) == "true"
+	cfg.AdminUsername = getEnv("ADMIN_USERNAME", "admin")
+	cfg.AdminPassword = os.Getenv("ADMIN_PASSWORD")
 
 	return cfg
 }
@@ -43,3 +43,45 @@ func TestLoad_EmbeddingFallbacks(t *testing.T) {
 		}
 	}
 }
+
+func TestLoad_AuthDefaults(t *testing.T) {
+	t.Setenv("AUTH_ENABLED", "")
+	t.Setenv("AUTH_SECRET", "")
+	if Load().AuthEnabled {
+		t.Error("Expected auth to be disabled without a secret")
+	}
+
+	t.Setenv("AUTH_SECRET", "s3cret")
+	if !Load().AuthEnabled {
+		t.Error("Expected auth to be enabled once a secret is set")
+	}
+
+	t.Setenv("AUTH_ENABLED", "false")
+	if Load().AuthEnabled {
+		t.Error("Expected AUTH_ENABLED=false to win over the secret")
+	}
+}
+
+func TestLoad_LLMFallbacks(t *testing.T) {
+	t.Setenv("LLM_FALLBACKS", "")
+	t.Setenv("LLM_MAX_RETRIES", "")
+	cfg := Load()
+	if len(cfg.LLM.Fallbacks) != 0 || cfg.LLM.MaxRetries != 2 {
+		t.Errorf("Expected no fallbacks and 2 retries by default, got %v / %d", cfg.LLM.Fallbacks, cfg.LLM.MaxRetries)
+	}
+
+	t.Setenv("LLM_FALLBACKS", "siliconflow:deepseek-ai/DeepSeek-V3,ollama:qwen2.5")
+	t.Setenv("LLM_MAX_RETRIES", "0")
+	cfg = Load()
+	if len(cfg.LLM.Fallbacks) != 2 || cfg.LLM.Fallbacks[1] != "ollama:qwen2.5" || cfg.LLM.MaxRetries != 0 {
+		t.Errorf("Expected the configured chain without retries, got %v / %d", cfg.LLM.Fallbacks, cfg.LLM.MaxRetries)
+	}
+}
+
+func TestLoad_LLMRateLimits(t *testing.T) {
+	t.Setenv("LLM_RATE_LIMITS", "deepseek=rpm:60,concurrency:4;siliconflow/deepseek-ai/DeepSeek-V3=tpm:100000")
+	cfg := Load()
+	if len(cfg.LLM.RateLimits) != 2 || cfg.LLM.RateLimits[1] != "siliconflow/deepseek-ai/DeepSeek-V3=tpm:100000" {
+		t.Errorf("Expected one entry per limit, got %v", cfg.LLM.RateLimits)
+	}
+}
@@ -15,6 +15,8 @@ type AgentConfig struct {
 	Temperature float64 `yaml:"temperature"`
 	MaxTokens   int     `yaml:"max_tokens"`
 	TopP        float64 `yaml:"top_p"`
+
+	CodeExecution bool `yaml:"code_execution"` // Grants the run_code tool
 }
 
 // AgentPrompt represents a parsed prompt file with config and content.
@@ -10,13 +10,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/hrygo/council/internal/core/tenant"
 	"github.com/hrygo/council/internal/pkg/config"
 )
 
 // SystemNamespace is the UUID namespace for system resources
 var SystemNamespace = uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 
-// Seeder handles database seeding for default data.
+// Seeder handles database seeding for default data. System agents, groups
+// and templates belong to the default tenant.
 type Seeder struct {
 	db  *pgxpool.Pool
 	cfg *config.Config
@@ -62,18 +64,19 @@ func (s *Seeder) SeedAgents(ctx context.Context) error {
 
 		capabilities, _ := json.Marshal(map[string]bool{
 			"web_search":     false,
-			"code_execution": false,
+			"code_execution": prompt.Config.CodeExecution,
 		})
 
 		_, err = s.db.Exec(ctx, `
-			INSERT INTO agents (agent_uuid, name, persona_prompt, model_config, capabilities, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+			INSERT INTO agents (agent_uuid, name, persona_prompt, model_config, capabilities, created_at, updated_at, tenant_uuid)
+			VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), $6)
 			ON CONFLICT (agent_uuid) DO UPDATE SET
 				name = EXCLUDED.name,
 				persona_prompt = EXCLUDED.persona_prompt,
 				model_config = EXCLUDED.model_config,
+				capabilities = EXCLUDED.capabilities,
 				updated_at = NOW()
-		`, agentUUID, prompt.Config.Name, prompt.Content, modelConfig, capabilities)
+		`, agentUUID, prompt.Config.Name, prompt.Content, modelConfig, capabilities, tenant.DefaultID)
 
 		if err != nil {
 			return fmt.Errorf("failed to seed agent %s: %w", agentID, err)
@@ -117,10 +120,10 @@ func (s *Seeder) SeedGroups(ctx context.Context) error {
 	agentIDsJSON, _ := json.Marshal(agentUUIDs)
 
 	_, err := s.db.Exec(ctx, `
-		INSERT INTO groups (group_uuid, name, system_prompt, default_agent_uuids, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		INSERT INTO groups (group_uuid, name, system_prompt, default_agent_uuids, created_at, updated_at, tenant_uuid)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), $5)
 		ON CONFLICT (group_uuid) DO NOTHING
-	`, groupUUID, "The Council", councilSystemPrompt, agentIDsJSON)
+	`, groupUUID, "The Council", councilSystemPrompt, agentIDsJSON, tenant.DefaultID)
 
 	if err != nil {
 		return fmt.Errorf("failed to seed group: %w", err)
@@ -291,15 +294,15 @@ func (s *Seeder) SeedWorkflows(ctx context.Context) error {
 		}
 
 		_, err := s.db.Exec(ctx, `
-			INSERT INTO workflow_templates (template_uuid, name, description, graph_definition, is_system, created_at, updated_at)
-			VALUES ($1, $2, $3, $4::jsonb, true, NOW(), NOW())
+			INSERT INTO workflow_templates (template_uuid, name, description, graph_definition, is_system, created_at, updated_at, tenant_uuid)
+			VALUES ($1, $2, $3, $4::jsonb, true, NOW(), NOW(), $5)
 			ON CONFLICT (template_uuid) DO UPDATE SET
 				name = EXCLUDED.name,
 				description = EXCLUDED.description,
 				graph_definition = EXCLUDED.graph_definition,
 				is_system = EXCLUDED.is_system,
 				updated_at = NOW()
-		`, wfUUID, wf.Name, wf.Description, compactGraph.String())
+		`, wfUUID, wf.Name, wf.Description, compactGraph.String(), tenant.DefaultID)
 
 		if err != nil {
 			return fmt.Errorf("failed to seed workflow %s: %w", wf.ID, err)
@@ -350,43 +353,48 @@ func (s *Seeder) SeedLLMOptions(ctx context.Context) error {
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
 			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 		);
+		ALTER TABLE llm_models ADD COLUMN IF NOT EXISTS context_window INTEGER;
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to ensure LLM tables: %w", err)
 	}
 
 	// 2. Define Data
+	type model struct {
+		ID            string
+		ContextWindow int // Tokens, prompt and reply together
+	}
 	providers := []struct {
 		ID        string
 		Name      string
 		Icon      string
 		SortOrder int
-		Models    []string
+		Models    []model
 	}{
 		{
 			ID: "openai", Name: "OpenAI", Icon: "🟢", SortOrder: 1,
-			Models: []string{"gpt-4o", "o1", "gpt-5-mini", "gpt-4.5-preview"},
+			Models: []model{{"gpt-4o", 128000}, {"o1", 200000}, {"gpt-5-mini", 400000}, {"gpt-4.5-preview", 128000}},
 		},
 		{
 			ID: "google", Name: "Google", Icon: "🔵", SortOrder: 2,
-			Models: []string{"gemini-3-pro", "gemini-3-flash", "gemini-2.0-flash", "gemini-2.0-pro"},
+			Models: []model{{"gemini-3-pro", 1048576}, {"gemini-3-flash", 1048576}, {"gemini-2.0-flash", 1048576}, {"gemini-2.0-pro", 2097152}},
 		},
 		{
 			ID: "deepseek", Name: "DeepSeek", Icon: "🟣", SortOrder: 3,
-			Models: []string{"deepseek-chat", "deepseek-reasoner"}, // verified: deepseek-chat (V3), deepseek-reasoner (R1)
+			Models: []model{{"deepseek-chat", 128000}, {"deepseek-reasoner", 128000}}, // verified: deepseek-chat (V3), deepseek-reasoner (R1)
 		},
 		{
 			ID: "dashscope", Name: "DashScope", Icon: "🟡", SortOrder: 4,
-			Models: []string{"qwen-max", "qwen-plus", "qwen-turbo"},
+			Models: []model{{"qwen-max", 32768}, {"qwen-plus", 131072}, {"qwen-turbo", 1000000}},
 		},
 		{
 			ID: "siliconflow", Name: "SiliconFlow", Icon: "🟠", SortOrder: 5,
-			Models: []string{
-				"zai-org/GLM-4.6", // Verified: SiliconFlow uses repo format
-				"Qwen/Qwen2.5-72B-Instruct",
-				"Qwen/Qwen2.5-Coder-32B-Instruct",
-				"deepseek-ai/DeepSeek-V3",
-				"deepseek-ai/DeepSeek-R1",
+			Models: []model{
+				{"zai-org/GLM-4.6", 131072}, // Verified: SiliconFlow uses repo format
+				{"Qwen/Qwen2.5-72B-Instruct", 32768},
+				{"Qwen/Qwen2.5-Coder-32B-Instruct", 32768},
+				{"deepseek-ai/DeepSeek-V3", 65536},
+				{"deepseek-ai/DeepSeek-R1", 65536},
 			},
 		},
 	}
@@ -408,18 +416,19 @@ func (s *Seeder) SeedLLMOptions(ctx context.Context) error {
 		}
 
 		// Upsert Models
-		for i, mID := range p.Models {
+		for i, m := range p.Models {
 			_, err := s.db.Exec(ctx, `
-				INSERT INTO llm_models (model_id, provider_id, name, is_mainstream, sort_order, updated_at)
-				VALUES ($1, $2, $3, true, $4, NOW())
+				INSERT INTO llm_models (model_id, provider_id, name, is_mainstream, sort_order, context_window, updated_at)
+				VALUES ($1, $2, $3, true, $4, $5, NOW())
 				ON CONFLICT (model_id) DO UPDATE SET
 					name = EXCLUDED.name,
 					is_mainstream = true,
 					sort_order = EXCLUDED.sort_order,
+					context_window = EXCLUDED.context_window,
 					updated_at = NOW()
-			`, mID, p.ID, mID, i+1)
+			`, m.ID, p.ID, m.ID, i+1, m.ContextWindow)
 			if err != nil {
-				return fmt.Errorf("failed to upsert model %s for %s: %w", mID, p.ID, err)
+				return fmt.Errorf("failed to upsert model %s for %s: %w", m.ID, p.ID, err)
 			}
 		}
 	}